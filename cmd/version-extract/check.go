@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+	"github.com/lfreleng-actions/version-extract-action/internal/extractor"
+)
+
+// Check command flags
+var (
+	checkGitTag bool
+	checkGroups []string
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that versions agree across manifests",
+	Long: `Extract the version from every supported manifest and check that they
+agree, optionally with the latest git tag.
+
+By default every manifest with a static version must agree. The
+consistency_groups configuration key, or --group, instead names the sets of
+files that must agree. The command exits non-zero and lists the disagreeing
+files when any group is inconsistent.`,
+	Args:         cobra.NoArgs,
+	RunE:         reportsOwnErrors(runCheck),
+	SilenceUsage: true,
+}
+
+// parseGroupFlag parses a --group value of the form name=glob[,glob...]
+func parseGroupFlag(value string) (config.ConsistencyGroup, error) {
+	name, globs, ok := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.TrimSpace(globs) == "" {
+		return config.ConsistencyGroup{}, fmt.Errorf(
+			"invalid --group %q: expected name=glob[,glob...]", value)
+	}
+
+	group := config.ConsistencyGroup{Name: name}
+	for _, glob := range strings.Split(globs, ",") {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			group.Files = append(group.Files, config.ConsistencyMember{File: glob})
+		}
+	}
+	return group, nil
+}
+
+// runCheck compares versions across manifests
+func runCheck(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfiguration(path)
+	if err != nil {
+		return handleError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	opts := extractor.CheckOptions{Groups: cfg.ConsistencyGroups, GitTag: checkGitTag}
+	if len(checkGroups) > 0 {
+		opts.Groups = nil
+		for _, value := range checkGroups {
+			group, err := parseGroupFlag(value)
+			if err != nil {
+				return handleError(err)
+			}
+			opts.Groups = append(opts.Groups, group)
+		}
+	}
+
+	ext := extractor.NewWithOptions(cfg, dynamicFallback)
+	ext.SetLogger(logger)
+	if err := ext.SetTagPrefix(tagPrefix); err != nil {
+		return handleError(err)
+	}
+	if err := ext.SetTagPolicy(tagPolicy); err != nil {
+		return handleError(err)
+	}
+	ext.SetExcludePrerelease(excludePre)
+	if err := ext.SetGitBackend(gitBackend); err != nil {
+		return handleError(err)
+	}
+	if err := ext.SetScheme(scheme); err != nil {
+		return handleError(err)
+	}
+	if err := ext.SetRevision(revision); err != nil {
+		return handleError(err)
+	}
+	result, err := ext.Check(path, opts)
+	if err != nil {
+		return handleError(fmt.Errorf("version check failed: %w", err))
+	}
+
+	if err := outputCheckResult(result); err != nil {
+		return err
+	}
+	if !result.Consistent {
+		return fmt.Errorf("versions are inconsistent")
+	}
+	return nil
+}
+
+// outputCheckResult prints a check result: JSON, or a summary per group
+// with a table of the files that disagree
+func outputCheckResult(result *extractor.CheckResult) error {
+	if outputFormat == "json" {
+		var data []byte
+		var err error
+		if jsonFormat == "pretty" {
+			data, err = json.MarshalIndent(result, "", "  ")
+		} else {
+			data, err = json.Marshal(result)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, group := range result.Groups {
+		if len(group.Entries) == 0 {
+			fmt.Printf("⚠️  Group %s: no files matched\n", group.Name)
+			continue
+		}
+		if group.Consistent {
+			fmt.Printf("✅ Group %s: %d versions agree on %s\n",
+				group.Name, len(group.Entries), group.Expected)
+			continue
+		}
+
+		fmt.Printf("❌ Group %s: versions disagree (expected %s)\n\n",
+			group.Name, group.Expected)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FILE\tTYPE\tVERSION\tNOTE")
+		for _, entry := range group.Mismatches() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Name(),
+				valueOr(entry.ProjectType, "-"), valueOr(entry.Version, "-"),
+				entry.Error)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+	return nil
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package main
+
+import "testing"
+
+func TestParseGroupFlag(t *testing.T) {
+	group, err := parseGroupFlag("release=package.json, charts/*/Chart.yaml,")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if group.Name != "release" || len(group.Files) != 2 ||
+		group.Files[1].File != "charts/*/Chart.yaml" {
+		t.Errorf("unexpected group: %+v", group)
+	}
+
+	for _, bad := range []string{"release", "=package.json", "release="} {
+		if _, err := parseGroupFlag(bad); err == nil {
+			t.Errorf("parseGroupFlag(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// configCmd groups the configuration subcommands
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the pattern configuration",
+}
+
+// configDumpCmd prints the embedded default patterns
+var configDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the built-in default patterns",
+	Long: `Print the default pattern configuration built into the binary.
+
+Use it as a starting point for customisation: keep only the entries to
+change in a .version-extract.yaml or --config file, which is layered over
+the defaults.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := cmd.OutOrStdout().Write(config.DefaultConfigData())
+		return err
+	},
+}
+
+// configSchemaCmd prints the JSON Schema for configuration files
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for configuration files",
+	Long: `Print the JSON Schema that configuration files are validated against,
+generated from the configuration types.
+
+Editors with YAML language support use it for completion and checking. Point
+them at the published copy with a modeline at the top of the file:
+
+  # yaml-language-server: $schema=` + config.SchemaID,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := config.SchemaJSON()
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+// configValidateCmd strictly validates the layered configuration
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration for problems",
+	Long: `Load the built-in defaults, any .version-extract.yaml found from --path
+and the --config file in strict mode, and list every problem with its file,
+line and column.
+
+Strict mode flags departures from the JSON Schema (see "config schema") such
+as unknown keys and missing or mistyped values, regex patterns that do not compile or do not
+have exactly one capture group for the version, malformed
+dynamic_version_indicators, and every entry that would otherwise be skipped
+with a warning. The command exits non-zero when there are problems.`,
+	Args:         cobra.NoArgs,
+	RunE:         reportsOwnErrors(runConfigValidate),
+	SilenceUsage: true,
+}
+
+// runConfigValidate lists the problems in the layered configuration
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	strictConfig = true
+	cfg, err := loadConfiguration(path)
+
+	var invalid *config.ValidationError
+	if errors.As(err, &invalid) {
+		for _, problem := range invalid.Problems {
+			fmt.Fprintln(cmd.OutOrStdout(), problem)
+		}
+		err = fmt.Errorf("configuration has %d problem(s)", len(invalid.Problems))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Configuration is valid: %d project types\n",
+		len(cfg.Projects))
+	return nil
+}
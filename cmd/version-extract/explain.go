@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/extractor"
+)
+
+// explainCmd traces the decisions made during extraction
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Show how the version is extracted",
+	Long: `Extract the version as the root command does and show every decision
+made along the way: each project type tried in priority order, the files found
+for it, each regex pattern or lookup tried with its raw capture and why it was
+rejected, which dynamic version indicator fired, and which git strategy
+produced the tag.
+
+Project types after the one that produced the version are not tried. The
+command exits non-zero when no version is found.`,
+	Args:         cobra.NoArgs,
+	RunE:         reportsOwnErrors(runExplain),
+	SilenceUsage: true,
+}
+
+// runExplain extracts the version with tracing and prints the trace
+func runExplain(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfiguration(path)
+	if err != nil {
+		return handleError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+	ext, err := newExtractor(cfg)
+	if err != nil {
+		return handleError(err)
+	}
+	ext.SetTrace(true)
+
+	result, extractErr := ext.Extract(path)
+	if result == nil {
+		return handleError(extractErr)
+	}
+
+	if outputFormat == "json" {
+		var data []byte
+		if jsonFormat == "pretty" {
+			data, err = json.MarshalIndent(result, "", "  ")
+		} else {
+			data, err = json.Marshal(result)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	} else {
+		writeTrace(cmd.OutOrStdout(), result)
+	}
+
+	if extractErr != nil {
+		if outputFormat != "json" {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", extractErr)
+		}
+		return extractErr
+	}
+	return nil
+}
+
+// writeTrace prints an extraction trace as an outline, one project type at
+// a time
+func writeTrace(w io.Writer, result *extractor.ExtractResult) {
+	if result.Trace != nil {
+		for i, project := range result.Trace.Projects {
+			fmt.Fprintf(w, "%d. %s", i+1, project.ProjectType)
+			if project.Subtype != "" {
+				fmt.Fprintf(w, " (%s)", project.Subtype)
+			}
+			fmt.Fprintf(w, ": %s\n", project.Pattern)
+			if len(project.Files) == 0 {
+				fmt.Fprintf(w, "   no files found\n")
+			}
+			if project.Skipped != "" {
+				fmt.Fprintf(w, "   skipped: %s\n", project.Skipped)
+			}
+			for _, file := range project.Attempts {
+				writeFileTrace(w, file)
+			}
+		}
+	}
+
+	if result.Success {
+		fmt.Fprintf(w, "\n✅ Version %s from %s (%s)\n", result.Version, result.File,
+			result.VersionSource)
+	} else {
+		fmt.Fprintf(w, "\n❌ No version found\n")
+	}
+}
+
+// writeFileTrace prints how a file was examined
+func writeFileTrace(w io.Writer, file *extractor.FileTrace) {
+	fmt.Fprintf(w, "   %s", file.File)
+	if file.Validation != "" {
+		fmt.Fprintf(w, " [%s]", file.Validation)
+	}
+	fmt.Fprintln(w)
+
+	for _, match := range file.Matches {
+		fmt.Fprintf(w, "     %s: ", match.MatchedBy)
+		switch {
+		case match.Capture == "" && match.Rejected != "":
+			fmt.Fprintf(w, "%s\n", match.Rejected)
+		case match.Capture == "":
+			fmt.Fprintf(w, "%s\n", match.Version)
+		case match.Rejected != "":
+			fmt.Fprintf(w, "captured %q -> %q, %s\n", match.Capture, match.Version,
+				match.Rejected)
+		default:
+			fmt.Fprintf(w, "captured %q -> %q\n", match.Capture, match.Version)
+		}
+	}
+	if file.Dynamic != "" {
+		fmt.Fprintf(w, "     dynamic versioning: %s\n", file.Dynamic)
+	}
+	if g := file.Git; g != nil {
+		fmt.Fprintf(w, "     git tags (policy %s", g.Policy)
+		if g.TagPrefix != "" {
+			fmt.Fprintf(w, ", prefix %q", g.TagPrefix)
+		}
+		if g.Error != "" {
+			fmt.Fprintf(w, "): %s\n", g.Error)
+		} else {
+			fmt.Fprintf(w, "): %s found %s -> %s\n", g.Strategy, g.Tag, g.Version)
+		}
+	}
+	fmt.Fprintf(w, "     => %s\n", valueOr(file.Outcome, "not examined"))
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/extractor"
+)
+
+func TestWriteTrace(t *testing.T) {
+	result := &extractor.ExtractResult{
+		Version: "1.2.3", File: "pyproject.toml", Success: true,
+		VersionSource: "dynamic-git-tag",
+		Trace: &extractor.Trace{Projects: []*extractor.ProjectTrace{
+			{ProjectType: "JavaScript", Subtype: "npm", Pattern: "package.json"},
+			{ProjectType: "Python", Pattern: "pyproject.toml", Files: []string{"pyproject.toml"},
+				Attempts: []*extractor.FileTrace{{
+					File:       "pyproject.toml",
+					Validation: "version_scheme pep440",
+					Matches: []*extractor.MatchTrace{
+						{MatchedBy: `version = "([^"]+)"`, Rejected: "no match"},
+						{MatchedBy: `__version__ = (\S+)`, Capture: "'dev'", Version: "dev",
+							Rejected: "not a valid version"},
+					},
+					Dynamic: "dynamic contains version",
+					Git: &extractor.GitTrace{Policy: "nearest", Strategy: "describe --match v*",
+						Tag: "v1.2.3", Version: "1.2.3"},
+					Outcome: extractor.Selected,
+				}}},
+		}},
+	}
+
+	var out bytes.Buffer
+	writeTrace(&out, result)
+	expected := `1. JavaScript (npm): package.json
+   no files found
+2. Python: pyproject.toml
+   pyproject.toml [version_scheme pep440]
+     version = "([^"]+)": no match
+     __version__ = (\S+): captured "'dev'" -> "dev", not a valid version
+     dynamic versioning: dynamic contains version
+     git tags (policy nearest): describe --match v* found v1.2.3 -> 1.2.3
+     => selected
+
+✅ Version 1.2.3 from pyproject.toml (dynamic-git-tag)
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected trace output:\n%s\nExpected:\n%s", out.String(), expected)
+	}
+}
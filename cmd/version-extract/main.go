@@ -7,8 +7,11 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -31,18 +34,70 @@ var (
 	failOnError     bool
 	jsonFormat      string
 	dynamicFallback bool
+	extractAll      bool
+	gitVersionStyle string
+	tagPrefix       string
+	tagPolicy       string
+	excludePre      bool
+	gitBackend      string
+	revision        string
+	gradleProps     []string
+	strictConfig    bool
+	scheme          string
+	bumpSet         string
+	bumpPreid       string
+	dryRun          bool
+	logLevel        string
+	logFormat       string
 )
 
-// verboseLog outputs message to appropriate stream based on output format
-func verboseLog(message string) {
-	if !verbose {
-		return
+// logger receives diagnostics, configured by --log-level and --log-format
+var logger = slog.Default()
+
+// setupLogging creates the logger from the logging flags and makes it the
+// default, so diagnostics from every package go through it
+func setupLogging(cmd *cobra.Command, args []string) error {
+	l, err := newLogger(os.Stderr, logLevel, logFormat,
+		verbose && !cmd.Flags().Changed("log-level"))
+	if err != nil {
+		return err
 	}
-	if outputFormat == "json" {
-		fmt.Fprintf(os.Stderr, "%s\n", message)
-	} else {
-		fmt.Printf("%s\n", message)
+	logger = l
+	slog.SetDefault(l)
+	return nil
+}
+
+// newLogger creates a logger writing diagnostics at or above level to w, as
+// logfmt-style text without timestamps or as JSON. verbose lowers the level
+// to info, so progress messages are shown.
+func newLogger(w io.Writer, level, format string, verbose bool) (*slog.Logger, error) {
+	var min slog.Level
+	if err := min.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q (expected one of: debug, info, warn, error)", level)
 	}
+	if verbose && min > slog.LevelInfo {
+		min = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: min}
+	switch format {
+	case "text":
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		}
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (expected one of: text, json)", format)
+}
+
+// verboseLog logs a progress message at info level, which --verbose shows
+func verboseLog(message string, args ...any) {
+	logger.Info(message, args...)
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -57,7 +112,8 @@ C#/.NET, Go, PHP, Ruby, Rust, Swift, Dart/Flutter, and many more.
 
 The tool searches for project metadata files in order of popularity and
 uses regular expressions to extract version information.`,
-	RunE: runExtractor,
+	RunE:              runExtractor,
+	PersistentPreRunE: setupLogging,
 }
 
 // versionCmd represents the version command
@@ -82,6 +138,33 @@ project types in priority order.`,
 	RunE: listSupportedTypes,
 }
 
+// bumpCmd represents the bump command
+var bumpCmd = &cobra.Command{
+	Use:   "bump [major|minor|patch|prerelease]",
+	Short: "Rewrite the project version in place",
+	Long: `Bump the extracted project version and write it back to the file it
+came from.
+
+Only the matched version text is replaced, so the surrounding formatting,
+quoting and line endings are preserved. Either give a bump level or an
+explicit version with --set. Versions taken from git tags cannot be
+rewritten; versions resolved from a Kotlin constant are rewritten where the
+constant is defined.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"major", "minor", "patch", "prerelease"},
+	RunE:      runBump,
+}
+
+// reportsOwnErrors wraps the run function of a command that prints its own
+// errors and results, returning an error only for the exit status. Cobra
+// still prints the flag and argument errors found before the command runs.
+func reportsOwnErrors(run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceErrors = true
+		return run(cmd, args)
+	}
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		// Don't use log.Fatal as it interferes with JSON output format
@@ -90,11 +173,19 @@ func main() {
 }
 
 func init() {
+	// Logging flags, for every command
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn",
+		"Minimum level of diagnostics to log: debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text",
+		"Diagnostic log format: text, json")
+
 	// Root command flags
 	rootCmd.Flags().StringVarP(&path, "path", "p", ".",
 		"Path to search for project files or path to a specific file")
 	rootCmd.Flags().StringVarP(&configPath, "config", "c", "",
-		"Path to configuration file (default: configs/default-patterns.yaml)")
+		"Configuration layered over the defaults and any .version-extract.yaml")
+	rootCmd.Flags().BoolVar(&strictConfig, "strict", false,
+		"Fail on any configuration problem instead of skipping the entry")
 	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", "text",
 		"Output format: text, json")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false,
@@ -105,60 +196,239 @@ func init() {
 		"JSON output format: pretty, minimised")
 	rootCmd.Flags().BoolVar(&dynamicFallback, "dynamic-fallback", true,
 		"Enable dynamic versioning fallback to Git tags")
+	rootCmd.Flags().BoolVar(&extractAll, "all", false,
+		"Extract versions from every supported project file (monorepo mode)")
+	rootCmd.Flags().StringVar(&gitVersionStyle, "git-version-style", "exact",
+		"Style for versions from git tags with later commits: exact, semver, pep440, maven-snapshot")
+	rootCmd.Flags().StringVar(&tagPrefix, "tag-prefix", "",
+		"Only use git tags with this prefix, e.g. api/ or {{.Path}}/ (overrides tag_prefix)")
+	rootCmd.Flags().StringVar(&tagPolicy, "tag-policy", "nearest",
+		"Which git tag is the latest: nearest, highest-reachable, highest-any")
+	rootCmd.Flags().BoolVar(&excludePre, "exclude-prerelease", false,
+		"Skip pre-release git tags")
+	rootCmd.Flags().StringVar(&gitBackend, "git-backend", "auto",
+		"How to read git repositories: auto, exec (run git) or native (read .git directly)")
+	rootCmd.Flags().StringVar(&scheme, "scheme", "",
+		"Validate versions against this scheme, e.g. semver or calver:YYYY.0M (overrides version_scheme)")
+	rootCmd.Flags().StringVar(&revision, "rev", "",
+		"Read project files from this git commit, tag or branch instead of the work tree")
+	rootCmd.Flags().StringArrayVarP(&gradleProps, "gradle-property", "P", nil,
+		"Set a Gradle project property as name=value, overriding gradle.properties; repeat for more properties")
 
 	// List command flags
 	listCmd.Flags().StringVarP(&configPath, "config", "c", "",
-		"Path to configuration file (default: configs/default-patterns.yaml)")
+		"Configuration layered over the defaults and any .version-extract.yaml")
+	listCmd.Flags().BoolVar(&strictConfig, "strict", false,
+		"Fail on any configuration problem instead of skipping the entry")
 	listCmd.Flags().StringVarP(&outputFormat, "format", "f", "text",
 		"Output format: text, json")
 	listCmd.Flags().StringVar(&jsonFormat, "json-format", "pretty",
 		"JSON output format: pretty, minimised")
 
+	// Bump command flags
+	bumpCmd.Flags().StringVarP(&path, "path", "p", ".",
+		"Path to search for project files or path to a specific file")
+	bumpCmd.Flags().StringVarP(&configPath, "config", "c", "",
+		"Configuration layered over the defaults and any .version-extract.yaml")
+	bumpCmd.Flags().BoolVar(&strictConfig, "strict", false,
+		"Fail on any configuration problem instead of skipping the entry")
+	bumpCmd.Flags().StringVarP(&outputFormat, "format", "f", "text",
+		"Output format: text, json")
+	bumpCmd.Flags().StringVar(&jsonFormat, "json-format", "pretty",
+		"JSON output format: pretty, minimised")
+	bumpCmd.Flags().StringVar(&bumpSet, "set", "",
+		"Set an explicit version instead of bumping")
+	bumpCmd.Flags().StringVar(&bumpPreid, "preid", "",
+		"Pre-release identifier for prerelease bumps (e.g. rc)")
+	bumpCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Show a unified diff of the change without writing it")
+
+	// Check command flags
+	checkCmd.Flags().StringVarP(&path, "path", "p", ".",
+		"Path to search for project files")
+	checkCmd.Flags().StringVarP(&configPath, "config", "c", "",
+		"Configuration layered over the defaults and any .version-extract.yaml")
+	checkCmd.Flags().BoolVar(&strictConfig, "strict", false,
+		"Fail on any configuration problem instead of skipping the entry")
+	checkCmd.Flags().StringVarP(&outputFormat, "format", "f", "text",
+		"Output format: text, json")
+	checkCmd.Flags().StringVar(&jsonFormat, "json-format", "pretty",
+		"JSON output format: pretty, minimised")
+	checkCmd.Flags().BoolVar(&dynamicFallback, "dynamic-fallback", true,
+		"Enable dynamic versioning fallback to Git tags")
+	checkCmd.Flags().BoolVar(&checkGitTag, "git-tag", false,
+		"Also compare versions with the latest git tag")
+	checkCmd.Flags().StringArrayVar(&checkGroups, "group", nil,
+		"Files that must agree, as name=glob[,glob...] (repeatable; overrides consistency_groups)")
+	checkCmd.Flags().StringVar(&tagPrefix, "tag-prefix", "",
+		"Only use git tags with this prefix, e.g. api/")
+	checkCmd.Flags().StringVar(&tagPolicy, "tag-policy", "nearest",
+		"Which git tag is the latest: nearest, highest-reachable, highest-any")
+	checkCmd.Flags().BoolVar(&excludePre, "exclude-prerelease", false,
+		"Skip pre-release git tags")
+	checkCmd.Flags().StringVar(&gitBackend, "git-backend", "auto",
+		"How to read git repositories: auto, exec (run git) or native (read .git directly)")
+	checkCmd.Flags().StringVar(&scheme, "scheme", "",
+		"Validate versions against this scheme (overrides version_scheme)")
+	checkCmd.Flags().StringVar(&revision, "rev", "",
+		"Read project files from this git commit, tag or branch instead of the work tree")
+	checkCmd.Flags().StringArrayVarP(&gradleProps, "gradle-property", "P", nil,
+		"Set a Gradle project property as name=value, overriding gradle.properties; repeat for more properties")
+
+	// Explain command flags
+	explainCmd.Flags().StringVarP(&path, "path", "p", ".",
+		"Path to search for project files or path to a specific file")
+	explainCmd.Flags().StringVarP(&configPath, "config", "c", "",
+		"Configuration layered over the defaults and any .version-extract.yaml")
+	explainCmd.Flags().BoolVar(&strictConfig, "strict", false,
+		"Fail on any configuration problem instead of skipping the entry")
+	explainCmd.Flags().StringVarP(&outputFormat, "format", "f", "text",
+		"Output format: text, json")
+	explainCmd.Flags().StringVar(&jsonFormat, "json-format", "pretty",
+		"JSON output format: pretty, minimised")
+	explainCmd.Flags().BoolVar(&dynamicFallback, "dynamic-fallback", true,
+		"Enable dynamic versioning fallback to Git tags")
+	explainCmd.Flags().StringVar(&gitVersionStyle, "git-version-style", "exact",
+		"Style for versions from git tags with later commits: exact, semver, pep440, maven-snapshot")
+	explainCmd.Flags().StringVar(&tagPrefix, "tag-prefix", "",
+		"Only use git tags with this prefix, e.g. api/ or {{.Path}}/ (overrides tag_prefix)")
+	explainCmd.Flags().StringVar(&tagPolicy, "tag-policy", "nearest",
+		"Which git tag is the latest: nearest, highest-reachable, highest-any")
+	explainCmd.Flags().BoolVar(&excludePre, "exclude-prerelease", false,
+		"Skip pre-release git tags")
+	explainCmd.Flags().StringVar(&gitBackend, "git-backend", "auto",
+		"How to read git repositories: auto, exec (run git) or native (read .git directly)")
+	explainCmd.Flags().StringVar(&scheme, "scheme", "",
+		"Validate versions against this scheme, e.g. semver or calver:YYYY.0M (overrides version_scheme)")
+	explainCmd.Flags().StringVar(&revision, "rev", "",
+		"Read project files from this git commit, tag or branch instead of the work tree")
+	explainCmd.Flags().StringArrayVarP(&gradleProps, "gradle-property", "P", nil,
+		"Set a Gradle project property as name=value, overriding gradle.properties; repeat for more properties")
+
 	// Add subcommands
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(bumpCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configDumpCmd)
+	configCmd.AddCommand(configSchemaCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	// Config validate command flags
+	configValidateCmd.Flags().StringVarP(&path, "path", "p", ".",
+		"Path whose .version-extract.yaml is validated")
+	configValidateCmd.Flags().StringVarP(&configPath, "config", "c", "",
+		"Configuration layered over the defaults and any .version-extract.yaml")
 }
 
-// runExtractor is the main extraction function
-func runExtractor(cmd *cobra.Command, args []string) error {
-	// Set default config path if not provided
-	if configPath == "" {
-		configPath = config.GetDefaultConfigPath()
+// loadConfiguration loads the embedded default patterns, then a
+// .version-extract.yaml found in the search path or its parents, then the
+// --config file, each layered over the ones before
+func loadConfiguration(searchPath string) (*config.Config, error) {
+	var layers []string
+	local, err := config.DiscoverConfig(searchPath)
+	if err != nil {
+		return nil, err
+	}
+	if local != "" {
+		layers = append(layers, local)
+	}
+	if configPath != "" {
+		layers = append(layers, absPath(configPath))
 	}
 
-	// Make config path absolute if relative
-	if !filepath.IsAbs(configPath) {
+	verboseLog("loading configuration", "layers",
+		append([]string{config.DefaultConfigName}, layers...))
+	loader := config.NewLoader()
+	loader.SetStrict(strictConfig)
+	loader.SetLogger(logger)
+	return loader.LoadDefault(layers...)
+}
+
+// absPath makes a relative path absolute against the working directory
+func absPath(p string) string {
+	if !filepath.IsAbs(p) {
 		if wd, err := os.Getwd(); err == nil {
-			configPath = filepath.Join(wd, configPath)
+			return filepath.Join(wd, p)
 		}
 	}
+	return p
+}
 
-	verboseLog(fmt.Sprintf("Loading configuration from: %s", configPath))
-	verboseLog(fmt.Sprintf("Searching in path: %s", path))
+// runExtractor is the main extraction function
+func runExtractor(cmd *cobra.Command, args []string) error {
+	verboseLog("searching for project files", "path", path)
 
-	cfg, err := config.LoadConfig(configPath)
+	cfg, err := loadConfiguration(path)
 	if err != nil {
 		return handleError(fmt.Errorf("failed to load configuration: %w", err))
 	}
 
-	verboseLog(fmt.Sprintf("Loaded %d project configurations", len(cfg.Projects)))
+	verboseLog("loaded configuration", "projects", len(cfg.Projects))
 
-	ext := extractor.NewWithOptions(cfg, dynamicFallback)
+	ext, err := newExtractor(cfg)
+	if err != nil {
+		return handleError(err)
+	}
+
+	if extractAll {
+		results, err := ext.ExtractAll(path)
+		if err != nil {
+			if failOnError {
+				return handleError(fmt.Errorf("version extraction failed: %w", err))
+			}
+			logger.Info("version extraction failed", "error", err)
+		}
+		return outputAllResults(results, err)
+	}
 
 	result, err := ext.Extract(path)
 	if err != nil {
 		if failOnError {
 			return handleError(fmt.Errorf("version extraction failed: %w", err))
 		}
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
-		}
+		logger.Info("version extraction failed", "error", err)
 	}
 
 	// Output result
 	return outputResult(result, err)
 }
 
+// newExtractor creates an extractor with the extraction flags applied
+func newExtractor(cfg *config.Config) (*extractor.VersionExtractor, error) {
+	ext := extractor.NewWithOptions(cfg, dynamicFallback)
+	ext.SetLogger(logger)
+	if err := ext.SetGitVersionStyle(gitVersionStyle); err != nil {
+		return nil, err
+	}
+	if err := ext.SetTagPrefix(tagPrefix); err != nil {
+		return nil, err
+	}
+	if err := ext.SetTagPolicy(tagPolicy); err != nil {
+		return nil, err
+	}
+	ext.SetExcludePrerelease(excludePre)
+	if err := ext.SetGitBackend(gitBackend); err != nil {
+		return nil, err
+	}
+	if err := ext.SetScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := ext.SetRevision(revision); err != nil {
+		return nil, err
+	}
+	for _, prop := range gradleProps {
+		// As with gradle -Pname, a property without a value is empty
+		name, value, _ := strings.Cut(prop, "=")
+		if err := ext.SetGradleProperty(name, value); err != nil {
+			return nil, err
+		}
+	}
+	return ext, nil
+}
+
 // handleError outputs error in the appropriate format and returns the error
 func handleError(err error) error {
 	if outputFormat == "json" {
@@ -194,19 +464,7 @@ func handleError(err error) error {
 
 // listSupportedTypes lists all supported project types
 func listSupportedTypes(cmd *cobra.Command, args []string) error {
-	// Set default config path if not provided
-	if configPath == "" {
-		configPath = config.GetDefaultConfigPath()
-	}
-
-	// Make config path absolute if relative
-	if !filepath.IsAbs(configPath) {
-		if wd, err := os.Getwd(); err == nil {
-			configPath = filepath.Join(wd, configPath)
-		}
-	}
-
-	cfg, err := config.LoadConfig(configPath)
+	cfg, err := loadConfiguration(".")
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
@@ -246,6 +504,60 @@ func listSupportedTypes(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runBump rewrites the extracted version in place
+func runBump(cmd *cobra.Command, args []string) error {
+	opts := extractor.BumpOptions{
+		Set:    bumpSet,
+		Preid:  bumpPreid,
+		DryRun: dryRun,
+	}
+	switch {
+	case len(args) == 1 && bumpSet != "":
+		return handleError(fmt.Errorf("give either a bump level or --set, not both"))
+	case len(args) == 1:
+		opts.Level = args[0]
+	case bumpSet == "":
+		return handleError(fmt.Errorf("a bump level (major, minor, patch, " +
+			"prerelease) or --set is required"))
+	}
+
+	cfg, err := loadConfiguration(path)
+	if err != nil {
+		return handleError(fmt.Errorf("failed to load configuration: %w", err))
+	}
+
+	// Keep dynamic detection on: a project versioned from git tags must be
+	// refused, not have its placeholder (e.g. 0.0.0-development) rewritten.
+	ext := extractor.NewWithOptions(cfg, true)
+	ext.SetLogger(logger)
+	result, err := ext.Bump(path, opts)
+	if err != nil {
+		return handleError(fmt.Errorf("version bump failed: %w", err))
+	}
+
+	if outputFormat == "json" {
+		var data []byte
+		if jsonFormat == "pretty" {
+			data, err = json.MarshalIndent(result, "", "  ")
+		} else {
+			data, err = json.Marshal(result)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if result.DryRun {
+		fmt.Print(result.Diff)
+		return nil
+	}
+	fmt.Printf("✅ Version bumped: %s -> %s\n", result.OldVersion, result.NewVersion)
+	fmt.Printf("File: %s\n", result.File)
+	return nil
+}
+
 // outputResult formats and outputs the extraction result
 func outputResult(result *extractor.ExtractResult, extractErr error) error {
 	if outputFormat == "json" {
@@ -260,8 +572,28 @@ func outputResult(result *extractor.ExtractResult, extractErr error) error {
 			output["file"] = result.File
 			output["matched_by"] = result.MatchedBy
 			output["version_source"] = result.VersionSource
+			if result.Fields != nil {
+				output["major"] = result.Major
+				output["minor"] = result.Minor
+				output["patch"] = result.Patch
+				output["prerelease"] = result.Prerelease
+				output["build"] = result.Build
+				output["scheme"] = result.Scheme
+			}
+			if result.CanonicalVersion != "" {
+				output["canonical_version"] = result.CanonicalVersion
+			}
+			if result.SemverVersion != "" {
+				output["semver_version"] = result.SemverVersion
+			}
+			if result.Snapshot {
+				output["snapshot"] = true
+			}
 			if result.GitTag != "" {
 				output["git_tag"] = result.GitTag
+				output["git_distance"] = result.GitDistance
+				output["git_commit"] = result.GitCommit
+				output["git_dirty"] = result.GitDirty
 			}
 		}
 
@@ -304,3 +636,59 @@ func outputResult(result *extractor.ExtractResult, extractErr error) error {
 
 	return nil
 }
+
+// outputAllResults formats and outputs the results of a monorepo (--all)
+// extraction. JSON output is an object holding a "results" array, with one
+// entry per manifest file.
+func outputAllResults(results []*extractor.ExtractResult, extractErr error) error {
+	if outputFormat == "json" {
+		if results == nil {
+			results = []*extractor.ExtractResult{}
+		}
+		output := map[string]interface{}{
+			"success": len(results) > 0,
+			"results": results,
+		}
+
+		if extractErr != nil {
+			output["error"] = extractErr.Error()
+		}
+
+		var data []byte
+		var err error
+		if jsonFormat == "pretty" {
+			data, err = json.MarshalIndent(output, "", "  ")
+		} else {
+			data, err = json.Marshal(output)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Text output
+	if len(results) == 0 {
+		fmt.Printf("❌ No version found\n")
+		if extractErr != nil {
+			fmt.Printf("Error: %v\n", extractErr)
+		}
+		return nil
+	}
+
+	fmt.Printf("✅ Extracted %d versions\n", len(results))
+	for _, result := range results {
+		fmt.Printf("\n%s: %s\n", result.RelativePath, result.Version)
+		fmt.Printf("   Project Type: %s", result.ProjectType)
+		if result.Subtype != "" {
+			fmt.Printf(" (%s)", result.Subtype)
+		}
+		fmt.Printf("\n   Version Source: %s\n", result.VersionSource)
+		if verbose {
+			fmt.Printf("   Matched by: %s\n", result.MatchedBy)
+		}
+	}
+
+	return nil
+}
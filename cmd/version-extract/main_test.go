@@ -4,9 +4,13 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
 )
 
 func TestHandleErrorJSONOutput(t *testing.T) {
@@ -122,3 +126,91 @@ type simpleError struct {
 func (e *simpleError) Error() string {
 	return e.message
 }
+
+func TestConfigDump(t *testing.T) {
+	var out bytes.Buffer
+	configDumpCmd.SetOut(&out)
+	defer configDumpCmd.SetOut(nil)
+
+	if err := configDumpCmd.RunE(configDumpCmd, nil); err != nil {
+		t.Fatalf("config dump failed: %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), config.DefaultConfigData()) {
+		t.Error("Expected config dump to print the embedded defaults")
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	var out bytes.Buffer
+	configSchemaCmd.SetOut(&out)
+	defer configSchemaCmd.SetOut(nil)
+
+	if err := configSchemaCmd.RunE(configSchemaCmd, nil); err != nil {
+		t.Fatalf("config schema failed: %v", err)
+	}
+	var schema config.JSONSchema
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("Expected config schema to print JSON: %v", err)
+	}
+	if schema.ID != config.SchemaID || schema.Defs["ProjectConfig"] == nil {
+		t.Errorf("Expected the configuration schema, got %s", out.String())
+	}
+}
+
+// TestFlagErrorsReported checks that commands printing their own errors
+// still report flag and argument errors
+func TestFlagErrorsReported(t *testing.T) {
+	var stderr bytes.Buffer
+	rootCmd.SetErr(&stderr)
+	defer rootCmd.SetErr(nil)
+	defer rootCmd.SetArgs(nil)
+
+	for _, args := range [][]string{
+		{"check", "--bogus"},
+		{"config", "validate", "--strict"},
+		{"explain", "--bogus"},
+	} {
+		stderr.Reset()
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+		if !strings.Contains(stderr.String(), "unknown flag") {
+			t.Errorf("%v: expected the flag error to be printed, got %q", args, stderr.String())
+		}
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := newLogger(&out, "warn", "text", false)
+	if err != nil {
+		t.Fatalf("newLogger failed: %v", err)
+	}
+	logger.Info("hidden")
+	logger.Warn("shown", "file", "x.yaml")
+	if got := out.String(); got != "level=WARN msg=shown file=x.yaml\n" {
+		t.Errorf("Unexpected text log output: %q", got)
+	}
+
+	out.Reset()
+	logger, err = newLogger(&out, "warn", "json", true)
+	if err != nil {
+		t.Fatalf("newLogger failed: %v", err)
+	}
+	logger.Info("progress")
+	var event map[string]any
+	if err := json.Unmarshal(out.Bytes(), &event); err != nil {
+		t.Fatalf("Expected a JSON log event, got %q: %v", out.String(), err)
+	}
+	if event["level"] != "INFO" || event["msg"] != "progress" {
+		t.Errorf("Expected --verbose to log info events, got %v", event)
+	}
+
+	if _, err := newLogger(&out, "loud", "text", false); err == nil {
+		t.Error("Expected an unknown log level to fail")
+	}
+	if _, err := newLogger(&out, "warn", "xml", false); err == nil {
+		t.Error("Expected an unknown log format to fail")
+	}
+}
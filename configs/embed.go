@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+// Package configs embeds the default pattern set into the binary, so it does
+// not depend on the working directory.
+package configs
+
+import _ "embed"
+
+// DefaultPatterns is the content of default-patterns.yaml
+//
+//go:embed default-patterns.yaml
+var DefaultPatterns []byte
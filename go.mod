@@ -6,6 +6,7 @@ module github.com/lfreleng-actions/version-extract-action
 go 1.24
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/spf13/cobra v1.9.1
 	gopkg.in/yaml.v3 v3.0.1
 )
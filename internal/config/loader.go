@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lfreleng-actions/version-extract-action/configs"
+)
+
+// LocalConfigName is the repository-local configuration file, found in the
+// search path or one of its parents
+const LocalConfigName = ".version-extract.yaml"
+
+// DefaultConfigName names the embedded default patterns in messages
+const DefaultConfigName = "built-in defaults"
+
+// layer is a configuration file as written. Entries stay as YAML nodes so
+// they can be decoded over the entries they override, changing only the
+// keys they set.
+type layer struct {
+	// Replace discards the layers below instead of merging with them
+	Replace           bool        `yaml:"replace,omitempty"`
+	Projects          []yaml.Node `yaml:"projects"`
+	ConsistencyGroups []yaml.Node `yaml:"consistency_groups,omitempty"`
+}
+
+// source is the content of one configuration layer
+type source struct {
+	name string
+	data []byte
+}
+
+// DefaultConfigData returns the default patterns embedded in the binary
+func DefaultConfigData() []byte {
+	return configs.DefaultPatterns
+}
+
+// Loader loads layered configuration. Each file is checked against the
+// JSON Schema as it is read. By default it logs a warning about each
+// problem and drops the entries it cannot use; in strict mode it fails with
+// a ValidationError listing every problem instead.
+type Loader struct {
+	strict bool
+	logger *slog.Logger
+	fsys   fs.FS // Configuration files are read from; nil for the host's
+}
+
+// NewLoader creates a Loader in the default, lenient mode
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// SetStrict selects strict mode, in which departures from the JSON Schema
+// such as unknown keys, regex patterns that do not compile or lack exactly
+// one capture group, malformed dynamic version indicators and every entry
+// lenient loading would skip are reported as problems with their file, line
+// and column
+func (l *Loader) SetStrict(strict bool) {
+	l.strict = strict
+}
+
+// SetLogger sets the logger for warnings about the configuration. A nil
+// logger, the default, uses slog.Default.
+func (l *Loader) SetLogger(logger *slog.Logger) {
+	l.logger = logger
+}
+
+// SetFS reads configuration files from fsys instead of the host file
+// system, with paths in fsys, e.g. one returned by DiscoverConfigFS. A nil
+// fsys, the default, reads the host file system.
+func (l *Loader) SetFS(fsys fs.FS) {
+	l.fsys = fsys
+}
+
+// readFile reads a configuration file from the loader's file system
+func (l *Loader) readFile(name string) ([]byte, error) {
+	if l.fsys == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(l.fsys, path.Clean(filepath.ToSlash(name)))
+}
+
+// LoadConfig loads and validates configuration from one or more YAML files.
+// Each file is a layer over those before it: a project entry with the same
+// type, subtype and file as an earlier one overrides just the keys it sets,
+// "disabled: true" removes it, and other entries add project types.
+// Consistency groups are merged the same way by name. A layer with
+// "replace: true" starts afresh instead. Warnings are logged to
+// slog.Default; use a Loader to log elsewhere.
+func LoadConfig(configPaths ...string) (*Config, error) {
+	return NewLoader().Load(configPaths...)
+}
+
+// LoadDefaultConfig loads the embedded default patterns with any files
+// layered over them, as LoadConfig layers files
+func LoadDefaultConfig(configPaths ...string) (*Config, error) {
+	return NewLoader().LoadDefault(configPaths...)
+}
+
+// Load loads configuration files layered as for LoadConfig
+func (l *Loader) Load(configPaths ...string) (*Config, error) {
+	if len(configPaths) == 0 {
+		return nil, fmt.Errorf("no config file given")
+	}
+	return l.load(nil, configPaths)
+}
+
+// LoadDefault loads the embedded default patterns with any files layered
+// over them
+func (l *Loader) LoadDefault(configPaths ...string) (*Config, error) {
+	defaults := source{name: DefaultConfigName, data: DefaultConfigData()}
+	return l.load([]source{defaults}, configPaths)
+}
+
+// load reads the configuration files, then merges them in order after the
+// given sources and validates the result
+func (l *Loader) load(sources []source, configPaths []string) (*Config, error) {
+	for _, configPath := range configPaths {
+		data, err := l.readFile(configPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("config file not found: %s", configPath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		sources = append(sources, source{name: configPath, data: data})
+	}
+
+	v := &validator{strict: l.strict, logger: l.logger}
+	if v.logger == nil {
+		v.logger = slog.Default()
+	}
+	var config Config
+	incomplete := false
+	for _, src := range sources {
+		// Parse YAML
+		var doc yaml.Node
+		if err := yaml.Unmarshal(src.data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config %s: %w", src.name, err)
+		}
+		start := len(v.problems)
+		v.checkDocument(src.name, &doc)
+		if !l.strict {
+			for _, problem := range v.problems[start:] {
+				o := origin{file: problem.File, line: problem.Line, column: problem.Column}
+				v.logger.Warn(problem.Message, o.attrs()...)
+			}
+			v.problems = v.problems[:start]
+		}
+		var layer layer
+		var err error
+		if len(doc.Content) > 0 {
+			err = doc.Decode(&layer)
+		}
+		if err == nil {
+			err = mergeLayer(&config, v, src.name, &layer)
+		}
+		if err != nil && l.strict && len(v.problems) > start {
+			// The schema problems explain the failure; report them with
+			// those of the other files
+			incomplete = true
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config %s: %w", src.name, err)
+		}
+	}
+	if incomplete {
+		return nil, &ValidationError{Problems: v.problems}
+	}
+
+	projects, origins := config.Projects[:0], v.projects[:0]
+	for i, project := range config.Projects {
+		if !project.Disabled {
+			projects = append(projects, project)
+			origins = append(origins, v.projects[i])
+		}
+	}
+	config.Projects, v.projects = projects, origins
+
+	err := v.validate(&config)
+	if l.strict && (err != nil || len(v.problems) > 0) {
+		if err != nil {
+			v.problems = append(v.problems, Problem{Message: err.Error()})
+		}
+		return nil, &ValidationError{Problems: v.problems}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	// Sort projects by priority
+	sortProjectsByPriority(&config)
+
+	return &config, nil
+}
+
+// mergeLayer merges a layer from the named file into the configuration
+// built from the layers below it, recording where each entry was last
+// defined. Entries are only matched against earlier layers, so duplicates
+// within one file are still reported by validation.
+func mergeLayer(config *Config, v *validator, name string, l *layer) error {
+	if l.Replace {
+		*config = Config{}
+		v.projects, v.groups = nil, nil
+	}
+
+	base := len(config.Projects)
+	for i := range l.Projects {
+		node := &l.Projects[i]
+		var entry ProjectConfig
+		if err := node.Decode(&entry); err != nil {
+			return err
+		}
+		match := -1
+		for j := 0; j < base; j++ {
+			p := &config.Projects[j]
+			if p.Type == entry.Type && p.Subtype == entry.Subtype && p.File == entry.File {
+				match = j
+				break
+			}
+		}
+		if match < 0 {
+			config.Projects = append(config.Projects, entry)
+			v.projects = append(v.projects, at(name, node))
+			continue
+		}
+		if err := node.Decode(&config.Projects[match]); err != nil {
+			return err
+		}
+		v.projects[match] = at(name, node)
+	}
+
+	base = len(config.ConsistencyGroups)
+	for i := range l.ConsistencyGroups {
+		node := &l.ConsistencyGroups[i]
+		var group ConsistencyGroup
+		if err := node.Decode(&group); err != nil {
+			return err
+		}
+		match := -1
+		for j := 0; j < base; j++ {
+			if config.ConsistencyGroups[j].Name == group.Name {
+				match = j
+				break
+			}
+		}
+		if match < 0 {
+			config.ConsistencyGroups = append(config.ConsistencyGroups, group)
+			v.groups = append(v.groups, at(name, node))
+			continue
+		}
+		if err := node.Decode(&config.ConsistencyGroups[match]); err != nil {
+			return err
+		}
+		v.groups[match] = at(name, node)
+	}
+	return nil
+}
+
+// DiscoverConfig looks for LocalConfigName in the search path and its
+// parents, stopping at the top of the git work tree, and returns its path,
+// or "" when there is none. A search path naming a file starts from the
+// file's directory.
+func DiscoverConfig(searchPath string) (string, error) {
+	dir, err := filepath.Abs(searchPath)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		candidate := filepath.Join(dir, LocalConfigName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// DiscoverConfigFS looks for LocalConfigName in fsys as DiscoverConfig does
+// on the host, from the search path up to the root of fsys, and returns its
+// path in fsys, or "" when there is none
+func DiscoverConfigFS(fsys fs.FS, searchPath string) (string, error) {
+	dir := path.Clean(filepath.ToSlash(searchPath))
+	if !fs.ValidPath(dir) {
+		return "", fmt.Errorf("invalid path in file system: %s", searchPath)
+	}
+	if info, err := fs.Stat(fsys, dir); err == nil && !info.IsDir() {
+		dir = path.Dir(dir)
+	}
+
+	for {
+		candidate := path.Join(dir, LocalConfigName)
+		if info, err := fs.Stat(fsys, candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+		if _, err := fs.Stat(fsys, path.Join(dir, ".git")); err == nil || dir == "." {
+			return "", nil
+		}
+		dir = path.Dir(dir)
+	}
+}
@@ -4,9 +4,14 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -172,7 +177,78 @@ func TestValidateConfig(t *testing.T) {
 			expectCount: 0,
 		},
 		{
-			name: "missing samples",
+			name: "structured lookup without regex",
+			config: Config{
+				Projects: []ProjectConfig{
+					{
+						Type:    "JavaScript",
+						File:    "package.json",
+						Path:    "$.version",
+						Samples: []string{"https://github.com/test/repo"},
+					},
+				},
+			},
+			expectError: false,
+			expectCount: 1,
+		},
+		{
+			name: "more than one structured lookup",
+			config: Config{
+				Projects: []ProjectConfig{
+					{
+						Type:    "Rust",
+						File:    "Cargo.toml",
+						Path:    "$.package.version",
+						TomlKey: "package.version",
+						Samples: []string{"https://github.com/test/repo"},
+					},
+				},
+			},
+			expectError: true,
+			expectCount: 0,
+		},
+		{
+			name: "invalid tag prefix template",
+			config: Config{
+				Projects: []ProjectConfig{
+					{
+						Type:      "Go",
+						File:      "go.mod",
+						TagPrefix: "{{.Path",
+						Samples:   []string{"https://github.com/test/repo"},
+
+						SupportsDynamicVersioning: true,
+					},
+				},
+			},
+			expectError: true,
+			expectCount: 0,
+		},
+		{
+			name: "invalid version scheme",
+			config: Config{
+				Projects: []ProjectConfig{
+					{
+						Type:          "JavaScript",
+						File:          "package.json",
+						Regex:         []string{`"version":\s*"([^"]+)"`},
+						VersionScheme: "calver:YYY",
+						Samples:       []string{"https://github.com/test/repo"},
+					},
+					{
+						Type:          "Python",
+						File:          "pyproject.toml",
+						Regex:         []string{`version\s*=\s*"([^"]+)"`},
+						VersionScheme: "pep440",
+						Samples:       []string{"https://github.com/test/repo"},
+					},
+				},
+			},
+			expectError: false,
+			expectCount: 1,
+		},
+		{
+			name: "samples only required by the defaults",
 			config: Config{
 				Projects: []ProjectConfig{
 					{
@@ -182,8 +258,8 @@ func TestValidateConfig(t *testing.T) {
 					},
 				},
 			},
-			expectError: true,
-			expectCount: 0,
+			expectError: false,
+			expectCount: 1,
 		},
 	}
 
@@ -306,3 +382,383 @@ func TestGetDefaultConfigPath(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, path)
 	}
 }
+
+func TestLoadConfigConsistencyGroups(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "groups.yaml")
+
+	content := `---
+projects:
+  - type: JavaScript
+    file: package.json
+    regex:
+      - '"version":\s*"([^"]+)"'
+    samples:
+      - https://github.com/facebook/react
+consistency_groups:
+  - name: release
+    git_tag: true
+    files:
+      - package.json
+      - file: charts/*/Chart.yaml
+        path: $.appVersion
+  - name: broken
+    files:
+      - "[unclosed"
+  - name: empty
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	cfg, err := LoadConfig(configFile)
+	if err != nil {
+		t.Fatalf("Expected successful load, got error: %v", err)
+	}
+	if len(cfg.ConsistencyGroups) != 1 {
+		t.Fatalf("Expected invalid groups to be dropped, got %+v", cfg.ConsistencyGroups)
+	}
+
+	group := cfg.ConsistencyGroups[0]
+	if group.Name != "release" || !group.GitTag || len(group.Files) != 2 {
+		t.Fatalf("Unexpected group: %+v", group)
+	}
+	if group.Files[0].File != "package.json" || group.Files[0].Path != "" {
+		t.Errorf("Expected plain file shorthand, got %+v", group.Files[0])
+	}
+	if group.Files[1].File != "charts/*/Chart.yaml" || group.Files[1].Path != "$.appVersion" {
+		t.Errorf("Expected file with lookup, got %+v", group.Files[1])
+	}
+}
+
+func TestLoadConfigLayers(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		file := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test config file: %v", err)
+		}
+		return file
+	}
+
+	defaults := write("defaults.yaml", `---
+projects:
+  - type: JavaScript
+    subtype: npm
+    file: package.json
+    regex:
+      - '"version":\s*"([^"]+)"'
+    samples:
+      - https://github.com/facebook/react
+    priority: 1
+  - type: Python
+    subtype: Modern
+    file: pyproject.toml
+    regex:
+      - 'version\s*=\s*"([^"]+)"'
+    samples:
+      - https://github.com/pallets/flask
+    priority: 2
+    supports_dynamic_versioning: true
+  - type: Go
+    file: go.mod
+    regex: []
+    samples:
+      - https://github.com/golang/go
+    priority: 3
+    supports_dynamic_versioning: true
+consistency_groups:
+  - name: release
+    files:
+      - package.json
+`)
+	local := write("local.yaml", `---
+projects:
+  - type: Python
+    subtype: Modern
+    file: pyproject.toml
+    regex:
+      - '__version__\s*=\s*"([^"]+)"'
+    supports_dynamic_versioning: false
+  - type: Go
+    file: go.mod
+    disabled: true
+  - type: Custom
+    file: VERSION.txt
+    regex:
+      - '^(\S+)$'
+    samples:
+      - https://example.com/custom
+consistency_groups:
+  - name: release
+    git_tag: true
+`)
+	explicit := write("explicit.yaml", `---
+projects:
+  - type: JavaScript
+    subtype: npm
+    file: package.json
+    priority: 10
+`)
+
+	cfg, err := LoadConfig(defaults, local, explicit)
+	if err != nil {
+		t.Fatalf("Expected successful load, got error: %v", err)
+	}
+
+	var types []string
+	for _, project := range cfg.Projects {
+		types = append(types, project.Type)
+	}
+	if strings.Join(types, ",") != "Python,Custom,JavaScript" {
+		t.Fatalf("Expected Python, Custom, JavaScript by priority, got %v", types)
+	}
+
+	python := cfg.Projects[0]
+	if len(python.Regex) != 1 || python.Regex[0] != `__version__\s*=\s*"([^"]+)"` {
+		t.Errorf("Expected the regex to be overridden, got %v", python.Regex)
+	}
+	if python.SupportsDynamicVersioning {
+		t.Error("Expected supports_dynamic_versioning to be overridden")
+	}
+	if python.Priority != 2 || len(python.Samples) != 1 {
+		t.Errorf("Expected unset keys to be kept, got %+v", python)
+	}
+	if js := cfg.Projects[2]; js.Priority != 10 || len(js.Regex) != 1 {
+		t.Errorf("Expected only the priority to change, got %+v", js)
+	}
+
+	if len(cfg.ConsistencyGroups) != 1 {
+		t.Fatalf("Expected 1 consistency group, got %+v", cfg.ConsistencyGroups)
+	}
+	if group := cfg.ConsistencyGroups[0]; !group.GitTag || len(group.Files) != 1 {
+		t.Errorf("Expected git_tag merged into the group, got %+v", group)
+	}
+
+	// A replacing layer discards the layers below it
+	replace := write("replace.yaml", `---
+replace: true
+projects:
+  - type: Custom
+    file: VERSION.txt
+    regex:
+      - '^(\S+)$'
+    samples:
+      - https://example.com/custom
+`)
+	cfg, err = LoadConfig(defaults, replace)
+	if err != nil {
+		t.Fatalf("Expected successful load, got error: %v", err)
+	}
+	if len(cfg.Projects) != 1 || cfg.Projects[0].Type != "Custom" || len(cfg.ConsistencyGroups) != 0 {
+		t.Errorf("Expected only the replacing layer, got %+v", cfg)
+	}
+
+	if _, err := LoadConfig(defaults, filepath.Join(tmpDir, "missing.yaml")); err == nil {
+		t.Error("Expected error for a missing layer, got nil")
+	}
+}
+
+func TestDiscoverConfig(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	nested := filepath.Join(repo, "services", "api")
+	for _, dir := range []string{filepath.Join(repo, ".git"), nested} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := filepath.Join(nested, "package.json")
+	if err := os.WriteFile(manifest, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file above the repository is not used
+	if err := os.WriteFile(filepath.Join(root, LocalConfigName), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := DiscoverConfig(nested); err != nil || found != "" {
+		t.Errorf("DiscoverConfig() = %q, %v; expected none", found, err)
+	}
+
+	expected := filepath.Join(repo, LocalConfigName)
+	if err := os.WriteFile(expected, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, start := range []string{nested, manifest, repo} {
+		if found, err := DiscoverConfig(start); err != nil || found != expected {
+			t.Errorf("DiscoverConfig(%s) = %q, %v; expected %s", start, found, err, expected)
+		}
+	}
+}
+
+func TestDiscoverConfigFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"services/api/package.json": {Data: []byte("{}")},
+	}
+	if found, err := DiscoverConfigFS(fsys, "services/api"); err != nil || found != "" {
+		t.Errorf("DiscoverConfigFS() = %q, %v; expected none", found, err)
+	}
+
+	fsys[LocalConfigName] = &fstest.MapFile{Data: []byte(`replace: true
+projects:
+  - type: Custom
+    file: VERSION
+    regex:
+      - '(\S+)'
+    samples:
+      - https://example.com/custom
+`)}
+	for _, start := range []string{"services/api", "services/api/package.json", "."} {
+		if found, err := DiscoverConfigFS(fsys, start); err != nil || found != LocalConfigName {
+			t.Errorf("DiscoverConfigFS(%s) = %q, %v; expected %s", start, found, err, LocalConfigName)
+		}
+	}
+
+	// The discovered file is read from the same file system
+	loader := NewLoader()
+	loader.SetFS(fsys)
+	cfg, err := loader.LoadDefault(LocalConfigName)
+	if err != nil {
+		t.Fatalf("Failed to load from the file system: %v", err)
+	}
+	if types := cfg.GetSupportedTypes(); len(types) != 1 || types[0] != "Custom" {
+		t.Errorf("Expected only the Custom project type, got %v", types)
+	}
+}
+
+func TestLoadDefaultConfig(t *testing.T) {
+	// The embedded defaults do not depend on the working directory
+	t.Chdir(t.TempDir())
+
+	cfg, err := LoadDefaultConfig()
+	if err != nil {
+		t.Fatalf("Expected the embedded defaults to load, got error: %v", err)
+	}
+	if cfg.GetProjectByType("JavaScript", "npm") == nil {
+		t.Error("Expected the defaults to include JavaScript (npm)")
+	}
+
+	overlay := filepath.Join(t.TempDir(), "overlay.yaml")
+	content := `---
+projects:
+  - type: JavaScript
+    subtype: npm
+    file: package.json
+    disabled: true
+`
+	if err := os.WriteFile(overlay, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	layered, err := LoadDefaultConfig(overlay)
+	if err != nil {
+		t.Fatalf("Expected successful load, got error: %v", err)
+	}
+	if layered.GetProjectByType("JavaScript", "npm") != nil {
+		t.Error("Expected JavaScript (npm) to be disabled")
+	}
+	if len(layered.Projects) != len(cfg.Projects)-1 {
+		t.Errorf("Expected %d projects, got %d", len(cfg.Projects)-1, len(layered.Projects))
+	}
+}
+
+func TestLoaderStrict(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "strict.yaml")
+	content := `---
+projects:
+  - type: Custom
+    file: VERSION.txt
+    regx:
+      - '(x)'
+    regex:
+      - '^(\S+'
+      - '^\S+$'
+      - '(\w+)-(\d+)'
+    samples:
+      - https://example.com/custom
+    dynamic_version_indicators:
+      - field: version
+      - exists: true
+      - path: "[tool.scm]"
+      - path: "[tool.scm]"
+        exists: true
+  - type: NoSamples
+    file: VERSION
+    regex:
+      - '(\S+)'
+unknown: true
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	// Leniently the problems are logged and the entries load
+	cfg, err := LoadConfig(configFile)
+	if err != nil {
+		t.Fatalf("Expected lenient load to succeed, got error: %v", err)
+	}
+	if len(cfg.Projects) != 2 {
+		t.Errorf("Expected 2 projects, got %d", len(cfg.Projects))
+	}
+
+	loader := NewLoader()
+	loader.SetStrict(true)
+	_, err = loader.Load(configFile)
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected a ValidationError, got: %v", err)
+	}
+
+	expected := []string{
+		`5:5: unknown key "regx"`,
+		"8:9: invalid regex",
+		"9:9: regex has 0 capture groups",
+		"10:9: regex has 2 capture groups",
+		"14:9: dynamic version indicator sets field without contains",
+		"15:9: dynamic version indicator sets exists without path",
+		"16:9: dynamic version indicator checks nothing",
+		`23:1: unknown key "unknown"`,
+	}
+	if len(invalid.Problems) != len(expected) {
+		t.Fatalf("Expected %d problems, got %d:\n%v", len(expected), len(invalid.Problems), err)
+	}
+	for i, problem := range invalid.Problems {
+		if !strings.HasPrefix(problem.String(), configFile+":"+expected[i]) {
+			t.Errorf("Problem %d: expected %s, got %s", i, expected[i], problem)
+		}
+	}
+}
+
+func TestLoaderLogger(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "lenient.yaml")
+	content := `---
+projects:
+  - type: Custom
+    file: VERSION
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	var logs bytes.Buffer
+	loader := NewLoader()
+	loader.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	if _, err := loader.Load(configFile); err == nil {
+		t.Fatal("Expected an error with no valid projects")
+	}
+
+	expected := `level=WARN msg="Project Custom missing regex patterns, skipping" ` +
+		`file=` + configFile + ` line=3 column=5`
+	if !strings.Contains(logs.String(), expected) {
+		t.Errorf("Expected the log to contain %s, got:\n%s", expected, logs.String())
+	}
+}
+
+func TestLoadDefaultConfigStrict(t *testing.T) {
+	loader := NewLoader()
+	loader.SetStrict(true)
+	if _, err := loader.LoadDefault(); err != nil {
+		t.Errorf("Expected the default patterns to pass strict validation, got: %v", err)
+	}
+}
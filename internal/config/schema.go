@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaID is where the published JSON Schema can be fetched from. Editors
+// that support the yaml-language-server modeline pick it up from a comment:
+//
+//	# yaml-language-server: $schema=<SchemaID>
+const SchemaID = "https://raw.githubusercontent.com/lfreleng-actions/" +
+	"version-extract-action/main/configs/version-extract.schema.json"
+
+// JSONSchema is the subset of JSON Schema (draft 2020-12) needed to describe
+// a configuration file
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	MinLength            int                    `json:"minLength,omitempty"`
+	MinItems             int                    `json:"minItems,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+}
+
+// fileLayout is what a configuration file holds: a Config, and the keys
+// that control how it is layered. It mirrors layer for the schema.
+type fileLayout struct {
+	Replace bool `yaml:"replace,omitempty" description:"Discard the layers below instead of merging with them"`
+	Config  `yaml:",inline"`
+}
+
+// Schema generates the JSON Schema for a configuration file from the
+// configuration types. Keys come from the yaml tags, descriptions from the
+// description tags, allowed values from enum tags ("a|b") and constraints
+// from the validate tags: "required" and "min=N", which sets the minimum
+// length of a string or array. The schema describes one layer, so it only
+// requires what every entry must set to be merged.
+func Schema() *JSONSchema {
+	b := &schemaBuilder{defs: make(map[string]*JSONSchema)}
+	root := b.object(reflect.TypeOf(fileLayout{}))
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	root.ID = SchemaID
+	root.Title = "version-extract configuration"
+	root.Description = "Version extraction patterns, layered over the built-in defaults"
+	root.Defs = b.defs
+	return root
+}
+
+// SchemaJSON returns the JSON Schema as indented JSON
+func SchemaJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(Schema(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// configSchema is the schema configuration files are checked against
+var configSchema = sync.OnceValue(Schema)
+
+// schemaBuilder generates schemas for types, collecting named structs as
+// definitions
+type schemaBuilder struct {
+	defs map[string]*JSONSchema
+}
+
+// typeSchema returns the schema for values of a type
+func (b *schemaBuilder) typeSchema(t reflect.Type) *JSONSchema {
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Slice:
+		return &JSONSchema{Type: "array", Items: b.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := b.defs[t.Name()]; !ok {
+			b.defs[t.Name()] = nil // Defined below; guards against recursion
+			def := b.object(t)
+			if t == reflect.TypeOf(ConsistencyMember{}) {
+				// UnmarshalYAML also accepts a plain file glob
+				def = &JSONSchema{OneOf: []*JSONSchema{{Type: "string", MinLength: 1}, def}}
+			}
+			b.defs[t.Name()] = def
+		}
+		return &JSONSchema{Ref: "#/$defs/" + t.Name()}
+	}
+	panic(fmt.Sprintf("config: no JSON Schema for %s", t))
+}
+
+// object returns the schema for a struct, which allows only its own keys
+func (b *schemaBuilder) object(t reflect.Type) *JSONSchema {
+	closed := false
+	s := &JSONSchema{
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: &closed,
+	}
+	b.fields(t, s)
+	return s
+}
+
+// fields adds a struct's fields to an object schema, including those of
+// inlined structs
+func (b *schemaBuilder) fields(t reflect.Type, s *JSONSchema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if options == "inline" {
+			b.fields(field.Type, s)
+			continue
+		}
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		property := b.typeSchema(field.Type)
+		property.Description = field.Tag.Get("description")
+		if enum := field.Tag.Get("enum"); enum != "" {
+			property.Enum = strings.Split(enum, "|")
+		}
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			switch {
+			case rule == "required":
+				s.Required = append(s.Required, name)
+				if property.Type == "string" {
+					property.MinLength = 1
+				}
+			case strings.HasPrefix(rule, "min="):
+				n, err := strconv.Atoi(strings.TrimPrefix(rule, "min="))
+				if err != nil {
+					panic(fmt.Sprintf("config: invalid validate rule %q on %s.%s", rule, t.Name(), field.Name))
+				}
+				if property.Type == "array" {
+					property.MinItems = n
+				} else {
+					property.MinLength = n
+				}
+			}
+		}
+		s.Properties[name] = property
+	}
+}
+
+// nodeType names the JSON Schema type of a YAML node
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.ShortTag() {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+	return "string"
+}
+
+// checkSchema reports where a YAML node does not conform to a schema, with
+// definitions resolved against root
+func (v *validator) checkSchema(file string, root, s *JSONSchema, node *yaml.Node) {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if s.Ref != "" {
+		s = root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+	}
+
+	if len(s.OneOf) > 0 {
+		var types []string
+		for _, alternative := range s.OneOf {
+			if alternative.Type == nodeType(node) {
+				v.checkSchema(file, root, alternative, node)
+				return
+			}
+			types = append(types, alternative.Type)
+		}
+		v.report(at(file, node), "expected %s, found %s",
+			strings.Join(types, " or "), nodeType(node))
+		return
+	}
+	if nodeType(node) != s.Type {
+		v.report(at(file, node), "expected %s, found %s", s.Type, nodeType(node))
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			property, ok := s.Properties[key.Value]
+			if !ok {
+				v.report(at(file, key), "unknown key %q", key.Value)
+				continue
+			}
+			seen[key.Value] = true
+			v.checkSchema(file, root, property, node.Content[i+1])
+		}
+		for _, name := range s.Required {
+			if !seen[name] {
+				v.report(at(file, node), "missing required key %q", name)
+			}
+		}
+	case "array":
+		if len(node.Content) < s.MinItems {
+			v.report(at(file, node), "expected at least %d item(s), found %d",
+				s.MinItems, len(node.Content))
+		}
+		for _, item := range node.Content {
+			v.checkSchema(file, root, s.Items, item)
+		}
+	case "string":
+		if len(node.Value) < s.MinLength {
+			v.report(at(file, node), "expected at least %d character(s)", s.MinLength)
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, node.Value) {
+			v.report(at(file, node), "%q is not one of: %s", node.Value,
+				strings.Join(s.Enum, ", "))
+		}
+	}
+}
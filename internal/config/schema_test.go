@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+
+	for _, key := range []string{"replace", "projects", "consistency_groups"} {
+		if schema.Properties[key] == nil {
+			t.Errorf("Expected top-level key %q", key)
+		}
+	}
+	if schema.AdditionalProperties == nil || *schema.AdditionalProperties {
+		t.Error("Expected unknown top-level keys to be disallowed")
+	}
+
+	project := schema.Defs["ProjectConfig"]
+	if project == nil {
+		t.Fatal("Expected a ProjectConfig definition")
+	}
+	if !slices.Equal(project.Required, []string{"type", "file"}) {
+		t.Errorf("Expected type and file to be required, got %v", project.Required)
+	}
+	if got := project.Properties["fallback_strategy"].Enum; !slices.Equal(got, []string{"git-tags"}) {
+		t.Errorf("Expected fallback_strategy enum [git-tags], got %v", got)
+	}
+	if got := project.Properties["samples"].MinItems; got != 1 {
+		t.Errorf("Expected samples minItems 1, got %d", got)
+	}
+	indicators := project.Properties["dynamic_version_indicators"]
+	if indicators.Items == nil || indicators.Items.Ref != "#/$defs/DynamicVersionIndicator" {
+		t.Errorf("Expected dynamic_version_indicators to reference its definition, got %+v", indicators.Items)
+	}
+	for name, property := range project.Properties {
+		if property.Description == "" {
+			t.Errorf("Expected a description for %s", name)
+		}
+	}
+
+	member := schema.Defs["ConsistencyMember"]
+	if member == nil || len(member.OneOf) != 2 || member.OneOf[0].Type != "string" {
+		t.Errorf("Expected ConsistencyMember to accept a string or an object, got %+v", member)
+	}
+}
+
+func TestSchemaPublished(t *testing.T) {
+	published, err := os.ReadFile(filepath.Join("..", "..", "configs", "version-extract.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to read the published schema: %v", err)
+	}
+	generated, err := SchemaJSON()
+	if err != nil {
+		t.Fatalf("Failed to generate the schema: %v", err)
+	}
+	if !bytes.Equal(published, generated) {
+		t.Error("configs/version-extract.schema.json is out of date; run make schema")
+	}
+}
+
+func TestLoaderSchema(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "schema.yaml")
+	content := `---
+replace: true
+projects:
+  - type: Custom
+    file: VERSION.txt
+    regex:
+      - '(\S+)'
+    samples:
+      - https://example.com/custom
+    priority: high
+    fallback_strategy: git-branches
+  - subtype: Untyped
+    file: ""
+    regex: '(\S+)'
+consistency_groups:
+  - name: release
+    files:
+      - VERSION.txt
+      - file: Chart.yaml
+        path: "$.appVersion"
+      - 1.0
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	loader := NewLoader()
+	loader.SetStrict(true)
+	_, err := loader.Load(configFile)
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected a ValidationError, got: %v", err)
+	}
+
+	expected := []string{
+		"10:15: expected integer, found string",
+		`11:24: "git-branches" is not one of: git-tags`,
+		`12:5: missing required key "type"`,
+		"13:11: expected at least 1 character(s)",
+		"14:12: expected array, found string",
+		"21:9: expected string or object, found number",
+	}
+	if len(invalid.Problems) != len(expected) {
+		t.Fatalf("Expected %d problems, got %d:\n%v", len(expected), len(invalid.Problems), err)
+	}
+	for i, problem := range invalid.Problems {
+		if !strings.HasPrefix(problem.String(), configFile+":"+expected[i]) {
+			t.Errorf("Problem %d: expected %s, got %s", i, expected[i], problem)
+		}
+	}
+}
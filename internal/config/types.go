@@ -5,134 +5,130 @@ package config
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/version"
 )
 
-// DynamicVersionIndicator represents a condition to detect dynamic versioning
+// DynamicVersionIndicator represents a condition to detect dynamic versioning.
+// The description tags here and on the other configuration types are
+// published in the JSON Schema; see Schema.
 type DynamicVersionIndicator struct {
-	Path     string   `yaml:"path,omitempty"`     // TOML section like "[project]"
-	Field    string   `yaml:"field,omitempty"`    // Field name like "dynamic"
-	Contains []string `yaml:"contains,omitempty"` // Values that indicate dynamic versioning
-	Exists   bool     `yaml:"exists,omitempty"`   // True if section/field existence indicates dynamic
+	Path     string   `yaml:"path,omitempty" description:"Section whose presence indicates dynamic versioning, e.g. [tool.setuptools_scm]"`
+	Field    string   `yaml:"field,omitempty" description:"Field whose value is checked against contains, e.g. dynamic"`
+	Contains []string `yaml:"contains,omitempty" description:"Values of field that indicate dynamic versioning"`
+	Exists   bool     `yaml:"exists,omitempty" description:"Whether the existence of the path section indicates dynamic versioning"`
 }
 
-// ProjectConfig represents a single project type configuration
+// ProjectConfig represents a single project type configuration. Only type
+// and file are required in every file: an entry overriding an earlier layer
+// inherits the rest, and validateConfig checks the merged result.
 type ProjectConfig struct {
-	Type    string `yaml:"type" validate:"required"`
-	Subtype string `yaml:"subtype,omitempty"`
-	File    string `yaml:"file" validate:"required"`
+	Type    string `yaml:"type" validate:"required" description:"Project type, e.g. JavaScript"`
+	Subtype string `yaml:"subtype,omitempty" description:"Variant of the project type, e.g. npm"`
+	File    string `yaml:"file" validate:"required" description:"File name or glob the version is read from"`
 	// Regex patterns for version extraction. No struct validation tags because
 	// empty arrays are allowed for projects with SupportsDynamicVersioning=true
 	// (e.g., Go projects that use git tags). Runtime validation in validateConfig()
-	// enforces that non-dynamic projects must have at least one regex pattern.
-	Regex                     []string                  `yaml:"regex"`
-	Samples                   []string                  `yaml:"samples" validate:"required,min=1"`
-	Priority                  int                       `yaml:"priority,omitempty"`
-	Notes                     string                    `yaml:"notes,omitempty"`
-	SupportsDynamicVersioning bool                      `yaml:"supports_dynamic_versioning,omitempty"`
-	DynamicVersionIndicators  []DynamicVersionIndicator `yaml:"dynamic_version_indicators,omitempty"`
-	FallbackStrategy          string                    `yaml:"fallback_strategy,omitempty"`
-}
-
-// Config represents the complete configuration structure
-type Config struct {
-	Projects []ProjectConfig `yaml:"projects" validate:"required,min=1"`
+	// enforces that non-dynamic projects must have at least one regex pattern
+	// or a structured lookup.
+	Regex []string `yaml:"regex" description:"Patterns tried in order, each with exactly one capture group for the version"`
+	// Structured lookups evaluated against the parsed document before the
+	// regex patterns, which avoids false matches such as a dependency's
+	// "version" key. At most one may be set.
+	Path                      string                    `yaml:"path,omitempty" description:"JSON or YAML path of the version, e.g. $.version"`
+	XPath                     string                    `yaml:"xpath,omitempty" description:"XML element path of the version, e.g. /project/version"`
+	TomlKey                   string                    `yaml:"toml_key,omitempty" description:"Dotted TOML key of the version, e.g. package.version"`
+	Samples                   []string                  `yaml:"samples" validate:"min=1" description:"Repositories using this project type, for integration testing"`
+	Priority                  int                       `yaml:"priority,omitempty" description:"Order in which project types are tried, lowest first"`
+	Notes                     string                    `yaml:"notes,omitempty" description:"Free-form notes"`
+	SupportsDynamicVersioning bool                      `yaml:"supports_dynamic_versioning,omitempty" description:"Whether the version may be set at build time, so the git tag fallback applies"`
+	DynamicVersionIndicators  []DynamicVersionIndicator `yaml:"dynamic_version_indicators,omitempty" description:"Conditions under which the file uses dynamic versioning"`
+	FallbackStrategy          string                    `yaml:"fallback_strategy,omitempty" enum:"git-tags" description:"Where to find the version when the file uses dynamic versioning"`
+	// TagPrefix restricts the git tag fallback to tags starting with this
+	// prefix, e.g. "api/" or "web-". It may be a template over the project
+	// directory relative to the repository root: "{{.Path}}/" or
+	// "charts/{{.Name}}-".
+	TagPrefix string `yaml:"tag_prefix,omitempty" description:"Prefix of the git tags used by the fallback; may be a template such as {{.Path}}/"`
+	// VersionScheme validates versions matched for this project type: one
+	// of semver, pep440, maven, calver, debian, rpm or loose, "calver:FORMAT"
+	// with a calver.org format such as "YYYY.0M.MICRO", or "regex:PATTERN".
+	// When unset, any version in a commonly used form is accepted.
+	VersionScheme string `yaml:"version_scheme,omitempty" description:"Scheme versions must follow: semver, pep440, maven, calver, debian, rpm, loose, calver:FORMAT or regex:PATTERN"`
+	// Disabled removes the project type, so a layered configuration can
+	// turn off one of the defaults
+	Disabled bool `yaml:"disabled,omitempty" description:"Remove this project type, defined in an earlier layer"`
 }
 
-// LoadConfig loads and validates configuration from a YAML file
-func LoadConfig(configPath string) (*Config, error) {
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file not found: %s", configPath)
-	}
-
-	// Read config file
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	// Parse YAML
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML config: %w", err)
+// SchemeRule returns the rule for the project's version scheme, or nil when
+// none is set
+func (p *ProjectConfig) SchemeRule() (*version.SchemeRule, error) {
+	if p.VersionScheme == "" {
+		return nil, nil
 	}
-
-	if err := validateConfig(&config); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
-	}
-
-	// Sort projects by priority
-	sortProjectsByPriority(&config)
-
-	return &config, nil
+	return version.ParseSchemeRule(p.VersionScheme)
 }
 
-// validateConfig performs basic validation on the configuration
-func validateConfig(config *Config) error {
-	if len(config.Projects) == 0 {
-		return fmt.Errorf("no projects defined in configuration")
-	}
-
-	seenTypes := make(map[string]bool)
-	validProjects := []ProjectConfig{}
-
-	for i, project := range config.Projects {
-		// Basic field validation
-		if project.Type == "" {
-			fmt.Fprintf(os.Stderr, "Warning: Project at index %d missing type, "+
-				"skipping\n", i)
-			continue
-		}
-		if project.File == "" {
-			fmt.Fprintf(os.Stderr, "Warning: Project %s missing file pattern, "+
-				"skipping\n", project.Type)
-			continue
-		}
-		if len(project.Regex) == 0 {
-			// Allow empty regex for projects that support dynamic versioning
-			// (e.g., Go projects that rely on git tags)
-			if !project.SupportsDynamicVersioning {
-				fmt.Fprintf(os.Stderr, "Warning: Project %s missing regex patterns, "+
-					"skipping\n", project.Type)
-				continue
-			}
-		}
-		if len(project.Samples) == 0 {
-			fmt.Fprintf(os.Stderr, "Warning: Project %s missing sample URLs, "+
-				"skipping\n", project.Type)
-			continue
-		}
+// HasStructuredLookup reports whether the project declares a parsed-document
+// lookup (path, xpath or toml_key)
+func (p *ProjectConfig) HasStructuredLookup() bool {
+	return p.structuredLookupCount() > 0
+}
 
-		key := fmt.Sprintf("%s-%s-%s", project.Type, project.Subtype,
-			project.File)
-		if seenTypes[key] {
-			fmt.Fprintf(os.Stderr, "Warning: Duplicate project config for %s, "+
-				"skipping\n", key)
-			continue
+// structuredLookupCount returns how many structured lookups are declared
+func (p *ProjectConfig) structuredLookupCount() int {
+	count := 0
+	for _, lookup := range []string{p.Path, p.XPath, p.TomlKey} {
+		if lookup != "" {
+			count++
 		}
-		seenTypes[key] = true
+	}
+	return count
+}
 
-		// Set default priority if not specified
-		if project.Priority == 0 {
-			project.Priority = i + 1
-		}
+// ConsistencyMember selects files whose versions must agree, for the check
+// command. File is a glob matched against the path relative to the search
+// root, or against the base name when it has no "/". An optional structured
+// lookup reads a different value than the file's project type would, e.g.
+// appVersion rather than version in Chart.yaml. A plain string is shorthand
+// for a member with only File set.
+type ConsistencyMember struct {
+	File    string `yaml:"file" validate:"required" description:"Glob matched against the path relative to the search root, or the base name"`
+	Path    string `yaml:"path,omitempty" description:"JSON or YAML path of the version"`
+	XPath   string `yaml:"xpath,omitempty" description:"XML element path of the version"`
+	TomlKey string `yaml:"toml_key,omitempty" description:"Dotted TOML key of the version"`
+}
 
-		validProjects = append(validProjects, project)
+// UnmarshalYAML accepts either a mapping or a plain file glob
+func (m *ConsistencyMember) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		m.File = node.Value
+		return nil
 	}
+	type plain ConsistencyMember
+	return node.Decode((*plain)(m))
+}
 
-	// Update config with valid projects only
-	config.Projects = validProjects
+// Lookup returns the member's structured lookup as a ProjectConfig, so it
+// can be evaluated like a project type's lookup
+func (m *ConsistencyMember) Lookup() *ProjectConfig {
+	return &ProjectConfig{File: m.File, Path: m.Path, XPath: m.XPath, TomlKey: m.TomlKey}
+}
 
-	if len(config.Projects) == 0 {
-		return fmt.Errorf("no valid projects after validation")
-	}
+// ConsistencyGroup names a set of files whose versions must agree
+type ConsistencyGroup struct {
+	Name   string              `yaml:"name" validate:"required" description:"Name of the group"`
+	Files  []ConsistencyMember `yaml:"files" validate:"min=1" description:"Files whose versions must agree; a string is shorthand for {file: ...}"`
+	GitTag bool                `yaml:"git_tag,omitempty" description:"Also compare with the latest git tag"`
+}
 
-	return nil
+// Config represents the complete configuration structure
+type Config struct {
+	Projects          []ProjectConfig    `yaml:"projects" validate:"min=1" description:"Project types, tried in priority order"`
+	ConsistencyGroups []ConsistencyGroup `yaml:"consistency_groups,omitempty" description:"Sets of files whose versions must agree, for the check command"`
 }
 
 // sortProjectsByPriority sorts projects by priority (lower number = higher
@@ -143,7 +139,8 @@ func sortProjectsByPriority(config *Config) {
 	})
 }
 
-// GetDefaultConfigPath returns the default configuration file path
+// GetDefaultConfigPath returns the path of the default configuration file in
+// the source tree. The binary embeds it; see LoadDefaultConfig.
 func GetDefaultConfigPath() string {
 	return filepath.Join("configs", "default-patterns.yaml")
 }
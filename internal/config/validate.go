@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is an issue found in a configuration file, with the line and
+// column it was found at when known
+type Problem struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// String formats the problem as file:line:column: message
+func (p Problem) String() string {
+	if p.File == "" {
+		return p.Message
+	}
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Message)
+}
+
+// ValidationError lists every problem strict loading found
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Error lists the problems, one per line
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("%d configuration problem(s)", len(e.Problems)))
+	for _, problem := range e.Problems {
+		lines = append(lines, problem.String())
+	}
+	return strings.Join(lines, "\n  ")
+}
+
+// origin is where an entry was defined
+type origin struct {
+	file         string
+	line, column int
+}
+
+// at returns the origin of a node in a file
+func at(file string, node *yaml.Node) origin {
+	return origin{file: file, line: node.Line, column: node.Column}
+}
+
+// attrs returns the origin as logging attributes, omitting what is unknown
+func (o origin) attrs() []any {
+	var attrs []any
+	if o.file != "" {
+		attrs = append(attrs, "file", o.file)
+	}
+	if o.line > 0 {
+		attrs = append(attrs, "line", o.line, "column", o.column)
+	}
+	return attrs
+}
+
+// validator checks a merged configuration. Leniently, it logs a warning
+// about each entry that cannot be used and drops it; strictly, it records a
+// Problem instead.
+type validator struct {
+	strict   bool
+	logger   *slog.Logger
+	problems []Problem
+	projects []origin // Where each project was defined, when known
+	groups   []origin // Where each consistency group was defined, when known
+}
+
+// report records a problem at an origin
+func (v *validator) report(o origin, format string, args ...any) {
+	v.problems = append(v.problems, Problem{
+		File: o.file, Line: o.line, Column: o.column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// drop reports an entry that is skipped, with the error that caused it
+// when there is one
+func (v *validator) drop(o origin, err error, format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if v.strict {
+		if err != nil {
+			message += ": " + err.Error()
+		}
+		v.report(o, "%s", message)
+		return
+	}
+	attrs := o.attrs()
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	v.logger.Warn(message+", skipping", attrs...)
+}
+
+// originOf returns the i'th entry's origin, or none when it is unknown
+func originOf(origins []origin, i int) origin {
+	if i < len(origins) {
+		return origins[i]
+	}
+	return origin{}
+}
+
+// validateConfig performs basic validation on the configuration, logging
+// the entries it drops to slog.Default
+func validateConfig(config *Config) error {
+	return (&validator{logger: slog.Default()}).validate(config)
+}
+
+// validate drops the projects and consistency groups that cannot be used
+func (v *validator) validate(config *Config) error {
+	if len(config.Projects) == 0 {
+		return fmt.Errorf("no projects defined in configuration")
+	}
+
+	seenTypes := make(map[string]bool)
+	validProjects := []ProjectConfig{}
+
+	for i, project := range config.Projects {
+		o := originOf(v.projects, i)
+
+		// Basic field validation
+		if project.Type == "" {
+			v.drop(o, nil, "Project at index %d missing type", i)
+			continue
+		}
+		if project.File == "" {
+			v.drop(o, nil, "Project %s missing file pattern", project.Type)
+			continue
+		}
+		if project.structuredLookupCount() > 1 {
+			v.drop(o, nil, "Project %s sets more than one of path, xpath and toml_key",
+				project.Type)
+			continue
+		}
+		if len(project.Regex) == 0 {
+			// Allow empty regex for projects that support dynamic versioning
+			// (e.g., Go projects that rely on git tags) or that declare a
+			// structured lookup instead
+			if !project.SupportsDynamicVersioning && !project.HasStructuredLookup() {
+				v.drop(o, nil, "Project %s missing regex patterns", project.Type)
+				continue
+			}
+		}
+		if project.TagPrefix != "" {
+			if _, err := template.New("tag_prefix").Parse(project.TagPrefix); err != nil {
+				v.drop(o, err, "Project %s has an invalid tag_prefix template", project.Type)
+				continue
+			}
+		}
+		if _, err := project.SchemeRule(); err != nil {
+			v.drop(o, err, "Project %s has an invalid version_scheme", project.Type)
+			continue
+		}
+		if len(project.Samples) == 0 && o.file == DefaultConfigName {
+			// The embedded patterns document where each was seen; types
+			// added by other layers need not
+			v.drop(o, nil, "Project %s missing sample URLs", project.Type)
+			continue
+		}
+
+		key := fmt.Sprintf("%s-%s-%s", project.Type, project.Subtype,
+			project.File)
+		if seenTypes[key] {
+			v.drop(o, nil, "Duplicate project config for %s", key)
+			continue
+		}
+		seenTypes[key] = true
+
+		// Set default priority if not specified
+		if project.Priority == 0 {
+			project.Priority = i + 1
+		}
+
+		validProjects = append(validProjects, project)
+	}
+
+	// Update config with valid projects only
+	config.Projects = validProjects
+
+	if len(config.Projects) == 0 {
+		return fmt.Errorf("no valid projects after validation")
+	}
+
+	config.ConsistencyGroups = v.validGroups(config.ConsistencyGroups)
+
+	return nil
+}
+
+// validGroups drops consistency groups that cannot be evaluated
+func (v *validator) validGroups(groups []ConsistencyGroup) []ConsistencyGroup {
+	var valid []ConsistencyGroup
+	seen := make(map[string]bool)
+
+	for i, group := range groups {
+		o := originOf(v.groups, i)
+		if group.Name == "" {
+			v.drop(o, nil, "Consistency group at index %d missing name", i)
+			continue
+		}
+		if seen[group.Name] {
+			v.drop(o, nil, "Duplicate consistency group %s", group.Name)
+			continue
+		}
+		if len(group.Files) == 0 {
+			v.drop(o, nil, "Consistency group %s has no files", group.Name)
+			continue
+		}
+
+		ok := true
+		for _, member := range group.Files {
+			if _, err := path.Match(member.File, ""); member.File == "" || err != nil {
+				v.drop(o, nil, "Consistency group %s has an invalid file pattern %q",
+					group.Name, member.File)
+				ok = false
+				break
+			}
+			if member.Lookup().structuredLookupCount() > 1 {
+				v.drop(o, nil, "Consistency group %s sets more than one of path, "+
+					"xpath and toml_key for %s", group.Name, member.File)
+				ok = false
+				break
+			}
+		}
+		if ok {
+			seen[group.Name] = true
+			valid = append(valid, group)
+		}
+	}
+
+	return valid
+}
+
+// checkDocument checks a configuration file as written, before it is
+// merged: it must conform to the JSON Schema, every regex must compile with
+// exactly one capture group for the version, and every dynamic version
+// indicator must be able to match
+func (v *validator) checkDocument(file string, doc *yaml.Node) {
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			return
+		}
+		doc = doc.Content[0]
+	}
+
+	// List the file's problems in the order they appear
+	start := len(v.problems)
+	defer func() {
+		found := v.problems[start:]
+		sort.SliceStable(found, func(i, j int) bool {
+			if found[i].Line != found[j].Line {
+				return found[i].Line < found[j].Line
+			}
+			return found[i].Column < found[j].Column
+		})
+	}()
+
+	schema := configSchema()
+	v.checkSchema(file, schema, schema, doc)
+	if doc.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if value := doc.Content[i+1]; doc.Content[i].Value == "projects" &&
+			value.Kind == yaml.SequenceNode {
+			for _, entry := range value.Content {
+				v.checkProject(file, entry)
+			}
+		}
+	}
+}
+
+// checkProject checks a project entry's regex patterns and dynamic version
+// indicators
+func (v *validator) checkProject(file string, entry *yaml.Node) {
+	if entry.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(entry.Content); i += 2 {
+		value := entry.Content[i+1]
+		switch entry.Content[i].Value {
+		case "regex":
+			if value.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, pattern := range value.Content {
+				re, err := regexp.Compile(pattern.Value)
+				switch {
+				case err != nil:
+					v.report(at(file, pattern), "invalid regex: %v", err)
+				case re.NumSubexp() != 1:
+					v.report(at(file, pattern), "regex has %d capture groups, "+
+						"expected exactly 1 for the version", re.NumSubexp())
+				}
+			}
+		case "dynamic_version_indicators":
+			if value.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, item := range value.Content {
+				var indicator DynamicVersionIndicator
+				if err := item.Decode(&indicator); err != nil {
+					continue
+				}
+				if message := indicatorProblem(indicator); message != "" {
+					v.report(at(file, item), "%s", message)
+				}
+			}
+		}
+	}
+}
+
+// indicatorProblem describes why a dynamic version indicator is malformed,
+// or returns "" when it is not. An indicator matches when the section path
+// exists, with exists set, or when field holds one of the contains values.
+func indicatorProblem(indicator DynamicVersionIndicator) string {
+	switch {
+	case indicator.Exists && indicator.Path == "":
+		return "dynamic version indicator sets exists without path"
+	case len(indicator.Contains) > 0 && indicator.Field == "":
+		return "dynamic version indicator sets contains without field"
+	case indicator.Field != "" && len(indicator.Contains) == 0:
+		return "dynamic version indicator sets field without contains"
+	case !indicator.Exists && indicator.Field == "":
+		return "dynamic version indicator checks nothing: set path with " +
+			"exists, or field with contains"
+	}
+	for _, value := range indicator.Contains {
+		if value == "" {
+			return "dynamic version indicator has an empty contains value"
+		}
+	}
+	return ""
+}
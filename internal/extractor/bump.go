@@ -0,0 +1,628 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// Bump levels accepted by BumpOptions.Level
+const (
+	BumpMajor      = "major"
+	BumpMinor      = "minor"
+	BumpPatch      = "patch"
+	BumpPrerelease = "prerelease"
+)
+
+// bumpVersionPattern splits a version into its numeric core, an optional
+// pre-release and optional build metadata. Up to four numeric components are
+// accepted, mirroring simplePattern.
+var bumpVersionPattern = regexp.MustCompile(
+	`^([0-9]+(?:\.[0-9]+){0,3})(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// BumpOptions controls how Bump computes and applies the new version
+type BumpOptions struct {
+	Level  string // major, minor, patch or prerelease; ignored if Set is given
+	Set    string // explicit replacement version
+	Preid  string // pre-release identifier used by prerelease bumps (e.g. "rc")
+	DryRun bool   // compute the change and diff without writing
+}
+
+// BumpResult describes a version rewrite performed (or planned) by Bump
+type BumpResult struct {
+	OldVersion  string `json:"old_version"`
+	NewVersion  string `json:"new_version"`
+	ProjectType string `json:"project_type"`
+	Subtype     string `json:"subtype,omitempty"`
+	File        string `json:"file"`
+	MatchedBy   string `json:"matched_by"`
+	DryRun      bool   `json:"dry_run"`
+	Diff        string `json:"diff,omitempty"`
+}
+
+// versionSpan locates the version text inside a file's raw content, as byte
+// offsets, so it can be replaced without disturbing the surrounding
+// formatting, quoting or line endings.
+type versionSpan struct {
+	file       string
+	start, end int
+}
+
+// Bump extracts the version from path and rewrites it in place. The new
+// version is either opts.Set or the current version bumped by opts.Level.
+//
+// Only the captured version text is replaced, so prefixes such as "v",
+// quoting and line endings are preserved. Versions from git tags cannot be
+// rewritten; versions resolved from a Kotlin constant are rewritten in the
+// constant's definition file.
+func (e *VersionExtractor) Bump(path string, opts BumpOptions) (*BumpResult, error) {
+	e, path, err := e.begin(path)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.Extract(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Constant definitions are searched from the same root extraction used.
+	searchRoot := path
+	if info, statErr := e.stat(path); statErr == nil && !info.IsDir() {
+		searchRoot = e.projectRootForFile(path)
+	}
+
+	valid := e.validatorFor(e.projectForResult(result), result.File)
+	newVersion, err := e.nextVersion(result.Version, opts, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	span, err := e.locateVersionSpan(result, searchRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read raw, un-normalised content: spans index into it directly.
+	raw, err := e.files().ReadFileContent(span.file, false)
+	if err != nil {
+		return nil, err
+	}
+	updated := raw[:span.start] + newVersion + raw[span.end:]
+
+	bumpResult := &BumpResult{
+		OldVersion:  result.Version,
+		NewVersion:  newVersion,
+		ProjectType: result.ProjectType,
+		Subtype:     result.Subtype,
+		File:        span.file,
+		MatchedBy:   result.MatchedBy,
+		DryRun:      opts.DryRun,
+		Diff:        unifiedDiff(span.file, raw, updated, span.start, span.end),
+	}
+
+	if opts.DryRun {
+		return bumpResult, nil
+	}
+	if !e.onHost() {
+		return nil, fmt.Errorf("cannot write %s: bumping is %w", span.file, errNoHostFS)
+	}
+
+	info, err := e.stat(span.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if err := os.WriteFile(span.file, []byte(updated), info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return bumpResult, nil
+}
+
+// nextVersion computes the replacement version for a bump. An explicit
+// version must pass valid, the project's version scheme.
+func (e *VersionExtractor) nextVersion(current string, opts BumpOptions,
+	valid versionValidator) (string, error) {
+	if opts.Set != "" {
+		clean := e.cleanVersion(opts.Set)
+		if !valid(clean) {
+			return "", fmt.Errorf("invalid version: %s", opts.Set)
+		}
+		return clean, nil
+	}
+
+	m := bumpVersionPattern.FindStringSubmatch(current)
+	if m == nil {
+		return "", fmt.Errorf("cannot bump version %s: not a numeric version", current)
+	}
+	parts := strings.Split(m[1], ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return "", fmt.Errorf("cannot bump version %s: %w", current, err)
+		}
+		nums[i] = n
+	}
+	prerelease := m[2]
+
+	// pad extends the core to at least n components before bumping index n-1.
+	pad := func(n int) {
+		for len(nums) < n {
+			nums = append(nums, 0)
+		}
+	}
+	bumpAt := func(i int) {
+		pad(i + 1)
+		nums[i]++
+		for j := i + 1; j < len(nums); j++ {
+			nums[j] = 0
+		}
+	}
+
+	switch opts.Level {
+	case BumpMajor:
+		bumpAt(0)
+		prerelease = ""
+	case BumpMinor:
+		bumpAt(1)
+		prerelease = ""
+	case BumpPatch:
+		// A pre-release of the same patch is released as-is (1.2.3-rc.1 ->
+		// 1.2.3), matching npm and other semver tooling.
+		if prerelease == "" {
+			bumpAt(2)
+		} else {
+			pad(3)
+		}
+		prerelease = ""
+	case BumpPrerelease:
+		if prerelease == "" {
+			bumpAt(2)
+			prerelease = "0"
+			if opts.Preid != "" {
+				prerelease = opts.Preid + ".0"
+			}
+		} else {
+			prerelease = nextPrerelease(prerelease, opts.Preid)
+		}
+	default:
+		return "", fmt.Errorf("unknown bump level %q (expected major, minor, "+
+			"patch or prerelease)", opts.Level)
+	}
+
+	strs := make([]string, len(nums))
+	for i, n := range nums {
+		strs[i] = strconv.Itoa(n)
+	}
+	next := strings.Join(strs, ".")
+	if prerelease != "" {
+		next += "-" + prerelease
+	}
+	return next, nil
+}
+
+// nextPrerelease increments the trailing numeric identifier of a pre-release
+// (rc.1 -> rc.2), appends ".0" when there is none, and restarts the counter
+// when a different preid is requested.
+func nextPrerelease(prerelease, preid string) string {
+	ids := strings.Split(prerelease, ".")
+	if preid != "" && ids[0] != preid {
+		return preid + ".0"
+	}
+	last := ids[len(ids)-1]
+	if n, err := strconv.Atoi(last); err == nil {
+		ids[len(ids)-1] = strconv.Itoa(n + 1)
+		return strings.Join(ids, ".")
+	}
+	return prerelease + ".0"
+}
+
+// locateVersionSpan finds the exact text that produced result.Version. It
+// replays the match that extraction made (the same regex and capture group,
+// or the pyproject/constant handlers) against the raw file content.
+func (e *VersionExtractor) locateVersionSpan(result *ExtractResult,
+	searchRoot string) (*versionSpan, error) {
+	switch result.VersionSource {
+	case "static":
+	case "static-constant":
+		return e.locateConstantSpan(result, searchRoot)
+	case "static-property":
+		if filepath.Base(result.File) == "pom.xml" {
+			return e.locateMavenSpan(result)
+		}
+		return e.locateMSBuildSpan(result)
+	case "dynamic-git-tag":
+		return nil, fmt.Errorf("version %s comes from git tag %s; "+
+			"refusing to rewrite %s", result.Version, result.GitTag,
+			result.File)
+	default:
+		return nil, fmt.Errorf("cannot rewrite version from source %q",
+			result.VersionSource)
+	}
+
+	if project := e.projectForResult(result); project != nil &&
+		project.HasStructuredLookup() &&
+		result.MatchedBy == structuredMatchedBy(project) {
+		return e.locateStructuredSpan(result, project)
+	}
+
+	if filepath.Base(result.File) == "pyproject.toml" {
+		switch result.MatchedBy {
+		case "[project] section version":
+			return e.locatePyprojectSpan(result.File, result.Version)
+		case "__version__.py":
+			_, versionFile := e.findDunderVersionFile(result.File,
+				e.validatorFor(e.projectForResult(result), result.File))
+			if versionFile == "" {
+				return nil, fmt.Errorf("cannot locate __version__.py for %s",
+					result.File)
+			}
+			return e.locatePatternSpan(versionFile, dunderVersionPatterns[0],
+				result.Version)
+		}
+	}
+
+	if filepath.Base(result.File) == "Cargo.toml" {
+		if span, ok, err := e.locateCargoSpan(result); ok {
+			return span, err
+		}
+	}
+
+	return e.locatePatternSpan(result.File, result.MatchedBy, result.Version)
+}
+
+// locatePatternSpan applies pattern to filePath the way extraction does and
+// returns the span of the first capture that cleans to version.
+func (e *VersionExtractor) locatePatternSpan(filePath, pattern,
+	version string) (*versionSpan, error) {
+	re, err := getCompiledRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := e.files().ReadFileContent(filePath, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.isMultiLinePattern(pattern) {
+		for _, m := range re.FindAllStringSubmatchIndex(raw, -1) {
+			if span := e.captureSpan(raw, m, version); span != nil {
+				span.file = filePath
+				return span, nil
+			}
+		}
+	} else {
+		// Match per line, like extractWithLineByLine, but keep byte offsets.
+		offset := 0
+		for _, line := range strings.SplitAfter(raw, "\n") {
+			text := strings.TrimRight(line, "\r\n")
+			if m := re.FindStringSubmatchIndex(text); m != nil {
+				if span := e.captureSpan(text, m, version); span != nil {
+					span.file = filePath
+					span.start += offset
+					span.end += offset
+					return span, nil
+				}
+			}
+			offset += len(line)
+		}
+	}
+
+	return nil, fmt.Errorf("cannot locate version %s in %s using pattern %s",
+		version, filePath, pattern)
+}
+
+// locateStructuredSpan re-evaluates a project's structured lookup and
+// returns the span of the value it found.
+func (e *VersionExtractor) locateStructuredSpan(result *ExtractResult,
+	project *config.ProjectConfig) (*versionSpan, error) {
+	found, err := e.lookupStructured(result.File, project)
+	if err != nil {
+		return nil, err
+	}
+	if found.start >= 0 {
+		m := []int{found.start, found.end, found.start, found.end}
+		raw, err := e.files().ReadFileContent(result.File, false)
+		if err != nil {
+			return nil, err
+		}
+		if span := e.captureSpan(raw, m, result.Version); span != nil {
+			span.file = result.File
+			return span, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot locate version %s in %s using %s",
+		result.Version, result.File, result.MatchedBy)
+}
+
+// captureSpan returns the span of version inside capture group 1 of match m,
+// or nil if the capture does not clean to version. The version is located
+// within the capture because cleanVersion strips quotes and "v" prefixes,
+// which must be left untouched.
+func (e *VersionExtractor) captureSpan(content string, m []int,
+	version string) *versionSpan {
+	if len(m) < 4 || m[2] < 0 {
+		return nil
+	}
+	capture := content[m[2]:m[3]]
+	if e.cleanVersion(capture) != version {
+		return nil
+	}
+	i := strings.Index(capture, version)
+	if i < 0 {
+		return nil
+	}
+	return &versionSpan{start: m[2] + i, end: m[2] + i + len(version)}
+}
+
+// locatePyprojectSpan mirrors extractFromPyprojectToml: only a version key in
+// the [project] table itself is considered.
+func (e *VersionExtractor) locatePyprojectSpan(filePath,
+	version string) (*versionSpan, error) {
+	raw, err := e.files().ReadFileContent(filePath, false)
+	if err != nil {
+		return nil, err
+	}
+	versionRe, err := getCompiledRegex(projectVersionPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	inProjectSection := false
+	offset := 0
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		trimmed := strings.TrimSpace(line)
+		lead := strings.Index(line, trimmed)
+		if strings.HasPrefix(trimmed, "[") {
+			inProjectSection = trimmed == "[project]"
+		} else if inProjectSection && !strings.HasPrefix(trimmed, "#") {
+			if m := versionRe.FindStringSubmatchIndex(trimmed); m != nil {
+				if span := e.captureSpan(trimmed, m, version); span != nil {
+					span.file = filePath
+					span.start += offset + lead
+					span.end += offset + lead
+					return span, nil
+				}
+			}
+		}
+		offset += len(line)
+	}
+
+	return nil, fmt.Errorf("cannot locate [project] version %s in %s",
+		version, filePath)
+}
+
+// locateConstantSpan finds the definition a build script's version was
+// resolved from (a constant, extra property, gradle.properties entry or
+// version catalog entry), so the definition file is rewritten instead of the
+// referencing script.
+func (e *VersionExtractor) locateConstantSpan(result *ExtractResult,
+	searchRoot string) (*versionSpan, error) {
+	project := e.projectForResult(result)
+	if project == nil {
+		return nil, fmt.Errorf("no project configuration for %s", result.ProjectType)
+	}
+	ref, err := e.findVersionConstant(result.File, searchRoot, project.Regex,
+		e.validatorFor(project, result.File))
+	if err != nil {
+		return nil, err
+	}
+	if ref == nil || ref.version != result.Version {
+		return nil, fmt.Errorf("cannot locate the constant definition for "+
+			"version %s referenced from %s", result.Version, result.File)
+	}
+	if ref.override {
+		return nil, fmt.Errorf("version %s referenced from %s is the %s; "+
+			"refusing to rewrite it", result.Version, result.File, ref.matchedBy)
+	}
+	if ref.defFile == "" || ref.start < 0 {
+		return nil, fmt.Errorf("version %s referenced from %s is composed "+
+			"from several values (%s); refusing to rewrite it",
+			result.Version, result.File, ref.matchedBy)
+	}
+
+	raw, err := e.files().ReadFileContent(ref.defFile, false)
+	if err != nil {
+		return nil, err
+	}
+	m := []int{ref.start, ref.end, ref.start, ref.end}
+	if ref.end <= len(raw) {
+		if span := e.captureSpan(raw, m, ref.version); span != nil {
+			span.file = ref.defFile
+			return span, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot locate %s in %s", ref.ident, ref.defFile)
+}
+
+// locateMavenSpan finds where a property-resolved Maven version is defined:
+// a <properties> entry or a -D entry in .mvn/maven.config. Versions composed
+// from several properties cannot be rewritten, nor can a literal version
+// inherited from <parent>: the parent's modules each name it in their
+// <parent> element, which rewriting the parent's <version> would break.
+func (e *VersionExtractor) locateMavenSpan(result *ExtractResult) (*versionSpan, error) {
+	res, err := e.resolveMavenVersion(result.File,
+		e.validatorFor(e.projectForResult(result), result.File))
+	if err != nil {
+		return nil, err
+	}
+	if res != nil && res.inherited {
+		if res.parentFile == "" {
+			return nil, fmt.Errorf("version %s of %s is inherited from a parent "+
+				"POM that is not on disk; refusing to rewrite", result.Version, result.File)
+		}
+		return nil, fmt.Errorf("version %s of %s is inherited from %s; bump the "+
+			"parent and the <parent> version of each module instead",
+			result.Version, result.File, res.parentFile)
+	}
+	if res == nil || res.defFile == "" {
+		return nil, fmt.Errorf("version %s of %s is composed from several "+
+			"properties; refusing to rewrite", result.Version, result.File)
+	}
+
+	raw, err := e.files().ReadFileContent(res.defFile, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var m []int
+	if res.defProperty != "" {
+		re, err := getCompiledRegex(`(?:^|\s)(?:-D\s*|--define[=\s])` +
+			regexp.QuoteMeta(res.defProperty) + `=["']?([^\s"']+)`)
+		if err != nil {
+			return nil, err
+		}
+		m = re.FindStringSubmatchIndex(raw)
+	} else if found, err := lookupXML([]byte(raw), res.defXPath); err == nil &&
+		found.start >= 0 {
+		m = []int{found.start, found.end, found.start, found.end}
+	}
+
+	if m != nil {
+		if span := e.captureSpan(raw, m, result.Version); span != nil {
+			span.file = res.defFile
+			return span, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot locate the definition of version %s in %s",
+		result.Version, res.defFile)
+}
+
+// locateMSBuildSpan finds the property definition an MSBuild version was
+// resolved from, following single $(Name) references. Versions composed
+// from several properties cannot be rewritten.
+func (e *VersionExtractor) locateMSBuildSpan(result *ExtractResult) (*versionSpan, error) {
+	res, err := e.resolveMSBuildVersion(result.File,
+		e.validatorFor(e.projectForResult(result), result.File))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil || res.def == nil {
+		return nil, fmt.Errorf("version %s of %s is composed from several "+
+			"properties; refusing to rewrite", result.Version, result.File)
+	}
+
+	raw, err := e.files().ReadFileContent(res.def.file, false)
+	if err != nil {
+		return nil, err
+	}
+	m := []int{res.def.start, res.def.end, res.def.start, res.def.end}
+	if res.def.end <= len(raw) {
+		if span := e.captureSpan(raw, m, result.Version); span != nil {
+			span.file = res.def.file
+			return span, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot locate the definition of version %s in %s",
+		result.Version, res.def.file)
+}
+
+// locateCargoSpan mirrors extractFromCargoToml. An inherited version is
+// rewritten in the workspace root's [workspace.package] table. The boolean
+// is false when result did not come from the Cargo.toml handler.
+func (e *VersionExtractor) locateCargoSpan(result *ExtractResult) (*versionSpan, bool, error) {
+	file := result.File
+	var keys []string
+	switch {
+	case result.MatchedBy == cargoPackageMatchedBy:
+		keys = []string{"package", "version"}
+	case result.MatchedBy == cargoWorkspaceMatchedBy:
+		keys = []string{"workspace", "package", "version"}
+	case strings.HasPrefix(result.MatchedBy, cargoInheritedMatchedBy):
+		member, err := e.parseCargoManifest(result.File)
+		if err != nil {
+			return nil, true, err
+		}
+		if file, _, err = e.findCargoWorkspaceRoot(result.File, member); err != nil {
+			return nil, true, err
+		}
+		keys = []string{"workspace", "package", "version"}
+	default:
+		return nil, false, nil
+	}
+
+	raw, err := e.files().ReadFileContent(file, false)
+	if err != nil {
+		return nil, true, err
+	}
+	start, end := tomlValueOffset([]byte(raw), keys, result.Version)
+	if start >= 0 {
+		if span := e.captureSpan(raw, []int{start, end, start, end}, result.Version); span != nil {
+			span.file = file
+			return span, true, nil
+		}
+	}
+	return nil, true, fmt.Errorf("cannot locate version %s in %s using %s",
+		result.Version, file, result.MatchedBy)
+}
+
+// projectForResult returns the project configuration that produced result
+func (e *VersionExtractor) projectForResult(result *ExtractResult) *config.ProjectConfig {
+	for i := range e.config.Projects {
+		p := &e.config.Projects[i]
+		if p.Type == result.ProjectType && p.Subtype == result.Subtype &&
+			e.fileMatchesPattern(filepath.Base(result.File), filepath.Base(p.File)) {
+			return p
+		}
+	}
+	return nil
+}
+
+// unifiedDiff renders a unified diff for a single replaced span, with up to
+// three lines of context either side, in the format produced by `diff -u`.
+func unifiedDiff(path, oldContent, newContent string, start, end int) string {
+	const context = 3
+
+	oldLines := strings.SplitAfter(oldContent, "\n")
+	newLines := strings.SplitAfter(newContent, "\n")
+
+	// Line indexes (0-based) of the first and last lines touched by the span.
+	first := strings.Count(oldContent[:start], "\n")
+	last := strings.Count(oldContent[:end], "\n")
+	delta := len(newLines) - len(oldLines)
+
+	from := max(first-context, 0)
+	to := min(last+context+1, len(oldLines))
+	// strings.SplitAfter yields a trailing empty element for content ending
+	// in a newline; it is not a line.
+	if to == len(oldLines) && oldLines[to-1] == "" {
+		to--
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", filepath.ToSlash(path),
+		filepath.ToSlash(path))
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", from+1, to-from, from+1,
+		to-from+delta)
+	writeLine := func(prefix, line string) {
+		b.WriteString(prefix)
+		b.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+	for i := from; i < first; i++ {
+		writeLine(" ", oldLines[i])
+	}
+	for i := first; i <= last; i++ {
+		writeLine("-", oldLines[i])
+	}
+	for i := first; i <= last+delta; i++ {
+		writeLine("+", newLines[i])
+	}
+	for i := last + 1; i < to; i++ {
+		writeLine(" ", oldLines[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		current string
+		opts    BumpOptions
+		want    string
+		wantErr bool
+	}{
+		{"1.2.3", BumpOptions{Level: BumpMajor}, "2.0.0", false},
+		{"1.2.3", BumpOptions{Level: BumpMinor}, "1.3.0", false},
+		{"1.2.3", BumpOptions{Level: BumpPatch}, "1.2.4", false},
+		{"1.2", BumpOptions{Level: BumpMinor}, "1.3", false},
+		{"1.2", BumpOptions{Level: BumpPatch}, "1.2.1", false},
+		{"1.2.3-rc.1", BumpOptions{Level: BumpPatch}, "1.2.3", false},
+		{"1.2.3+build.5", BumpOptions{Level: BumpPatch}, "1.2.4", false},
+		{"1.2.3", BumpOptions{Level: BumpPrerelease}, "1.2.4-0", false},
+		{"1.2.3", BumpOptions{Level: BumpPrerelease, Preid: "rc"}, "1.2.4-rc.0", false},
+		{"1.2.4-rc.0", BumpOptions{Level: BumpPrerelease, Preid: "rc"}, "1.2.4-rc.1", false},
+		{"1.2.4-alpha.3", BumpOptions{Level: BumpPrerelease, Preid: "beta"}, "1.2.4-beta.0", false},
+		{"1.2.4-beta", BumpOptions{Level: BumpPrerelease}, "1.2.4-beta.0", false},
+		{"1.2.3", BumpOptions{Set: "v1.4.0"}, "1.4.0", false},
+		{"1.2.3", BumpOptions{Set: "not-a-version"}, "", true},
+		{"1.2.3", BumpOptions{Level: "huge"}, "", true},
+		{"1.2.3.dev", BumpOptions{Level: BumpPatch}, "", true},
+	}
+
+	e := New(&config.Config{})
+	for _, tt := range tests {
+		got, err := e.nextVersion(tt.current, tt.opts, e.validatorFor(nil, "package.json"))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("nextVersion(%q, %+v): expected error, got %q", tt.current, tt.opts, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("nextVersion(%q, %+v): unexpected error: %v", tt.current, tt.opts, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("nextVersion(%q, %+v) = %q, want %q", tt.current, tt.opts, got, tt.want)
+		}
+	}
+}
+
+// TestBumpPreservesFormatting checks that only the captured version changes,
+// leaving a dependency's identical version, quoting and CRLF endings alone.
+func TestBumpPreservesFormatting(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "package.json")
+	original := "{\r\n  \"name\": \"app\",\r\n  \"version\": \"1.2.3\",\r\n" +
+		"  \"dependencies\": {\"dep\": \"1.2.3\"}\r\n}\r\n"
+	writeFile(t, file, original)
+
+	result, err := New(monorepoConfig()).Bump(tmpDir, BumpOptions{Level: BumpMinor})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result.OldVersion != "1.2.3" || result.NewVersion != "1.3.0" {
+		t.Errorf("expected 1.2.3 -> 1.3.0, got %s -> %s", result.OldVersion, result.NewVersion)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := strings.Replace(original, `"version": "1.2.3"`, `"version": "1.3.0"`, 1)
+	if string(data) != want {
+		t.Errorf("unexpected file content:\n%q\nwant:\n%q", data, want)
+	}
+}
+
+func TestBumpDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "package.json")
+	original := "{\n  \"name\": \"app\",\n  \"version\": \"v2.0.0\"\n}\n"
+	writeFile(t, file, original)
+
+	result, err := New(monorepoConfig()).Bump(file, BumpOptions{Set: "2.1.0", DryRun: true})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != original {
+		t.Error("dry run must not modify the file")
+	}
+
+	wantDiff := "--- " + filepath.ToSlash(file) + "\n" +
+		"+++ " + filepath.ToSlash(file) + "\n" +
+		"@@ -1,4 +1,4 @@\n" +
+		" {\n" +
+		"   \"name\": \"app\",\n" +
+		"-  \"version\": \"v2.0.0\"\n" +
+		"+  \"version\": \"v2.1.0\"\n" +
+		" }\n"
+	if result.Diff != wantDiff {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", result.Diff, wantDiff)
+	}
+}
+
+// TestBumpSetScheme validates an explicit version against the project's
+// version scheme rather than the default rules.
+func TestBumpSetScheme(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "package.json"), "{\n  \"version\": \"1.2.3\"\n}\n")
+
+	cfg := monorepoConfig()
+	for i := range cfg.Projects {
+		cfg.Projects[i].VersionScheme = "semver"
+	}
+	ext := New(cfg)
+	if _, err := ext.Bump(tmpDir, BumpOptions{Set: "1.4", DryRun: true}); err == nil {
+		t.Error("expected 1.4 to be rejected by the semver scheme")
+	}
+	if _, err := ext.Bump(tmpDir, BumpOptions{Set: "1.4.0", DryRun: true}); err != nil {
+		t.Errorf("expected 1.4.0 to pass the semver scheme, got %v", err)
+	}
+
+	// Maven builds accept Maven versions the default rules reject
+	writeFile(t, filepath.Join(tmpDir, "pom.xml"), "<project>\n  <version>1.0.0</version>\n</project>\n")
+	result, err := NewWithOptions(mavenConfig(), false).Bump(tmpDir,
+		BumpOptions{Set: "1.1.0.Final", DryRun: true})
+	if err != nil || result.NewVersion != "1.1.0.Final" {
+		t.Errorf("expected 1.1.0.Final for a Maven build, got %v", err)
+	}
+}
+
+// TestBumpPyprojectSection ensures the [project] version is rewritten, not
+// an identical value in another table.
+func TestBumpPyprojectSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "pyproject.toml")
+	writeFile(t, file, "[tool.other]\nversion = \"0.9.0\"\n\n[project]\n"+
+		"name = \"sdk\"\n  version = \"0.9.0\"\n")
+
+	if _, err := New(monorepoConfig()).Bump(tmpDir, BumpOptions{Level: BumpPatch}); err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	want := "[tool.other]\nversion = \"0.9.0\"\n\n[project]\n" +
+		"name = \"sdk\"\n  version = \"0.9.1\"\n"
+	if string(data) != want {
+		t.Errorf("unexpected file content:\n%s", data)
+	}
+}
+
+// TestBumpConstantDefinition rewrites the buildSrc constant a Gradle build
+// script's versionName refers to.
+func TestBumpConstantDefinition(t *testing.T) {
+	tmpDir := t.TempDir()
+	script := filepath.Join(tmpDir, "app", "build.gradle.kts")
+	def := filepath.Join(tmpDir, "buildSrc", "src", "main", "kotlin", "ProjectConfig.kt")
+	writeFile(t, script, "android {\n    versionName = APP_VERSION_NAME\n}\n")
+	writeFile(t, def, "// const val APP_VERSION_NAME = \"0.28.8\"\n"+
+		"const val APP_VERSION_NAME = \"0.28.8\"\n")
+
+	result, err := New(kotlinAndroidConfig()).Bump(tmpDir, BumpOptions{Level: BumpPatch})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result.File != def {
+		t.Errorf("expected definition file %s, got %s", def, result.File)
+	}
+
+	data, _ := os.ReadFile(def)
+	want := "// const val APP_VERSION_NAME = \"0.28.8\"\n" +
+		"const val APP_VERSION_NAME = \"0.28.9\"\n"
+	if string(data) != want {
+		t.Errorf("unexpected definition content:\n%s", data)
+	}
+	scriptData, _ := os.ReadFile(script)
+	if !strings.Contains(string(scriptData), "versionName = APP_VERSION_NAME") {
+		t.Error("build script must not be modified")
+	}
+}
+
+func TestBumpRefusesGitTag(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "go.mod"), "module example.com/m\n\ngo 1.24\n")
+
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"add", "."},
+		{"commit", "-m", "init"},
+		{"tag", "v1.0.0"},
+	} {
+		if err := runGitCommand(tmpDir, args...); err != nil {
+			t.Skipf("git unavailable: %v", err)
+		}
+	}
+
+	cfg := &config.Config{
+		Projects: []config.ProjectConfig{
+			{
+				Type:                      "Go",
+				File:                      "go.mod",
+				SupportsDynamicVersioning: true,
+				Priority:                  1,
+			},
+		},
+	}
+
+	_, err := New(cfg).Bump(tmpDir, BumpOptions{Level: BumpPatch})
+	if err == nil || !strings.Contains(err.Error(), "git tag") {
+		t.Fatalf("expected refusal for git tag version, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// maxCargoWorkspaceSearch bounds the directories walked upwards from a
+// member Cargo.toml looking for its workspace root.
+const maxCargoWorkspaceSearch = 8
+
+// MatchedBy values reported by the Cargo.toml handler. An inherited version
+// reports cargoInheritedMatchedBy followed by the workspace root manifest.
+const (
+	cargoPackageMatchedBy   = "[package] section version"
+	cargoWorkspaceMatchedBy = "[workspace.package] section version"
+	cargoInheritedMatchedBy = "version.workspace = true -> [workspace.package] version in "
+)
+
+// cargoManifest holds the parts of a Cargo.toml needed to find its version
+type cargoManifest struct {
+	Package *struct {
+		// Either a string, or a table such as {workspace = true} when the
+		// version is inherited.
+		Version   interface{} `toml:"version"`
+		Workspace string      `toml:"workspace"`
+	} `toml:"package"`
+	Workspace *struct {
+		Members []string `toml:"members"`
+		Exclude []string `toml:"exclude"`
+		Package *struct {
+			Version string `toml:"version"`
+		} `toml:"package"`
+	} `toml:"workspace"`
+}
+
+// extractFromCargoToml handles Cargo.toml with section-aware parsing, like
+// extractFromPyprojectToml. It reads [package].version, follows
+// `version.workspace = true` to the enclosing workspace root's
+// [workspace.package].version, and for a virtual manifest reports
+// [workspace.package].version directly. Versions in other tables (such as
+// dependencies) are never considered. A manifest that does not parse falls
+// back to the configured patterns.
+func (e *VersionExtractor) extractFromCargoToml(filePath string,
+	patterns []string, valid versionValidator) (string, string, error) {
+	manifest, err := e.parseCargoManifest(filePath)
+	if err != nil {
+		return e.extractVersionWithPatterns(filePath, patterns, valid)
+	}
+
+	if manifest.Package != nil && manifest.Package.Version != nil {
+		switch v := manifest.Package.Version.(type) {
+		case string:
+			version := e.cleanVersion(v)
+			if valid(version) {
+				return version, cargoPackageMatchedBy, nil
+			}
+			return "", "", nil
+		case map[string]interface{}:
+			if inherit, _ := v["workspace"].(bool); inherit {
+				version, rootFile, err := e.findCargoWorkspaceVersion(filePath, manifest, valid)
+				if err != nil {
+					return "", "", err
+				}
+				return version, cargoInheritedMatchedBy +
+					relativeTo(filepath.Dir(filePath), rootFile), nil
+			}
+		}
+		return "", "", nil
+	}
+
+	if version := workspacePackageVersion(manifest); version != "" {
+		version = e.cleanVersion(version)
+		if valid(version) {
+			return version, cargoWorkspaceMatchedBy, nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// findCargoWorkspaceVersion locates the workspace root of a member manifest
+// and returns its [workspace.package].version and the root Cargo.toml. The
+// root is named by package.workspace when set; otherwise it is the nearest
+// ancestor Cargo.toml with a [workspace] table, as Cargo resolves it.
+func (e *VersionExtractor) findCargoWorkspaceVersion(memberPath string,
+	member *cargoManifest, valid versionValidator) (string, string, error) {
+	rootFile, root, err := e.findCargoWorkspaceRoot(memberPath, member)
+	if err != nil {
+		return "", "", err
+	}
+
+	version := e.cleanVersion(workspacePackageVersion(root))
+	if version == "" {
+		return "", "", fmt.Errorf("workspace root %s does not set "+
+			"[workspace.package] version", rootFile)
+	}
+	if !valid(version) {
+		return "", "", fmt.Errorf("workspace version %q in %s is not valid",
+			version, rootFile)
+	}
+	return version, rootFile, nil
+}
+
+// findCargoWorkspaceRoot returns the path and parsed manifest of the
+// workspace root that memberPath belongs to.
+func (e *VersionExtractor) findCargoWorkspaceRoot(memberPath string,
+	member *cargoManifest) (string, *cargoManifest, error) {
+	memberDir := filepath.Dir(memberPath)
+
+	// The root manifest is its own workspace root.
+	if member.Workspace != nil {
+		return memberPath, member, nil
+	}
+
+	if member.Package != nil && member.Package.Workspace != "" {
+		rootFile := filepath.Join(memberDir,
+			filepath.FromSlash(member.Package.Workspace), "Cargo.toml")
+		root, err := e.parseCargoManifest(rootFile)
+		if err != nil {
+			return "", nil, err
+		}
+		if root.Workspace == nil {
+			return "", nil, fmt.Errorf("%s has no [workspace] table", rootFile)
+		}
+		return rootFile, root, nil
+	}
+
+	current := filepath.Dir(memberDir)
+	for i := 0; i < maxCargoWorkspaceSearch; i++ {
+		candidate := filepath.Join(current, "Cargo.toml")
+		if _, statErr := e.stat(candidate); statErr == nil {
+			root, err := e.parseCargoManifest(candidate)
+			if err == nil && root.Workspace != nil {
+				if !isCargoWorkspaceMember(current, memberDir, root) {
+					return "", nil, fmt.Errorf("%s is not a member of the "+
+						"workspace in %s", memberPath, candidate)
+				}
+				return candidate, root, nil
+			}
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+
+	return "", nil, fmt.Errorf("no workspace root found for %s", memberPath)
+}
+
+// isCargoWorkspaceMember reports whether memberDir is matched by the
+// workspace's members globs and not excluded.
+func isCargoWorkspaceMember(rootDir, memberDir string, root *cargoManifest) bool {
+	rel := relativeTo(rootDir, memberDir)
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+			if pattern == rel {
+				return true
+			}
+			if ok, _ := path.Match(pattern, rel); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return matches(root.Workspace.Members) && !matches(root.Workspace.Exclude)
+}
+
+// workspacePackageVersion returns [workspace.package].version, if any
+func workspacePackageVersion(manifest *cargoManifest) string {
+	if manifest.Workspace == nil || manifest.Workspace.Package == nil {
+		return ""
+	}
+	return manifest.Workspace.Package.Version
+}
+
+// parseCargoManifest decodes the version-related parts of a Cargo.toml
+func (e *VersionExtractor) parseCargoManifest(filePath string) (*cargoManifest, error) {
+	content, err := e.files().ReadFileContent(filePath, false)
+	if err != nil {
+		return nil, err
+	}
+	var manifest cargoManifest
+	if _, err := toml.Decode(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// cargoConfig mirrors the default Rust project type
+func cargoConfig() *config.Config {
+	return &config.Config{
+		Projects: []config.ProjectConfig{
+			{
+				Type:     "Rust",
+				Subtype:  "Cargo",
+				File:     "Cargo.toml",
+				TomlKey:  "package.version",
+				Regex:    []string{`version\s*=\s*"([^"]+)"`},
+				Priority: 1,
+			},
+		},
+	}
+}
+
+const cargoWorkspaceRoot = `[workspace]
+members = ["crates/*", "tools/cli"]
+exclude = ["crates/scratch"]
+
+[workspace.package]
+version = "2.4.1"
+edition = "2021"
+
+[workspace.dependencies]
+serde = { version = "1.0.200" }
+`
+
+func TestCargoWorkspaceInheritance(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		path      string
+		want      string
+		wantBy    string
+		wantError bool
+	}{
+		{
+			name: "dotted key",
+			files: map[string]string{
+				"Cargo.toml": cargoWorkspaceRoot,
+				"crates/core/Cargo.toml": `[package]
+name = "core"
+version.workspace = true
+
+[dependencies]
+serde = { version = "1.0.200" }
+`,
+			},
+			path:   "crates/core/Cargo.toml",
+			want:   "2.4.1",
+			wantBy: cargoInheritedMatchedBy + "../../Cargo.toml",
+		},
+		{
+			name: "inline table",
+			files: map[string]string{
+				"Cargo.toml": cargoWorkspaceRoot,
+				"tools/cli/Cargo.toml": `[dependencies]
+clap = "4.5.0"
+
+[package]
+name = "cli"
+version = { workspace = true }
+`,
+			},
+			path:   "tools/cli/Cargo.toml",
+			want:   "2.4.1",
+			wantBy: cargoInheritedMatchedBy + "../../Cargo.toml",
+		},
+		{
+			name: "explicit package.workspace",
+			files: map[string]string{
+				"build/Cargo.toml": cargoWorkspaceRoot,
+				"crates/core/Cargo.toml": `[package]
+name = "core"
+workspace = "../../build"
+version.workspace = true
+`,
+			},
+			path:   "crates/core/Cargo.toml",
+			want:   "2.4.1",
+			wantBy: cargoInheritedMatchedBy + "../../build/Cargo.toml",
+		},
+		{
+			name: "root package inherits from its own workspace",
+			files: map[string]string{
+				"Cargo.toml": `[package]
+name = "app"
+version.workspace = true
+
+[workspace]
+members = ["."]
+
+[workspace.package]
+version = "0.9.0"
+`,
+			},
+			path:   "Cargo.toml",
+			want:   "0.9.0",
+			wantBy: cargoInheritedMatchedBy + "Cargo.toml",
+		},
+		{
+			name:   "virtual manifest",
+			files:  map[string]string{"Cargo.toml": cargoWorkspaceRoot},
+			path:   "Cargo.toml",
+			want:   "2.4.1",
+			wantBy: cargoWorkspaceMatchedBy,
+		},
+		{
+			name: "excluded member",
+			files: map[string]string{
+				"Cargo.toml": cargoWorkspaceRoot,
+				"crates/scratch/Cargo.toml": `[package]
+name = "scratch"
+version.workspace = true
+`,
+			},
+			path:      "crates/scratch/Cargo.toml",
+			wantError: true,
+		},
+		{
+			name: "no workspace root",
+			files: map[string]string{
+				"member/Cargo.toml": `[package]
+name = "orphan"
+version.workspace = true
+
+[dependencies]
+serde = "1.0.0"
+`,
+			},
+			path:      "member/Cargo.toml",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(tmpDir, name), content)
+			}
+
+			result, err := New(cargoConfig()).Extract(filepath.Join(tmpDir, tt.path))
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected success, got error: %v", err)
+			}
+			if result.Version != tt.want {
+				t.Errorf("expected version %s, got %s", tt.want, result.Version)
+			}
+			if result.MatchedBy != tt.wantBy {
+				t.Errorf("expected matched_by %q, got %q", tt.wantBy, result.MatchedBy)
+			}
+			if result.VersionSource != "static" {
+				t.Errorf("expected version_source static, got %s", result.VersionSource)
+			}
+		})
+	}
+}
+
+// TestCargoWorkspaceExtractAll reports each member with the inherited version
+// alongside members that set their own.
+func TestCargoWorkspaceExtractAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "Cargo.toml"), cargoWorkspaceRoot)
+	writeFile(t, filepath.Join(tmpDir, "crates", "core", "Cargo.toml"),
+		"[package]\nname = \"core\"\nversion.workspace = true\n")
+	writeFile(t, filepath.Join(tmpDir, "crates", "macros", "Cargo.toml"),
+		"[package]\nname = \"macros\"\nversion = \"0.3.0\"\n")
+
+	results, err := New(cargoConfig()).ExtractAll(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, r := range results {
+		got[r.RelativePath] = r.Version
+	}
+	want := map[string]string{
+		"Cargo.toml":               "2.4.1",
+		"crates/core/Cargo.toml":   "2.4.1",
+		"crates/macros/Cargo.toml": "0.3.0",
+	}
+	for path, version := range want {
+		if got[path] != version {
+			t.Errorf("%s: expected %s, got %q", path, version, got[path])
+		}
+	}
+}
+
+// TestBumpCargoWorkspaceMember rewrites the inherited version at the
+// workspace root, leaving the member and dependency versions untouched.
+func TestBumpCargoWorkspaceMember(t *testing.T) {
+	tmpDir := t.TempDir()
+	root := filepath.Join(tmpDir, "Cargo.toml")
+	writeFile(t, root, cargoWorkspaceRoot)
+	member := filepath.Join(tmpDir, "crates", "core", "Cargo.toml")
+	memberContent := "[package]\nname = \"core\"\nversion.workspace = true\n"
+	writeFile(t, member, memberContent)
+
+	result, err := New(cargoConfig()).Bump(member, BumpOptions{Level: BumpMinor})
+	if err != nil {
+		t.Fatalf("bump failed: %v", err)
+	}
+	if result.File != root || result.NewVersion != "2.5.0" {
+		t.Errorf("unexpected bump result: %+v", result)
+	}
+
+	data, _ := os.ReadFile(root)
+	want := strings.Replace(cargoWorkspaceRoot, `version = "2.4.1"`, `version = "2.5.0"`, 1)
+	if string(data) != want {
+		t.Errorf("unexpected root manifest after bump:\n%s", data)
+	}
+	if data, _ := os.ReadFile(member); string(data) != memberContent {
+		t.Errorf("member manifest changed:\n%s", data)
+	}
+}
@@ -0,0 +1,334 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+	"github.com/lfreleng-actions/version-extract-action/internal/git"
+	"github.com/lfreleng-actions/version-extract-action/internal/version"
+)
+
+// defaultCheckGroup names the group used when no consistency groups are
+// configured, which holds every manifest with a static version.
+const defaultCheckGroup = "all"
+
+// CheckOptions controls a consistency check
+type CheckOptions struct {
+	// Groups lists the sets of files that must agree. When empty, every
+	// manifest with a static version forms a single group.
+	Groups []config.ConsistencyGroup
+	// GitTag compares every group with the latest git tag, as though each
+	// group set git_tag.
+	GitTag bool
+}
+
+// CheckEntry is one version compared by a consistency check: a file, or
+// the latest git tag when GitTag is set.
+type CheckEntry struct {
+	File         string `json:"file,omitempty"`
+	RelativePath string `json:"relative_path,omitempty"`
+	GitTag       string `json:"git_tag,omitempty"`
+	ProjectType  string `json:"project_type,omitempty"`
+	Version      string `json:"version"`
+	Normalized   string `json:"normalized"`
+	MatchedBy    string `json:"matched_by,omitempty"`
+	Agrees       bool   `json:"agrees"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Name identifies the entry in reports
+func (c *CheckEntry) Name() string {
+	if c.GitTag != "" {
+		return "git tag " + c.GitTag
+	}
+	return c.RelativePath
+}
+
+// CheckGroupResult is the outcome of checking one group
+type CheckGroupResult struct {
+	Name       string        `json:"name"`
+	Consistent bool          `json:"consistent"`
+	Expected   string        `json:"expected,omitempty"`
+	Entries    []*CheckEntry `json:"files"`
+}
+
+// Mismatches returns the entries that disagree with the expected version
+func (g *CheckGroupResult) Mismatches() []*CheckEntry {
+	var mismatches []*CheckEntry
+	for _, entry := range g.Entries {
+		if !entry.Agrees {
+			mismatches = append(mismatches, entry)
+		}
+	}
+	return mismatches
+}
+
+// CheckResult is the outcome of a consistency check
+type CheckResult struct {
+	Consistent bool                `json:"consistent"`
+	Groups     []*CheckGroupResult `json:"groups"`
+}
+
+// Check extracts versions from the manifests beneath path and reports, per
+// group, whether their normalised versions agree. The expected version of a
+// group is the git tag's when it is compared, otherwise the most common one,
+// with ties going to the file found first (root-level files come first).
+// A manifest that cannot be read is reported as an entry with Error set;
+// only finding no manifest at all fails the check.
+func (e *VersionExtractor) Check(path string, opts CheckOptions) (*CheckResult, error) {
+	e, path, err := e.begin(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := e.stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("path does not exist: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat path: %w", err)
+	}
+	if !fileInfo.IsDir() {
+		return nil, fmt.Errorf("check needs a directory, got file: %s", path)
+	}
+
+	var failures []fileFailure
+	e.failures = &failures
+	results, extractErr := e.ExtractAll(path)
+	if extractErr != nil && !errors.Is(extractErr, ErrNoVersion) {
+		return nil, extractErr
+	}
+	byFile := make(map[string]*ExtractResult, len(results))
+	for _, result := range results {
+		byFile[result.File] = result
+	}
+	failed := make(map[string]error, len(failures))
+	for _, failure := range failures {
+		failed[failure.file] = failure.err
+	}
+
+	// The latest tag is looked up once, on behalf of every group comparing it.
+	var gitResult *git.GitTagResult
+	latestTag := func() *git.GitTagResult {
+		if gitResult == nil {
+			gitResult = e.tryGitFallback(path, config.ProjectConfig{}, "")
+		}
+		return gitResult
+	}
+
+	check := &CheckResult{Consistent: true}
+	if len(opts.Groups) == 0 {
+		group := &CheckGroupResult{Name: defaultCheckGroup}
+		for _, result := range results {
+			if result.VersionSource == "dynamic-git-tag" {
+				continue
+			}
+			group.Entries = append(group.Entries, e.entryFromResult(result))
+		}
+		for _, failure := range failures {
+			if byFile[failure.file] != nil || failed[failure.file] == nil {
+				continue
+			}
+			delete(failed, failure.file) // Reported once
+			group.Entries = append(group.Entries, &CheckEntry{
+				File:         failure.file,
+				RelativePath: relativeTo(path, failure.file),
+				ProjectType:  failure.project.Type,
+				Error:        failure.err.Error(),
+			})
+		}
+		if len(group.Entries) == 0 && extractErr != nil {
+			return nil, extractErr
+		}
+		if opts.GitTag {
+			addGitTagEntry(group, latestTag())
+		}
+		check.add(group)
+		return check, nil
+	}
+
+	idx := e.buildFileIndex(path)
+	found := false
+	for _, g := range opts.Groups {
+		group := &CheckGroupResult{Name: g.Name}
+		seen := make(map[string]bool)
+		for _, member := range g.Files {
+			for _, file := range idx.all {
+				rel := relativeTo(idx.root, file)
+				if seen[file] || !memberMatches(member.File, rel) {
+					continue
+				}
+				seen[file] = true
+				found = true
+				group.Entries = append(group.Entries,
+					e.memberEntry(member, file, rel, byFile[file], failed[file]))
+			}
+		}
+		if opts.GitTag || g.GitTag {
+			addGitTagEntry(group, latestTag())
+		}
+		check.add(group)
+	}
+	if !found && extractErr != nil {
+		return nil, extractErr
+	}
+
+	return check, nil
+}
+
+// add records a group's outcome, deciding which entries agree
+func (c *CheckResult) add(group *CheckGroupResult) {
+	group.Expected = expectedVersion(group.Entries)
+	group.Consistent = true
+	for _, entry := range group.Entries {
+		entry.Agrees = entry.Error == "" && entry.Normalized == group.Expected
+		if !entry.Agrees {
+			group.Consistent = false
+		}
+	}
+	if !group.Consistent {
+		c.Consistent = false
+	}
+	c.Groups = append(c.Groups, group)
+}
+
+// expectedVersion picks the version a group's entries should agree on
+func expectedVersion(entries []*CheckEntry) string {
+	counts := make(map[string]int)
+	expected := ""
+	for _, entry := range entries {
+		if entry.Error != "" {
+			continue
+		}
+		if entry.GitTag != "" {
+			return entry.Normalized
+		}
+		counts[entry.Normalized]++
+		if counts[entry.Normalized] > counts[expected] {
+			expected = entry.Normalized
+		}
+	}
+	return expected
+}
+
+// memberMatches reports whether a root-relative path matches a member's
+// file glob. Globs without a "/" match the base name at any depth.
+func memberMatches(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		rel = path.Base(rel)
+	}
+	ok, _ := path.Match(pattern, rel)
+	return ok
+}
+
+// memberEntry reads a group member's version from file, using the member's
+// structured lookup if it has one, otherwise the extraction result, or the
+// error extraction failed with
+func (e *VersionExtractor) memberEntry(member config.ConsistencyMember,
+	file, rel string, result *ExtractResult, failure error) *CheckEntry {
+	lookup := member.Lookup()
+	if lookup.HasStructuredLookup() {
+		entry := &CheckEntry{File: file, RelativePath: rel}
+		if result != nil {
+			entry.ProjectType = result.ProjectType
+		}
+		entry.MatchedBy = structuredMatchedBy(lookup)
+		version, err := e.extractStructuredVersion(file, lookup)
+		if err != nil {
+			entry.Error = err.Error()
+			return entry
+		}
+		entry.Version = version
+		entry.Normalized = normalizeCheckVersion(file, version)
+		return entry
+	}
+
+	if result == nil {
+		entry := &CheckEntry{File: file, RelativePath: rel, Error: "no version found"}
+		if failure != nil {
+			entry.Error = failure.Error()
+		}
+		return entry
+	}
+	return e.entryFromResult(result)
+}
+
+// entryFromResult converts an extraction result to a check entry
+func (e *VersionExtractor) entryFromResult(result *ExtractResult) *CheckEntry {
+	return &CheckEntry{
+		File:         result.File,
+		RelativePath: result.RelativePath,
+		ProjectType:  result.ProjectType,
+		Version:      result.Version,
+		Normalized:   normalizeCheckVersion(result.File, result.Version),
+		MatchedBy:    result.MatchedBy,
+	}
+}
+
+// addGitTagEntry adds the latest git tag to a group. A missing tag is
+// reported as a disagreeing entry rather than skipped, so a requested
+// comparison never passes silently.
+func addGitTagEntry(group *CheckGroupResult, gitResult *git.GitTagResult) {
+	if gitResult == nil || !gitResult.Success {
+		group.Entries = append(group.Entries, &CheckEntry{
+			GitTag: "(none)",
+			Error:  "no version tag found",
+		})
+		return
+	}
+
+	// The tag goes first: it is the version the files are expected to match.
+	entry := &CheckEntry{
+		GitTag:     gitResult.Tag,
+		Version:    gitResult.Version,
+		Normalized: normalizeCheckVersion("", gitResult.Version),
+		MatchedBy:  "git-tag",
+	}
+	group.Entries = append([]*CheckEntry{entry}, group.Entries...)
+}
+
+// normalizeCheckVersion reduces a version to the form compared by Check:
+// cleaned of prefixes such as "v", lower case, and without build metadata,
+// which does not take part in version precedence. Versions that parse are
+// also normalised, so 1.02 agrees with 1.2. PEP 440 versions, which include
+// every version from a Python manifest, are compared through their semver
+// equivalent, so 1.0-RC1 in pyproject.toml agrees with 1.0.0-rc.1. Maven
+// and Gradle versions that Maven orders equal to their release numbers are
+// compared as those numbers, so 1.2.3.Final in pom.xml agrees with 1.2.3.
+func normalizeCheckVersion(file, v string) string {
+	if isMavenVersionFile(file) {
+		if parsed, err := version.ParseMaven(v).Version(); err == nil &&
+			len(parsed.Prerelease) == 0 {
+			v = parsed.String()
+		}
+	}
+	var p *version.PEP440
+	if isPythonVersionFile(file) {
+		p, _ = version.ParsePEP440(v)
+	} else if parsed, err := version.Parse(v); err == nil {
+		p = parsed.PEP440()
+	}
+	if p != nil {
+		if semver, err := p.Semver(); err == nil {
+			v = semver
+		}
+	}
+	if parsed, err := version.Parse(v); err == nil {
+		parsed.Build = nil
+		return strings.ToLower(parsed.String())
+	}
+
+	v = strings.ToLower(strings.TrimSpace(v))
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+	return v
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// writeDriftedRepo writes a repository whose chart version is independent
+// of the release version, and whose appVersion has drifted from it
+func writeDriftedRepo(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "package.json"), `{"name": "app", "version": "v1.2.3"}`)
+	writeFile(t, filepath.Join(tmpDir, "sdk", "pyproject.toml"),
+		"[project]\nname = \"sdk\"\nversion = \"1.2.3\"\n")
+	writeFile(t, filepath.Join(tmpDir, "charts", "app", "Chart.yaml"),
+		"apiVersion: v2\nname: app\nversion: 0.4.1\nappVersion: \"1.2.2\"\n")
+	return tmpDir
+}
+
+func TestCheckDefaultGroup(t *testing.T) {
+	result, err := New(monorepoConfig()).Check(writeDriftedRepo(t), CheckOptions{})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if result.Consistent || len(result.Groups) != 1 {
+		t.Fatalf("expected one inconsistent group, got %+v", result)
+	}
+
+	group := result.Groups[0]
+	if group.Name != defaultCheckGroup || group.Expected != "1.2.3" {
+		t.Errorf("unexpected group: %+v", group)
+	}
+	mismatches := group.Mismatches()
+	if len(mismatches) != 1 || mismatches[0].RelativePath != "charts/app/Chart.yaml" ||
+		mismatches[0].Version != "0.4.1" {
+		t.Errorf("expected the chart version to disagree, got %+v", mismatches)
+	}
+}
+
+func TestCheckGroups(t *testing.T) {
+	tmpDir := writeDriftedRepo(t)
+	ext := New(monorepoConfig())
+
+	groups := []config.ConsistencyGroup{{
+		Name: "release",
+		Files: []config.ConsistencyMember{
+			{File: "package.json"},
+			{File: "sdk/*.toml"},
+			{File: "Chart.yaml", Path: "$.appVersion"},
+		},
+	}}
+	result, err := ext.Check(tmpDir, CheckOptions{Groups: groups})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	group := result.Groups[0]
+	if result.Consistent || len(group.Entries) != 3 {
+		t.Fatalf("expected three entries with a mismatch, got %+v", group)
+	}
+	mismatches := group.Mismatches()
+	if len(mismatches) != 1 || mismatches[0].Version != "1.2.2" ||
+		mismatches[0].MatchedBy != "path: $.appVersion" {
+		t.Errorf("expected appVersion to disagree, got %+v", mismatches)
+	}
+
+	// The chart's own version is not part of the group
+	writeFile(t, filepath.Join(tmpDir, "charts", "app", "Chart.yaml"),
+		"apiVersion: v2\nname: app\nversion: 0.4.1\nappVersion: \"1.2.3+build.7\"\n")
+	result, err = ext.Check(tmpDir, CheckOptions{Groups: groups})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if !result.Consistent {
+		t.Errorf("expected versions to agree, got %+v", result.Groups[0].Mismatches())
+	}
+
+	// A listed file without a version disagrees
+	writeFile(t, filepath.Join(tmpDir, "other", "package.json"), `{"name": "other"}`)
+	result, err = ext.Check(tmpDir, CheckOptions{Groups: groups})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	mismatches = result.Groups[0].Mismatches()
+	if result.Consistent || len(mismatches) != 1 || mismatches[0].Error == "" {
+		t.Errorf("expected other/package.json to be reported, got %+v", mismatches)
+	}
+}
+
+func TestCheckGitTag(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping git integration test")
+	}
+
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "package.json"), `{"name": "app", "version": "1.2.3"}`)
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+		{"add", "package.json"},
+		{"commit", "-m", "Initial commit"},
+		{"tag", "-a", "v1.2.4", "-m", "v1.2.4"},
+	} {
+		if err := runGitCommand(tmpDir, args...); err != nil {
+			t.Skipf("Failed to set up git repo: %v", err)
+		}
+	}
+
+	result, err := New(monorepoConfig()).Check(tmpDir, CheckOptions{GitTag: true})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	group := result.Groups[0]
+	if result.Consistent || group.Expected != "1.2.4" || group.Entries[0].GitTag != "v1.2.4" {
+		t.Fatalf("expected the git tag to set the expected version, got %+v", group)
+	}
+	mismatches := group.Mismatches()
+	if len(mismatches) != 1 || mismatches[0].RelativePath != "package.json" {
+		t.Errorf("expected package.json to disagree with the tag, got %+v", mismatches)
+	}
+}
+
+func TestCheckPEP440Equivalence(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "package.json"), `{"version": "1.0.0-rc.1"}`)
+	writeFile(t, filepath.Join(tmpDir, "sdk", "pyproject.toml"),
+		"[project]\nname = \"sdk\"\nversion = \"1.0-RC1\"\n")
+
+	result, err := New(monorepoConfig()).Check(tmpDir, CheckOptions{})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if !result.Consistent || result.Groups[0].Expected != "1.0.0-rc.1" {
+		t.Errorf("expected 1.0-RC1 to agree with 1.0.0-rc.1, got %+v", result.Groups[0])
+	}
+}
+
+func TestCheckMavenEquivalence(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "package.json"), `{"version": "1.2.3"}`)
+	writeFile(t, filepath.Join(tmpDir, "java", "pom.xml"),
+		"<project>\n  <artifactId>app</artifactId>\n  <version>1.2.3.Final</version>\n</project>\n")
+
+	cfg := mavenConfig()
+	cfg.Projects = append(cfg.Projects, monorepoConfig().Projects...)
+	result, err := New(cfg).Check(tmpDir, CheckOptions{})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	if !result.Consistent || result.Groups[0].Expected != "1.2.3" {
+		t.Errorf("expected 1.2.3.Final to agree with 1.2.3, got %+v", result.Groups[0])
+	}
+}
+
+func TestCheckFailedFiles(t *testing.T) {
+	cfg := &config.Config{Projects: []config.ProjectConfig{
+		{Type: "JavaScript", File: "package.json", Path: "$.version", Priority: 1},
+	}}
+	ext := New(cfg)
+
+	// A manifest that cannot be read is reported, not fatal
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "web", "package.json"), `{"version" "1.0.0"}`)
+	result, err := ext.Check(tmpDir, CheckOptions{})
+	if err != nil {
+		t.Fatalf("check failed: %v", err)
+	}
+	mismatches := result.Groups[0].Mismatches()
+	if result.Consistent || len(mismatches) != 1 || mismatches[0].RelativePath != "web/package.json" {
+		t.Fatalf("expected web/package.json to be reported, got %+v", result.Groups[0])
+	}
+
+	writeFile(t, filepath.Join(tmpDir, "package.json"), `{"version": "1.2.3"}`)
+	groups := []config.ConsistencyGroup{{
+		Name:  "release",
+		Files: []config.ConsistencyMember{{File: "package.json"}},
+	}}
+	for _, opts := range []CheckOptions{{}, {Groups: groups}} {
+		result, err := ext.Check(tmpDir, opts)
+		if err != nil {
+			t.Fatalf("check failed: %v", err)
+		}
+		group := result.Groups[0]
+		mismatches := group.Mismatches()
+		if len(group.Entries) != 2 || group.Expected != "1.2.3" || len(mismatches) != 1 ||
+			!strings.Contains(mismatches[0].Error, "failed to parse JSON") {
+			t.Errorf("expected web/package.json to disagree with its parse error, got %+v", group)
+		}
+	}
+
+	// Finding no manifest at all is an error
+	for _, opts := range []CheckOptions{{}, {Groups: groups}} {
+		if _, err := ext.Check(t.TempDir(), opts); !errors.Is(err, ErrNoVersion) {
+			t.Errorf("expected ErrNoVersion, got %v", err)
+		}
+	}
+}
@@ -4,84 +4,442 @@
 package extractor
 
 import (
-	"errors"
 	"fmt"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"regexp"
 	"strings"
 )
 
 // maxConstantScanFiles bounds the number of Kotlin/Gradle files scanned when
-// resolving a version constant, to keep the cost reasonable on large repos.
+// resolving a version reference, to keep the cost reasonable on large repos.
+// The budget is shared by every lookup made while resolving one reference.
 const maxConstantScanFiles = 600
 
-// errStopWalk is a sentinel used to halt filepath.Walk early once a match is
-// found (filepath.Walk has no other early-exit mechanism).
-var errStopWalk = errors.New("stop walk")
+// maxGradleValueDepth bounds nested references, such as an ext property whose
+// definition is itself a "${major}.${minor}" template.
+const maxGradleValueDepth = 5
 
 // blockCommentPattern matches /* ... */ comments, including inline ones and
 // those spanning multiple lines.
 var blockCommentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
 
-// resolveVersionConstant handles the common Kotlin/Gradle idiom where the
-// version is assigned from a named constant instead of a literal:
+// Reference expressions understood on the right-hand side of a version
+// assignment, after any trailing conversion (gradleExprSuffix) is removed.
+var (
+	// findProperty("x"), project.property('x'), providers.gradleProperty("x")
+	gradlePropertyCall = regexp.MustCompile(`^(?:(?:project|rootProject)\.)?` +
+		`(?:findProperty|property|providers\.gradleProperty)\(\s*["']([^"']+)["']\s*\)$`)
+	// project.properties["x"], extra["x"], rootProject.ext['x']
+	gradlePropertyIndex = regexp.MustCompile(`^(?:(?:project|rootProject)\.)?` +
+		`(?:properties|extra|ext)\[\s*["']([^"']+)["']\s*\]$`)
+	// libs.versions.kotlin.coroutines
+	gradleCatalogVersion = regexp.MustCompile(
+		`^([A-Za-z][A-Za-z0-9_]*)\.versions\.([A-Za-z0-9_.-]+)$`)
+	// APP_VERSION, appVersion, ext.appVersion, rootProject.ext.appVersion
+	gradleExtraAccess = regexp.MustCompile(`^(?:(?:project|rootProject)\.)?` +
+		`(?:(?:ext|extra)\.)?([A-Za-z_][A-Za-z0-9_]*)$`)
+	// .get(), .toString(), .orNull, !! and "as String" conversions
+	gradleExprSuffix = regexp.MustCompile(
+		`(?:\.get\(\)|\.toString\(\)|\.orNull|!!|\s+as\s+String)$`)
+	// ${expr} and $name references inside a double-quoted string
+	gradleTemplateRef = regexp.MustCompile(`\$\{([^}]+)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// Kinds of definition a Gradle version reference can resolve to, as they
+// appear in ExtractResult.MatchedBy.
+const (
+	gradleKindConstant = "constant"
+	gradleKindExtra    = "extra property"
+	gradleKindProperty = "property"
+	gradleKindCatalog  = "version catalog entry"
+)
+
+// SetGradleProperty sets a Gradle project property, as gradle -Pname=value
+// does. It takes precedence over gradle.properties files when a build script
+// refers to the property.
+func (e *VersionExtractor) SetGradleProperty(name, value string) error {
+	if name == "" || strings.ContainsAny(name, "= \t\r\n") {
+		return fmt.Errorf("invalid Gradle property name %q", name)
+	}
+	if e.gradleProperties == nil {
+		e.gradleProperties = make(map[string]string)
+	}
+	e.gradleProperties[name] = value
+	return nil
+}
+
+// resolveVersionConstant handles the common Gradle idioms where the version is
+// assigned from a reference instead of a literal:
 //
 //	// app/build.gradle.kts
 //	versionName = NEWPIPE_VERSION_NAME
 //	// buildSrc/src/main/kotlin/ProjectConfig.kt
 //	const val NEWPIPE_VERSION_NAME = "0.28.8"
 //
+// as well as findProperty("x") / property("x") backed by gradle.properties,
+// "${projectVersion}" string templates, Groovy rootProject.ext.appVersion
+// with `def`, `ext.x =` or `ext { }` definitions, and libs.versions.x entries
+// from a version catalog.
+//
 // The assignment key it looks for (versionName and/or version) is derived from
 // the running project type's own regex patterns, so the resulting project_type
 // label stays consistent with the type that matched. It scans refFile for such
-// a reference and resolves the constant's literal value by searching
-// conventional locations within searchPath (buildSrc, build-logic, the
+// a reference and resolves its value from gradle.properties and version
+// catalogs between the referencing file and searchPath, then from definitions
+// in conventional locations within searchPath (buildSrc, build-logic, the
 // referencing file's directory) before falling back to a bounded walk of the
 // wider tree. It returns the resolved version and a description of the match.
 func (e *VersionExtractor) resolveVersionConstant(refFile, searchPath string,
-	patterns []string) (string, string, error) {
+	patterns []string, valid versionValidator) (string, string, error) {
+	ref, err := e.findVersionConstant(refFile, searchPath, patterns, valid)
+	if err != nil || ref == nil {
+		return "", "", err
+	}
+	return ref.version, ref.matchedBy, nil
+}
+
+// constantRef describes a version reference resolved by findVersionConstant.
+type constantRef struct {
+	ident     string // reference expression, e.g. NEWPIPE_VERSION_NAME
+	version   string // cleaned, validated version value
+	matchedBy string // description of the resolution
+	// Definition holding the value and the span of the value within it.
+	// defFile is empty when the version is composed from several
+	// definitions or literal text, so no single definition can be edited.
+	defFile    string
+	start, end int
+	// override is set when the value is a project property given with
+	// SetGradleProperty, which no file holds
+	override bool
+}
+
+// gradleValue is a single definition a reference resolved to
+type gradleValue struct {
+	name  string
+	kind  string
+	value string
+	file  string // "" for a project property given with SetGradleProperty
+	// Span of the value in file, or -1 when the definition is itself a
+	// template composed from other values.
+	start, end int
+}
+
+// describe formats a definition for ExtractResult.MatchedBy
+func (v *gradleValue) describe() string {
+	if v.file == "" {
+		return fmt.Sprintf("%s %s given with -P", v.kind, v.name)
+	}
+	return fmt.Sprintf("%s %s defined in %s", v.kind, v.name,
+		filepath.Base(v.file))
+}
+
+// gradleResolution is the outcome of resolving a reference expression
+type gradleResolution struct {
+	value     string
+	values    []*gradleValue // definitions used, outermost first
+	composite bool           // value combines literal text or several values
+}
+
+// gradleResolver resolves Gradle reference expressions for one build script,
+// sharing the maxConstantScanFiles budget across every lookup.
+type gradleResolver struct {
+	e          *VersionExtractor
+	searchPath string
+	refDir     string
+	scanned    int
+}
+
+// findVersionConstant does the work behind resolveVersionConstant, returning
+// where the value is defined as well as the value itself so that callers
+// which rewrite the version (bump) can edit the definition. It returns nil
+// when no reference resolves to a valid version.
+func (e *VersionExtractor) findVersionConstant(refFile, searchPath string,
+	patterns []string, valid versionValidator) (*constantRef, error) {
 
 	// Only Gradle build scripts use this assignment idiom.
 	if !isGradleScript(refFile) {
-		return "", "", nil
+		return nil, nil
 	}
 
 	keys := versionAssignmentKeys(patterns)
 	if len(keys) == 0 {
-		return "", "", nil
+		return nil, nil
 	}
 	refRe, err := constRefPattern(keys)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	content, err := fileReader.ReadFileContent(refFile, true)
+	content, err := e.files().ReadFileContent(refFile, true)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
 	refs := refRe.FindAllStringSubmatch(stripComments(content), -1)
 	if len(refs) == 0 {
-		return "", "", nil
+		return nil, nil
 	}
 
+	r := &gradleResolver{e: e, searchPath: searchPath,
+		refDir: filepath.Dir(refFile)}
 	for _, ref := range refs {
-		ident := ref[1]
-		value, defFile := e.lookupConstantValue(ident, searchPath,
-			filepath.Dir(refFile))
-		if value == "" {
+		expr := strings.TrimSpace(ref[1])
+		res, ok := r.resolveExpr(expr, 0)
+		if !ok {
+			continue
+		}
+		clean := e.cleanVersion(res.value)
+		if !valid(clean) {
+			continue
+		}
+
+		found := &constantRef{ident: expr, version: clean, start: -1, end: -1}
+		if !res.composite && len(res.values) == 1 {
+			v := res.values[0]
+			found.matchedBy = v.describe()
+			found.defFile, found.start, found.end = v.file, v.start, v.end
+			found.override = v.file == ""
+		} else {
+			described := make([]string, 0, len(res.values))
+			for _, v := range res.values {
+				described = append(described, v.describe())
+			}
+			found.matchedBy = fmt.Sprintf("%s from %s", expr,
+				strings.Join(described, ", "))
+		}
+		return found, nil
+	}
+
+	return nil, nil
+}
+
+// resolveExpr resolves a reference expression to its value. Literal strings
+// are not resolved: the project type's own regexes already handle those.
+func (r *gradleResolver) resolveExpr(expr string, depth int) (*gradleResolution, bool) {
+	if depth > maxGradleValueDepth {
+		return nil, false
+	}
+
+	expr = strings.TrimSpace(expr)
+	for {
+		trimmed := gradleExprSuffix.ReplaceAllString(expr, "")
+		if trimmed == expr {
+			break
+		}
+		expr = strings.TrimSpace(trimmed)
+	}
+
+	if len(expr) >= 2 && expr[0] == '"' && expr[len(expr)-1] == '"' {
+		return r.expandTemplate(expr[1:len(expr)-1], depth)
+	}
+	if m := gradlePropertyCall.FindStringSubmatch(expr); m != nil {
+		return r.resolveName(m[1], depth)
+	}
+	if m := gradlePropertyIndex.FindStringSubmatch(expr); m != nil {
+		return r.resolveName(m[1], depth)
+	}
+	if m := gradleCatalogVersion.FindStringSubmatch(expr); m != nil {
+		return r.resolveCatalog(m[1], m[2])
+	}
+	if m := gradleExtraAccess.FindStringSubmatch(expr); m != nil {
+		return r.resolveName(m[1], depth)
+	}
+	return nil, false
+}
+
+// expandTemplate resolves every ${expr} and $name reference in a
+// double-quoted Groovy/Kotlin string. A template that is exactly one
+// reference, such as "${projectVersion}", is not composite.
+func (r *gradleResolver) expandTemplate(tmpl string, depth int) (*gradleResolution, bool) {
+	matches := gradleTemplateRef.FindAllStringSubmatchIndex(tmpl, -1)
+	if len(matches) == 0 {
+		return nil, false
+	}
+
+	res := &gradleResolution{}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(tmpl[last:m[0]])
+		refExpr := ""
+		if m[2] >= 0 {
+			refExpr = tmpl[m[2]:m[3]]
+		} else {
+			refExpr = tmpl[m[4]:m[5]]
+		}
+		sub, ok := r.resolveExpr(refExpr, depth+1)
+		if !ok {
+			return nil, false
+		}
+		b.WriteString(sub.value)
+		res.values = append(res.values, sub.values...)
+		res.composite = res.composite || sub.composite
+		last = m[1]
+	}
+	b.WriteString(tmpl[last:])
+
+	res.value = b.String()
+	if len(matches) > 1 || matches[0][0] != 0 || matches[0][1] != len(tmpl) {
+		res.composite = true
+	}
+	return res, true
+}
+
+// resolveName resolves a property or constant name: project properties given
+// with SetGradleProperty, then gradle.properties files from the referencing
+// directory up to searchPath take precedence, as Gradle project properties
+// do, then Kotlin/Groovy definitions in build sources.
+func (r *gradleResolver) resolveName(name string, depth int) (*gradleResolution, bool) {
+	if value, ok := r.e.gradleProperties[name]; ok {
+		return &gradleResolution{value: value, values: []*gradleValue{{
+			name: name, kind: gradleKindProperty, value: value, start: -1, end: -1,
+		}}}, true
+	}
+	for _, dir := range r.ancestorDirs() {
+		file := filepath.Join(dir, "gradle.properties")
+		raw, err := r.e.files().ReadFileContent(file, false)
+		if err != nil {
 			continue
 		}
-		clean := e.cleanVersion(value)
-		if e.isValidVersion(clean) {
-			matchedBy := fmt.Sprintf("constant %s defined in %s",
-				ident, filepath.Base(defFile))
-			return clean, matchedBy, nil
+		if value, start, end, ok := findGradleProperty(raw, name); ok {
+			return &gradleResolution{value: value, values: []*gradleValue{{
+				name: name, kind: gradleKindProperty, value: value,
+				file: file, start: start, end: end,
+			}}}, true
 		}
 	}
 
-	return "", "", nil
+	def := r.lookupDefinition(name)
+	if def == nil {
+		return nil, false
+	}
+	res := &gradleResolution{value: def.value, values: []*gradleValue{def}}
+	if def.start < 0 {
+		// The definition is a template of its own; expand it.
+		sub, ok := r.expandTemplate(def.value, depth+1)
+		if !ok {
+			return nil, false
+		}
+		res.value = sub.value
+		res.values = append(res.values, sub.values...)
+		res.composite = true
+	}
+	return res, true
+}
+
+// resolveCatalog resolves a <catalog>.versions.<alias> accessor from
+// gradle/<catalog>.versions.toml. Gradle maps "-", "_" and "." in catalog
+// keys to the same accessor, so keys are compared in that normalised form.
+func (r *gradleResolver) resolveCatalog(catalog, alias string) (*gradleResolution, bool) {
+	for _, dir := range r.ancestorDirs() {
+		file := filepath.Join(dir, "gradle", catalog+".versions.toml")
+		raw, err := r.e.files().ReadFileContent(file, false)
+		if err != nil {
+			continue
+		}
+		key, value, ok := findCatalogVersion(raw, alias)
+		if !ok {
+			continue
+		}
+		start, end := tomlValueOffset([]byte(raw), []string{"versions", key}, value)
+		return &gradleResolution{value: value, values: []*gradleValue{{
+			name: key, kind: gradleKindCatalog, value: value,
+			file: file, start: start, end: end,
+		}}}, true
+	}
+	return nil, false
+}
+
+// ancestorDirs lists the directories from the referencing file's directory up
+// to searchPath, nearest first. When the file lies outside searchPath only
+// the two directories themselves are returned.
+func (r *gradleResolver) ancestorDirs() []string {
+	root := filepath.Clean(r.searchPath)
+	current := filepath.Clean(r.refDir)
+	if rel, err := filepath.Rel(root, current); err != nil ||
+		rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return []string{current, root}
+	}
+
+	var dirs []string
+	for i := 0; i < 8; i++ {
+		dirs = append(dirs, current)
+		if current == root {
+			break
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	return dirs
+}
+
+// lookupDefinition searches the project for a Kotlin or Groovy definition of
+// name (see findGradleDefinition) and returns it, or nil if none is found.
+// Conventional constant locations are searched first; a bounded walk of
+// searchPath is the fallback.
+func (r *gradleResolver) lookupDefinition(name string) *gradleValue {
+	var found *gradleValue
+
+	scan := func(root string) {
+		info, statErr := r.e.stat(root)
+		if statErr != nil || !info.IsDir() {
+			return
+		}
+		_ = fs.WalkDir(r.e.filesystem(), root, func(path string, d fs.DirEntry,
+			werr error) error {
+			if werr != nil {
+				return nil
+			}
+			if d.IsDir() {
+				// Never skip the walk root itself, such as "." for --path .
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return fs.SkipDir
+				}
+				for _, skip := range r.e.skipDirectories {
+					if d.Name() == skip {
+						return fs.SkipDir
+					}
+				}
+				return nil
+			}
+			if !isBuildSource(d.Name()) {
+				return nil
+			}
+			if r.scanned >= maxConstantScanFiles {
+				return fs.SkipAll
+			}
+			r.scanned++
+			// Raw content: the span is used to rewrite the definition.
+			fileContent, readErr := r.e.files().ReadFileContent(path, false)
+			if readErr != nil {
+				return nil
+			}
+			if def := findGradleDefinition(fileContent, name); def != nil {
+				def.file = path
+				found = def
+				return fs.SkipAll
+			}
+			return nil
+		})
+	}
+
+	for _, root := range []string{
+		filepath.Join(r.searchPath, "buildSrc"),
+		filepath.Join(r.searchPath, "build-logic"),
+		r.refDir,
+		r.searchPath,
+	} {
+		scan(root)
+		if found != nil || r.scanned >= maxConstantScanFiles {
+			break
+		}
+	}
+
+	return found
 }
 
 // versionAssignmentKeys inspects a project type's regex patterns and returns
@@ -111,88 +469,126 @@ func versionAssignmentKeys(patterns []string) []string {
 	return keys
 }
 
-// constRefPattern builds (and caches) a regex matching `<key> = IDENTIFIER` for
-// the given assignment keys, where the right-hand side is a bare constant
-// reference rather than a quoted literal, numeric literal, or function call.
+// constRefPattern builds (and caches) a regex matching `<key> = <expr>` for
+// the given assignment keys, capturing the right-hand side up to any trailing
+// line comment. Which expressions are references is decided by resolveExpr.
 func constRefPattern(keys []string) (*regexp.Regexp, error) {
 	alt := strings.Join(keys, "|")
 	return getCompiledRegex(
 		`(?m)(?:^|[^A-Za-z0-9_])(?:` + alt + `)[ \t]*=[ \t]*` +
-			`([A-Za-z_][A-Za-z0-9_]*)[ \t]*(?://.*)?$`)
+			`([^ \t\n][^\n]*?)[ \t]*(?://.*)?$`)
 }
 
-// lookupConstantValue searches the project for a Kotlin constant definition of
-// the form `const val IDENT = "value"` (or `val IDENT = "value"`, optionally
-// typed) and returns its literal value and the file it was found in.
-// Conventional constant locations are searched first; a bounded walk of
-// searchPath is the fallback.
-func (e *VersionExtractor) lookupConstantValue(ident, searchPath,
-	refDir string) (string, string) {
+// constDefPattern builds (and caches) a regex matching a Kotlin constant
+// `[const] val IDENT[: Type] = "value"` or a Groovy `def IDENT = 'value'`,
+// capturing the value.
+func constDefPattern(ident string) (*regexp.Regexp, error) {
+	name := regexp.QuoteMeta(ident)
+	return getCompiledRegex(
+		`(?m)(?:^|[^A-Za-z0-9_.])(?:(?:const[ \t]+)?val[ \t]+` + name +
+			`[ \t]*(?::[^=\n]+)?|def[ \t]+` + name + `)[ \t]*=[ \t]*` +
+			`["']([^"'\n]*)["']`)
+}
 
-	defRe, err := getCompiledRegex(
-		`(?m)(?:^|[^A-Za-z0-9_])(?:const[ \t]+)?val[ \t]+` +
-			regexp.QuoteMeta(ident) +
-			`[ \t]*(?::[^=\n]+)?=[ \t]*"([^"]+)"`)
-	if err != nil {
-		return "", ""
-	}
+// extraDefPattern builds (and caches) a regex matching an extra property
+// definition: `ext.IDENT = 'v'`, `rootProject.ext.IDENT = "v"`,
+// `extra["IDENT"] = "v"`, `extra.set("IDENT", "v")` or
+// `val IDENT by extra("v")`, capturing the value.
+func extraDefPattern(ident string) (*regexp.Regexp, error) {
+	name := regexp.QuoteMeta(ident)
+	owner := `(?:(?:project|rootProject)\.)?(?:ext|extra)`
+	return getCompiledRegex(
+		`(?m)(?:^|[^A-Za-z0-9_.])(?:` +
+			owner + `\.` + name + `[ \t]*=|` +
+			owner + `\[[ \t]*["']` + name + `["'][ \t]*\][ \t]*=|` +
+			owner + `\.set\([ \t]*["']` + name + `["'][ \t]*,|` +
+			`val[ \t]+` + name + `(?:[ \t]*:[^=\n]+?)?[ \t]+by[ \t]+extra\()` +
+			`[ \t]*["']([^"'\n]*)["']`)
+}
+
+// extBlockAssignPattern builds (and caches) a regex matching `IDENT = 'v'` on
+// its own line, as written inside a Groovy `ext { }` block.
+func extBlockAssignPattern(ident string) (*regexp.Regexp, error) {
+	return getCompiledRegex(`(?m)^[ \t]*` + regexp.QuoteMeta(ident) +
+		`[ \t]*=[ \t]*["']([^"'\n]*)["']`)
+}
 
-	var value, valueFile string
-	scanned := 0
+// extBlockStart matches the opening of a Groovy `ext { }` block
+var extBlockStart = regexp.MustCompile(`(?m)(?:^|[^A-Za-z0-9_.])ext[ \t]*\{`)
 
-	scan := func(root string) {
-		info, statErr := os.Stat(root)
-		if statErr != nil || !info.IsDir() {
-			return
+// findGradleDefinition finds the first definition of name in a Kotlin or
+// Groovy source, skipping any inside comments. Values in double quotes that
+// contain "$" are templates; their span is reported as -1 so callers expand
+// them instead of treating them as a literal.
+func findGradleDefinition(content, name string) *gradleValue {
+	comments := blockCommentPattern.FindAllStringIndex(content, -1)
+	valid := func(pos int) bool {
+		return !inRanges(pos, comments) && !inLineComment(content, pos)
+	}
+	result := func(kind string, start, end int) *gradleValue {
+		v := &gradleValue{name: name, kind: kind,
+			value: content[start:end], start: start, end: end}
+		if content[start-1] == '"' && strings.Contains(v.value, "$") {
+			v.start, v.end = -1, -1
 		}
-		_ = filepath.Walk(root, func(path string, fi os.FileInfo,
-			werr error) error {
-			if werr != nil {
-				return nil
-			}
-			if fi.IsDir() {
-				if strings.HasPrefix(fi.Name(), ".") {
-					return filepath.SkipDir
-				}
-				for _, skip := range e.skipDirectories {
-					if fi.Name() == skip {
-						return filepath.SkipDir
-					}
-				}
-				return nil
-			}
-			if !isKotlinSource(fi.Name()) {
-				return nil
-			}
-			if scanned >= maxConstantScanFiles {
-				return errStopWalk
-			}
-			scanned++
-			fileContent, readErr := fileReader.ReadFileContent(path, true)
-			if readErr != nil {
-				return nil
+		return v
+	}
+
+	if re, err := constDefPattern(name); err == nil {
+		for _, m := range re.FindAllStringSubmatchIndex(content, -1) {
+			if valid(m[2]) {
+				return result(gradleKindConstant, m[2], m[3])
 			}
-			if m := defRe.FindStringSubmatch(stripComments(fileContent)); len(m) == 2 {
-				value, valueFile = m[1], path
-				return errStopWalk
+		}
+	}
+	if re, err := extraDefPattern(name); err == nil {
+		for _, m := range re.FindAllStringSubmatchIndex(content, -1) {
+			if valid(m[2]) {
+				return result(gradleKindExtra, m[2], m[3])
 			}
-			return nil
-		})
+		}
 	}
 
-	for _, root := range []string{
-		filepath.Join(searchPath, "buildSrc"),
-		filepath.Join(searchPath, "build-logic"),
-		refDir,
-		searchPath,
-	} {
-		scan(root)
-		if value != "" || scanned >= maxConstantScanFiles {
-			break
+	re, err := extBlockAssignPattern(name)
+	if err != nil {
+		return nil
+	}
+	for _, block := range extBlockStart.FindAllStringIndex(content, -1) {
+		open := block[1] - 1
+		if !valid(open) {
+			continue
+		}
+		closeAt := matchingBrace(content, open)
+		if closeAt < 0 {
+			continue
+		}
+		body := content[open+1 : closeAt]
+		for _, m := range re.FindAllStringSubmatchIndex(body, -1) {
+			if valid(open + 1 + m[2]) {
+				return result(gradleKindExtra, open+1+m[2], open+1+m[3])
+			}
 		}
 	}
+	return nil
+}
 
-	return value, valueFile
+// matchingBrace returns the index of the brace closing the one at open, or
+// -1 if it is unbalanced. Braces inside quoted strings are not special-cased;
+// ext blocks hold simple assignments.
+func matchingBrace(content string, open int) int {
+	depth := 0
+	for i := open; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
 }
 
 // isGradleScript reports whether the file is a Gradle build script where the
@@ -204,13 +600,14 @@ func isGradleScript(name string) bool {
 		strings.HasSuffix(name, ".gradle")
 }
 
-// isKotlinSource reports whether the file may contain a Kotlin/Gradle constant
-// definition. Only Kotlin sources are scanned: `const val`/`val` is Kotlin
-// syntax, so Groovy `*.gradle` files (which use `def`) would never match and
-// would only consume the scan budget.
-func isKotlinSource(name string) bool {
+// isBuildSource reports whether the file may contain a Kotlin or Groovy
+// definition of a version value: Kotlin sources and scripts, and Groovy
+// build scripts (which use `def` and `ext`).
+func isBuildSource(name string) bool {
 	return strings.HasSuffix(name, ".kt") ||
-		strings.HasSuffix(name, ".kts")
+		strings.HasSuffix(name, ".kts") ||
+		strings.HasSuffix(name, ".gradle") ||
+		strings.HasSuffix(name, ".groovy")
 }
 
 // stripComments removes comments before pattern matching so commented-out
@@ -232,3 +629,19 @@ func stripComments(content string) string {
 	}
 	return strings.Join(kept, "\n")
 }
+
+// inRanges reports whether pos falls within any of the [start, end) ranges
+func inRanges(pos int, ranges [][]int) bool {
+	for _, r := range ranges {
+		if pos >= r[0] && pos < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// inLineComment reports whether pos sits on a whole-line // comment
+func inLineComment(content string, pos int) bool {
+	lineStart := strings.LastIndex(content[:pos], "\n") + 1
+	return strings.HasPrefix(strings.TrimSpace(content[lineStart:pos]), "//")
+}
@@ -6,6 +6,7 @@ package extractor
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/lfreleng-actions/version-extract-action/internal/config"
@@ -229,3 +230,293 @@ func TestResolveVersionConstantIgnoresComments(t *testing.T) {
 		t.Errorf("expected 1.2.3 (commented assignment/definition ignored), got %q", result.Version)
 	}
 }
+
+// groovyGradleConfig mirrors the default "Java - Gradle" project type, with
+// the Gradle Kotlin DSL type after it.
+func groovyGradleConfig() *config.Config {
+	return &config.Config{
+		Projects: []config.ProjectConfig{
+			{
+				Type:    "Java",
+				Subtype: "Gradle",
+				File:    "build.gradle",
+				Regex: []string{
+					`version\s*=\s*['"]([^'"]+)['"]`,
+					`version\s*['"]([^'"]+)['"]`,
+				},
+				Priority: 1,
+			},
+			{
+				Type:     "Java",
+				Subtype:  "Gradle Kotlin",
+				File:     "build.gradle.kts",
+				Regex:    []string{`version\s*=\s*"([^"]+)"`},
+				Priority: 2,
+			},
+		},
+	}
+}
+
+// TestResolveGradleValues covers the Gradle reference forms beyond Kotlin
+// constants: project properties, string templates, extra properties, Groovy
+// definitions and version catalogs.
+func TestResolveGradleValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		script string
+		want   string
+		wantBy string
+	}{
+		{
+			name: "findProperty from gradle.properties",
+			files: map[string]string{
+				"build.gradle":      "version = findProperty('releaseVersion')\n",
+				"gradle.properties": "# release\norg.gradle.jvmargs=-Xmx2g\nreleaseVersion=1.5.0\n",
+			},
+			script: "build.gradle",
+			want:   "1.5.0",
+			wantBy: "property releaseVersion defined in gradle.properties",
+		},
+		{
+			name: "string template from gradle.properties",
+			files: map[string]string{
+				"build.gradle.kts":  "version = \"${projectVersion}\"\n",
+				"gradle.properties": "projectVersion = 2.0.0\n",
+			},
+			script: "build.gradle.kts",
+			want:   "2.0.0",
+			wantBy: "property projectVersion defined in gradle.properties",
+		},
+		{
+			name: "subproject gradle.properties wins",
+			files: map[string]string{
+				"gradle.properties":     "releaseVersion=1.0.0\n",
+				"lib/gradle.properties": "releaseVersion: 1.1.0\n",
+				"lib/build.gradle":      "version = project.property(\"releaseVersion\") as String\n",
+			},
+			script: "lib/build.gradle",
+			want:   "1.1.0",
+			wantBy: "property releaseVersion defined in gradle.properties",
+		},
+		{
+			name: "rootProject.ext from an ext block",
+			files: map[string]string{
+				"build.gradle":     "ext {\n    minSdk = 21\n    appVersion = '3.1.0'\n}\n",
+				"app/build.gradle": "android {}\nversion = rootProject.ext.appVersion\n",
+			},
+			script: "app/build.gradle",
+			want:   "3.1.0",
+			wantBy: "extra property appVersion defined in build.gradle",
+		},
+		{
+			name: "Groovy def",
+			files: map[string]string{
+				"build.gradle": "def appVersion = '0.9.1'\nversion = appVersion\n",
+			},
+			script: "build.gradle",
+			want:   "0.9.1",
+			wantBy: "constant appVersion defined in build.gradle",
+		},
+		{
+			name: "Kotlin extra property",
+			files: map[string]string{
+				"build.gradle.kts":     "val appVersion by extra(\"5.0.0\")\n",
+				"app/build.gradle.kts": "version = rootProject.extra[\"appVersion\"] as String\n",
+			},
+			script: "app/build.gradle.kts",
+			want:   "5.0.0",
+			wantBy: "extra property appVersion defined in build.gradle.kts",
+		},
+		{
+			name: "version catalog",
+			files: map[string]string{
+				"build.gradle.kts":          "version = libs.versions.my.app.get()\n",
+				"gradle/libs.versions.toml": "[versions]\nkotlin = \"2.0.0\"\nmy-app = \"4.2.0\"\n\n[libraries]\n",
+			},
+			script: "build.gradle.kts",
+			want:   "4.2.0",
+			wantBy: "version catalog entry my-app defined in libs.versions.toml",
+		},
+		{
+			name: "composed template",
+			files: map[string]string{
+				"build.gradle.kts":  "version = \"$major.$minor.$patch\"\n",
+				"gradle.properties": "major=1\nminor=2\npatch=3\n",
+			},
+			script: "build.gradle.kts",
+			want:   "1.2.3",
+			wantBy: `"$major.$minor.$patch" from property major defined in gradle.properties, ` +
+				`property minor defined in gradle.properties, property patch defined in gradle.properties`,
+		},
+		{
+			name: "ext property that is itself a template",
+			files: map[string]string{
+				"build.gradle":      "ext.appVersion = \"${baseVersion}-rc.1\"\nversion = ext.appVersion\n",
+				"gradle.properties": "baseVersion=2.0.0\n",
+			},
+			script: "build.gradle",
+			want:   "2.0.0-rc.1",
+			wantBy: "ext.appVersion from extra property appVersion defined in build.gradle, " +
+				"property baseVersion defined in gradle.properties",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFile(t, filepath.Join(tmpDir, "settings.gradle"), "")
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(tmpDir, name), content)
+			}
+
+			result, err := New(groovyGradleConfig()).Extract(filepath.Join(tmpDir, tt.script))
+			if err != nil {
+				t.Fatalf("expected success, got error: %v", err)
+			}
+			if result.Version != tt.want {
+				t.Errorf("expected version %s, got %q", tt.want, result.Version)
+			}
+			if result.MatchedBy != tt.wantBy {
+				t.Errorf("expected matched_by %q, got %q", tt.wantBy, result.MatchedBy)
+			}
+			if result.VersionSource != "static-constant" {
+				t.Errorf("expected version_source static-constant, got %q", result.VersionSource)
+			}
+		})
+	}
+}
+
+// TestResolveGradleValuesRelativePath resolves definitions in the root
+// build script when the search path is the relative ".", as --path . gives
+func TestResolveGradleValuesRelativePath(t *testing.T) {
+	for name, files := range map[string]map[string]string{
+		"Groovy def": {"build.gradle": "def appVersion = '0.9.1'\nversion = appVersion\n"},
+		"ext block": {
+			"build.gradle":     "ext {\n    appVersion = '0.9.1'\n}\n",
+			"app/build.gradle": "version = rootProject.ext.appVersion\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFile(t, filepath.Join(tmpDir, "settings.gradle"), "")
+			for file, content := range files {
+				writeFile(t, filepath.Join(tmpDir, file), content)
+			}
+			t.Chdir(tmpDir)
+
+			result, err := New(groovyGradleConfig()).Extract(".")
+			if err != nil {
+				t.Fatalf("expected success, got error: %v", err)
+			}
+			if result.Version != "0.9.1" {
+				t.Errorf("expected version 0.9.1, got %q", result.Version)
+			}
+		})
+	}
+}
+
+// TestUnresolvedGradlePropertyFails ensures a property with no definition
+// does not produce a version.
+func TestUnresolvedGradlePropertyFails(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "build.gradle"),
+		"version = findProperty('releaseVersion')\n")
+	writeFile(t, filepath.Join(tmpDir, "gradle.properties"), "otherVersion=1.0.0\n")
+
+	result, err := New(groovyGradleConfig()).Extract(tmpDir)
+	if err == nil && result != nil && result.Success {
+		t.Errorf("expected no extraction, got version %q", result.Version)
+	}
+}
+
+// TestGradleProjectProperty resolves a property given with -P ahead of
+// gradle.properties, and refuses to bump it
+func TestGradleProjectProperty(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "build.gradle"),
+		"version = findProperty('releaseVersion')\n")
+	writeFile(t, filepath.Join(tmpDir, "gradle.properties"), "releaseVersion=1.0.0\n")
+
+	ext := New(groovyGradleConfig())
+	if err := ext.SetGradleProperty("releaseVersion", "2.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ext.Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result.Version != "2.0.0" || result.MatchedBy != "property releaseVersion given with -P" {
+		t.Errorf("expected 2.0.0 from -P, got %q (%s)", result.Version, result.MatchedBy)
+	}
+	if _, err := ext.Bump(tmpDir, BumpOptions{Level: BumpMinor, DryRun: true}); err == nil ||
+		!strings.Contains(err.Error(), "given with -P") {
+		t.Errorf("expected bumping a -P property to be refused, got %v", err)
+	}
+
+	for _, name := range []string{"", "a=b", "release version"} {
+		if err := ext.SetGradleProperty(name, "1.0.0"); err == nil {
+			t.Errorf("expected property name %q to be rejected", name)
+		}
+	}
+}
+
+func TestFindGradleProperty(t *testing.T) {
+	content := "! comment\n# releaseVersion=0.0.1\nreleaseVersionX=9\n  releaseVersion : 1.2.3  \r\n"
+	value, start, end, ok := findGradleProperty(content, "releaseVersion")
+	if !ok || value != "1.2.3" || content[start:end] != "1.2.3" {
+		t.Errorf("unexpected result %q [%d:%d] %v", value, start, end, ok)
+	}
+	if _, _, _, ok := findGradleProperty(content, "missing"); ok {
+		t.Error("expected missing property not to be found")
+	}
+}
+
+// TestBumpGradleValues rewrites the gradle.properties entry or catalog
+// version a build script refers to, and refuses composed versions.
+func TestBumpGradleValues(t *testing.T) {
+	t.Run("gradle.properties", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFile(t, filepath.Join(tmpDir, "build.gradle"), "version = findProperty('releaseVersion')\n")
+		props := filepath.Join(tmpDir, "gradle.properties")
+		writeFile(t, props, "org.gradle.caching=true\nreleaseVersion=1.5.0\n")
+
+		result, err := New(groovyGradleConfig()).Bump(tmpDir, BumpOptions{Level: BumpMinor})
+		if err != nil {
+			t.Fatalf("bump failed: %v", err)
+		}
+		if result.File != props {
+			t.Errorf("expected %s to be rewritten, got %s", props, result.File)
+		}
+		data, _ := os.ReadFile(props)
+		if string(data) != "org.gradle.caching=true\nreleaseVersion=1.6.0\n" {
+			t.Errorf("unexpected gradle.properties after bump: %q", data)
+		}
+	})
+
+	t.Run("version catalog", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFile(t, filepath.Join(tmpDir, "build.gradle.kts"), "version = libs.versions.app.get()\n")
+		catalog := filepath.Join(tmpDir, "gradle", "libs.versions.toml")
+		writeFile(t, catalog, "[versions]\napp = \"4.2.0\"\n")
+
+		if _, err := New(groovyGradleConfig()).Bump(tmpDir, BumpOptions{Set: "4.3.0"}); err != nil {
+			t.Fatalf("bump failed: %v", err)
+		}
+		data, _ := os.ReadFile(catalog)
+		if string(data) != "[versions]\napp = \"4.3.0\"\n" {
+			t.Errorf("unexpected catalog after bump: %q", data)
+		}
+	})
+
+	t.Run("composed template refused", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFile(t, filepath.Join(tmpDir, "build.gradle.kts"), "version = \"$major.$minor.$patch\"\n")
+		writeFile(t, filepath.Join(tmpDir, "gradle.properties"), "major=1\nminor=2\npatch=0\n")
+
+		_, err := New(groovyGradleConfig()).Bump(tmpDir, BumpOptions{Level: BumpPatch})
+		if err == nil || !strings.Contains(err.Error(), "composed") {
+			t.Errorf("expected composed version bump to be refused, got %v", err)
+		}
+	})
+}
@@ -14,7 +14,7 @@ import (
 func (e *VersionExtractor) detectDynamicVersioning(filePath string, indicators []config.DynamicVersionIndicator) (bool, error) {
 	// Read full file content for dynamic versioning detection
 	// This requires full content due to complex multi-line patterns and cross-references
-	fileContent, err := fileReader.ReadFileContent(filePath, true)
+	fileContent, err := e.files().ReadFileContent(filePath, true)
 	if err != nil {
 		return false, err
 	}
@@ -25,6 +25,7 @@ func (e *VersionExtractor) detectDynamicVersioning(filePath string, indicators [
 			return false, err
 		}
 		if matched {
+			e.tracer.dynamic(indicator)
 			return true, nil
 		}
 	}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// ExtractAll extracts a version from every supported manifest beneath path,
+// rather than stopping at the first hit as Extract does. This suits monorepos
+// that hold several components (e.g. a Go service, an npm frontend and a Helm
+// chart), each with its own version.
+//
+// The tree is indexed once and every project type is matched against it in
+// priority order. Each file is reported at most once: when several project
+// types match the same file, the highest-priority type that yields a version
+// claims it. When path is a file, the result holds that single file.
+func (e *VersionExtractor) ExtractAll(path string) ([]*ExtractResult, error) {
+	e, path, err := e.begin(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := e.stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("path does not exist: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat path: %w", err)
+	}
+
+	if !fileInfo.IsDir() {
+		result, err := e.extractFromSpecificFile(path)
+		if err != nil {
+			return nil, err
+		}
+		result.RelativePath = filepath.Base(path)
+		result.setParsedVersion()
+		return []*ExtractResult{result}, nil
+	}
+
+	idx := e.buildFileIndex(path)
+	claimed := make(map[string]bool)
+	var results []*ExtractResult
+
+	for _, project := range e.config.Projects {
+		if err := e.cancelled(); err != nil {
+			return nil, err
+		}
+		for _, file := range idx.match(project.File) {
+			if claimed[file] {
+				continue
+			}
+
+			result := e.extractAllFromFile(path, project, file)
+			if result == nil {
+				continue
+			}
+
+			claimed[file] = true
+			result.RelativePath = relativeTo(idx.root, file)
+			result.setParsedVersion()
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNoVersion
+	}
+
+	return results, nil
+}
+
+// ExtractAllContext is ExtractAll, returning ctx's error as soon as it
+// notices that ctx is done, as ExtractContext does
+func (e *VersionExtractor) ExtractAllContext(ctx context.Context,
+	path string) ([]*ExtractResult, error) {
+	results, err := e.withContext(ctx).ExtractAll(path)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return results, err
+}
+
+// extractAllFromFile extracts the version for one manifest on behalf of
+// ExtractAll, routing project types without regex patterns (e.g. go.mod) to
+// the git fallback when it is enabled.
+func (e *VersionExtractor) extractAllFromFile(searchPath string,
+	project config.ProjectConfig, file string) *ExtractResult {
+	if len(project.Regex) == 0 && !project.HasStructuredLookup() {
+		if !e.dynamicFallback || !project.SupportsDynamicVersioning {
+			return nil
+		}
+		return e.gitFallbackResult(searchPath, project, file)
+	}
+
+	return e.extractFromProjectFile(searchPath, project, file)
+}
+
+// relativeTo returns file relative to root using forward slashes, so the
+// reported path is stable across platforms. It falls back to the original
+// path if the two cannot be related.
+func relativeTo(root, file string) string {
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
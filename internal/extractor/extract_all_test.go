@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+	"github.com/lfreleng-actions/version-extract-action/internal/version"
+)
+
+// monorepoConfig returns a config covering the component types used by the
+// monorepo tests, in the same priority order as the default patterns.
+func monorepoConfig() *config.Config {
+	return &config.Config{
+		Projects: []config.ProjectConfig{
+			{
+				Type:     "JavaScript",
+				Subtype:  "npm",
+				File:     "package.json",
+				Regex:    []string{`"version":\s*"([^"]+)"`},
+				Priority: 1,
+			},
+			{
+				Type:     "Python",
+				Subtype:  "Modern (pyproject.toml)",
+				File:     "pyproject.toml",
+				Regex:    []string{`version\s*=\s*["']([^"']+)["']`},
+				Priority: 2,
+			},
+			{
+				Type:     "Helm",
+				File:     "Chart.yaml",
+				Regex:    []string{`^version:\s*["']?([^"'\s]+)["']?`},
+				Priority: 3,
+			},
+			{
+				Type:     "Generic",
+				Subtype:  "JSON",
+				File:     "*.json",
+				Regex:    []string{`"version":\s*"([^"]+)"`},
+				Priority: 4,
+			},
+		},
+	}
+}
+
+func TestExtractAllMonorepo(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeFile(t, filepath.Join(tmpDir, "web", "package.json"),
+		`{"name": "web", "version": "1.9.0"}`)
+	writeFile(t, filepath.Join(tmpDir, "sdk", "pyproject.toml"),
+		"[project]\nname = \"sdk\"\nversion = \"0.4.2\"\n")
+	writeFile(t, filepath.Join(tmpDir, "charts", "api", "Chart.yaml"),
+		"apiVersion: v2\nname: api\nversion: 2.3.0\n")
+	writeFile(t, filepath.Join(tmpDir, "README.md"), "no version here\n")
+
+	results, err := New(monorepoConfig()).ExtractAll(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	want := map[string]struct{ version, projectType string }{
+		"web/package.json":      {"1.9.0", "JavaScript"},
+		"sdk/pyproject.toml":    {"0.4.2", "Python"},
+		"charts/api/Chart.yaml": {"2.3.0", "Helm"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d: %+v", len(want), len(results), results)
+	}
+	for _, r := range results {
+		w, ok := want[r.RelativePath]
+		if !ok {
+			t.Errorf("unexpected result for %q", r.RelativePath)
+			continue
+		}
+		if r.Version != w.version {
+			t.Errorf("%s: expected version %s, got %s", r.RelativePath, w.version, r.Version)
+		}
+		if r.ProjectType != w.projectType {
+			t.Errorf("%s: expected type %s, got %s", r.RelativePath, w.projectType, r.ProjectType)
+		}
+		if r.VersionSource != "static" {
+			t.Errorf("%s: expected version_source static, got %s", r.RelativePath, r.VersionSource)
+		}
+	}
+}
+
+// TestExtractAllClaimsFileOnce ensures a file matched by several project
+// types is reported once, by the highest-priority type.
+func TestExtractAllClaimsFileOnce(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeFile(t, filepath.Join(tmpDir, "package.json"),
+		`{"name": "app", "version": "3.1.4"}`)
+	writeFile(t, filepath.Join(tmpDir, "manifest.json"),
+		`{"version": "0.0.7"}`)
+
+	results, err := New(monorepoConfig()).ExtractAll(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+	if results[0].RelativePath != "package.json" || results[0].ProjectType != "JavaScript" {
+		t.Errorf("expected package.json claimed by JavaScript, got %+v", results[0])
+	}
+	if results[1].RelativePath != "manifest.json" || results[1].Subtype != "JSON" {
+		t.Errorf("expected manifest.json claimed by Generic JSON, got %+v", results[1])
+	}
+}
+
+func TestExtractAllSpecificFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "package.json")
+	writeFile(t, file, `{"version": "1.0.0"}`)
+
+	results, err := New(monorepoConfig()).ExtractAll(file)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if len(results) != 1 || results[0].Version != "1.0.0" {
+		t.Fatalf("expected a single 1.0.0 result, got %+v", results)
+	}
+}
+
+func TestExtractAllNoMatches(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "README.md"), "nothing\n")
+
+	if _, err := New(monorepoConfig()).ExtractAll(tmpDir); err == nil {
+		t.Fatal("expected error when no manifests are found")
+	}
+}
+
+// TestExtractParsedVersion checks the parsed version components reported
+// alongside the version, in both extraction modes and in JSON.
+func TestExtractParsedVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "package.json"), `{"version": "v2.1.0-rc.2+sha.5"}`)
+	writeFile(t, filepath.Join(tmpDir, "charts", "app", "Chart.yaml"), "name: app\nversion: 2024.05\n")
+
+	ext := New(monorepoConfig())
+	result, err := ext.Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result.ParsedVersion == nil || !result.ParsedVersion.IsPrerelease() {
+		t.Fatalf("expected a parsed pre-release, got %+v", result.ParsedVersion)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"major": 2.0, "minor": 1.0, "patch": 0.0,
+		"prerelease": "rc.2", "build": "sha.5", "scheme": "semver",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("JSON %s = %v, want %v", key, fields[key], value)
+		}
+	}
+
+	results, err := ext.ExtractAll(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if len(results) != 2 || results[1].Fields == nil || results[1].Scheme != version.SchemeCalVer ||
+		results[1].Major != 2024 || results[1].Prerelease != "" {
+		t.Errorf("expected a CalVer chart version, got %+v", results[1])
+	}
+}
+
+// TestExtractPEP440 checks that Python manifests accept PEP 440 versions and
+// report their canonical form and semver equivalent.
+func TestExtractPEP440(t *testing.T) {
+	tests := []struct {
+		version, canonical, semver string
+	}{
+		{"1.0-RC1", "1.0rc1", "1.0.0-rc.1"},
+		{"2.0.post3", "2.0.post3", "2.0.0+post.3"},
+		{"1.0.dev4+local.7", "1.0.dev4+local.7", "1.0.0-dev.4+local.7"},
+		{"1!2.0", "1!2.0", ""},
+	}
+
+	ext := New(monorepoConfig())
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFile(t, filepath.Join(tmpDir, "pyproject.toml"),
+				"[project]\nname = \"sdk\"\nversion = \""+tt.version+"\"\n")
+
+			result, err := ext.Extract(tmpDir)
+			if err != nil {
+				t.Fatalf("expected success, got error: %v", err)
+			}
+			if result.Version != tt.version || result.CanonicalVersion != tt.canonical ||
+				result.SemverVersion != tt.semver {
+				t.Errorf("got version %q, canonical %q, semver %q; want %q, %q, %q",
+					result.Version, result.CanonicalVersion, result.SemverVersion,
+					tt.version, tt.canonical, tt.semver)
+			}
+			if result.Fields == nil || result.Scheme != version.SchemePEP440 {
+				t.Errorf("expected a pep440 scheme, got %+v", result.Fields)
+			}
+		})
+	}
+
+	// Versions outside Python manifests do not report PEP 440 forms
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "package.json"), `{"version": "1.2.3"}`)
+	result, err := ext.Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result.CanonicalVersion != "" || result.SemverVersion != "" {
+		t.Errorf("unexpected PEP 440 forms for package.json: %+v", result)
+	}
+}
+
+func TestExtractMavenSnapshot(t *testing.T) {
+	tests := []struct {
+		version    string
+		snapshot   bool
+		prerelease bool
+	}{
+		{"1.2.3-SNAPSHOT", true, true},
+		{"1.0-20240115.103000-3", true, true},
+		{"2.0-M1", false, true},
+		{"1.2.3.Final", false, false},
+	}
+
+	ext := New(mavenConfig())
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFile(t, filepath.Join(tmpDir, "pom.xml"),
+				"<project>\n  <groupId>org.example</groupId>\n"+
+					"  <artifactId>app</artifactId>\n  <version>"+tt.version+
+					"</version>\n</project>\n")
+
+			result, err := ext.Extract(tmpDir)
+			if err != nil {
+				t.Fatalf("expected success, got error: %v", err)
+			}
+			if result.Version != tt.version || result.Snapshot != tt.snapshot {
+				t.Errorf("got version %q, snapshot %t; want %q, %t",
+					result.Version, result.Snapshot, tt.version, tt.snapshot)
+			}
+			if result.ParsedVersion == nil || result.Scheme != version.SchemeMaven ||
+				result.ParsedVersion.IsPrerelease() != tt.prerelease {
+				t.Errorf("expected a maven version with prerelease %t, got %+v",
+					tt.prerelease, result.Fields)
+			}
+		})
+	}
+}
+
+func TestExtractConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	webDir, apiDir := filepath.Join(tmpDir, "web"), filepath.Join(tmpDir, "api")
+	writeFile(t, filepath.Join(webDir, "package.json"), `{"version": "1.0.0"}`)
+	writeFile(t, filepath.Join(apiDir, "pyproject.toml"), "[project]\nversion = \"2.0.0\"\n")
+
+	// One extractor serves every call; traces and cancellation stay with
+	// the call they belong to
+	e := New(monorepoConfig())
+	e.SetTrace(true)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dir, want := webDir, "1.0.0"
+			if i%2 == 1 {
+				dir, want = apiDir, "2.0.0"
+			}
+			if i%4 == 3 {
+				if _, err := e.ExtractContext(cancelled, dir); !errors.Is(err, context.Canceled) {
+					t.Errorf("Expected a cancelled extraction, got %v", err)
+				}
+				return
+			}
+
+			result, err := e.ExtractContext(context.Background(), dir)
+			if err != nil {
+				t.Errorf("Extract %s failed: %v", dir, err)
+				return
+			}
+			if result.Version != want || result.Trace == nil {
+				t.Errorf("Expected %s with a trace from %s, got %+v", want, dir, result)
+				return
+			}
+			for _, project := range result.Trace.Projects {
+				for _, file := range project.Files {
+					if !strings.HasPrefix(file, dir) {
+						t.Errorf("Trace of %s lists %s", dir, file)
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -4,13 +4,17 @@
 package extractor
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"log/slog"
 	"path/filepath"
 	"strings"
 
 	"github.com/lfreleng-actions/version-extract-action/internal/config"
 	"github.com/lfreleng-actions/version-extract-action/internal/git"
+	"github.com/lfreleng-actions/version-extract-action/internal/version"
 )
 
 // File processing limits
@@ -21,6 +25,9 @@ const (
 	maxVersionFilesToCheck = 10
 )
 
+// ErrNoVersion is returned when no supported project file yields a version
+var ErrNoVersion = errors.New("no version found in any supported project files")
+
 // defaultSkipDirectories defines common directories to skip during file search
 // This is a package-level constant to prevent accidental modification
 var defaultSkipDirectories = []string{"node_modules", "vendor", "target", "build", "dist"}
@@ -33,15 +40,98 @@ type ExtractResult struct {
 	File          string `json:"file"`
 	MatchedBy     string `json:"matched_by"`
 	Success       bool   `json:"success"`
-	VersionSource string `json:"version_source,omitempty"` // "static", "static-constant", or "dynamic-git-tag"
+	VersionSource string `json:"version_source,omitempty"` // "static", "static-constant", "static-property", or "dynamic-git-tag"
 	GitTag        string `json:"git_tag,omitempty"`        // Original git tag if dynamic
+	GitDistance   int    `json:"git_distance,omitempty"`   // Commits since GitTag
+	GitCommit     string `json:"git_commit,omitempty"`     // HEAD commit if dynamic
+	GitDirty      bool   `json:"git_dirty,omitempty"`      // Uncommitted changes if dynamic
+	RelativePath  string `json:"relative_path,omitempty"`  // File relative to the search path (ExtractAll only)
+
+	// CanonicalVersion and SemverVersion are set for PEP 440 versions found
+	// in Python manifests: the normalised form, e.g. 1.0rc1 for 1.0-RC1, and
+	// its Semantic Versioning equivalent, e.g. 1.0.0-rc.1, which is empty
+	// when the version has none.
+	CanonicalVersion string `json:"canonical_version,omitempty"`
+	SemverVersion    string `json:"semver_version,omitempty"`
+
+	// Snapshot is set for Maven and Gradle versions that are snapshots, such
+	// as 1.2.3-SNAPSHOT.
+	Snapshot bool `json:"snapshot,omitempty"`
+
+	// ParsedVersion is Version parsed into its components, or nil when the
+	// version is not in a recognised form. Its flattened Fields are reported
+	// in JSON as major, minor, patch, prerelease, build and scheme.
+	ParsedVersion *version.Version `json:"-"`
+	*version.Fields
+
+	// Trace records the decisions made during extraction, when tracing is
+	// enabled with SetTrace
+	Trace *Trace `json:"trace,omitempty"`
+}
+
+// setParsedVersion parses the result's version into ParsedVersion and Fields.
+// Versions from Python manifests are parsed as PEP 440 first, and versions
+// from Maven and Gradle builds as Maven versions.
+func (r *ExtractResult) setParsedVersion() {
+	if r == nil || !r.Success {
+		return
+	}
+	r.ParsedVersion, r.Fields = nil, nil
+	r.CanonicalVersion, r.SemverVersion, r.Snapshot = "", "", false
+	if isMavenVersionFile(r.File) {
+		m := version.ParseMaven(r.Version)
+		r.Snapshot = m.IsSnapshot()
+		if parsed, err := m.Version(); err == nil {
+			r.ParsedVersion = parsed
+			r.Fields = parsed.Fields()
+			return
+		}
+	}
+	if isPythonVersionFile(r.File) {
+		if p, err := version.ParsePEP440(r.Version); err == nil {
+			r.ParsedVersion = p.Version()
+			r.Fields = r.ParsedVersion.Fields()
+			r.CanonicalVersion = p.String()
+			r.SemverVersion, _ = p.Semver()
+			return
+		}
+	}
+	if parsed, err := version.Parse(r.Version); err == nil {
+		r.ParsedVersion = parsed
+		r.Fields = parsed.Fields()
+	}
 }
 
 // VersionExtractor handles version extraction from project files
 type VersionExtractor struct {
-	config          *config.Config
-	dynamicFallback bool
-	skipDirectories []string
+	config            *config.Config
+	dynamicFallback   bool
+	skipDirectories   []string
+	gitVersionStyle   git.VersionStyle
+	tagPrefix         string
+	tagPolicy         git.TagPolicy
+	excludePrerelease bool
+	gitBackend        git.BackendKind
+	schemeRule        *version.SchemeRule
+	gradleProperties  map[string]string // Gradle project properties, as given with -P
+	tracing           bool
+	logger            *slog.Logger
+	fsys              fs.FS  // Project files are read from; nil for the host's
+	revision          string // Git revision project files are read from
+
+	// The state of one call, set only on the copy the call works with; see
+	// begin
+	tracer      *tracer         // Collects the trace of the extraction
+	ctx         context.Context // Cancels the extraction, when set
+	revisionDir string          // Host directory git runs in while a revision is read
+	failures    *[]fileFailure  // Collects files that fail extraction, when set
+}
+
+// fileFailure is a project file whose version could not be read
+type fileFailure struct {
+	file    string
+	project config.ProjectConfig
+	err     error
 }
 
 // New creates a new VersionExtractor instance
@@ -62,24 +152,84 @@ func NewWithOptions(cfg *config.Config, dynamicFallback bool) *VersionExtractor
 	}
 }
 
+// begin returns the extractor a call works with: a copy of e, so the state
+// of the call, such as its context, trace and open revision, is never seen
+// by calls running concurrently. With a revision set, the copy reads project
+// files from the revision's tree and hostPath is returned as a path in it. A
+// call made on behalf of another, as when Check calls ExtractAll, keeps the
+// other's state.
+func (e *VersionExtractor) begin(hostPath string) (*VersionExtractor, string, error) {
+	c := *e
+	if c.revision == "" || c.revisionDir != "" {
+		return &c, hostPath, nil
+	}
+	path, err := c.openRevision(hostPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return &c, path, nil
+}
+
+// withContext returns a copy of e for a call cancelled by ctx
+func (e *VersionExtractor) withContext(ctx context.Context) *VersionExtractor {
+	c := *e
+	c.ctx = ctx
+	return &c
+}
+
 // Extract attempts to extract version from the given directory or file path
 func (e *VersionExtractor) Extract(path string) (*ExtractResult, error) {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	e, path, err := e.begin(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := e.stat(path); errors.Is(err, fs.ErrNotExist) {
 		return nil, fmt.Errorf("path does not exist: %s", path)
 	}
 
 	// Check if this is a file or directory
-	fileInfo, err := os.Stat(path)
+	fileInfo, err := e.stat(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to stat path: %w", err)
 	}
 
+	if e.tracing {
+		e.tracer = &tracer{}
+	}
+
+	var result *ExtractResult
 	if !fileInfo.IsDir() {
-		return e.extractFromSpecificFile(path)
+		result, err = e.extractFromSpecificFile(path)
+	} else {
+		result, err = e.extractFromDirectory(path)
+	}
+	result.setParsedVersion()
+	if result != nil && e.tracer != nil {
+		result.Trace = &e.tracer.trace
+	}
+	return result, err
+}
+
+// ExtractContext is Extract, returning ctx's error as soon as it notices
+// that ctx is done. Cancellation is checked while the tree is indexed and
+// before each project type is tried, and stops a git command already
+// running.
+func (e *VersionExtractor) ExtractContext(ctx context.Context,
+	path string) (*ExtractResult, error) {
+	result, err := e.withContext(ctx).Extract(path)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
+	return result, err
+}
 
-	// Handle directory path (existing behavior)
-	return e.extractFromDirectory(path)
+// cancelled returns the error of the extraction's context once it is done
+func (e *VersionExtractor) cancelled() error {
+	if e.ctx == nil {
+		return nil
+	}
+	return e.ctx.Err()
 }
 
 // extractFromSpecificFile handles extraction from a specific file
@@ -100,10 +250,19 @@ func (e *VersionExtractor) extractFromSpecificFile(filePath string) (*ExtractRes
 			Success: false,
 		}, fmt.Errorf("file '%s' is of an unsupported type", fileName)
 	}
+	e.tracer.startProject(*matchingProject, []string{filePath})
+	e.tracer.startFile(filePath, e.validationName(matchingProject))
+
+	// A pom.xml or MSBuild project may take its version from properties
+	if result := e.propertyResult(matchingProject, filePath); result != nil {
+		e.traceResolved(result)
+		return result, nil
+	}
 
 	// Try to extract version from the specific file
-	version, matchedRegex, err := e.extractVersionFromFile(filePath, matchingProject.Regex)
+	version, matchedRegex, err := e.extractProjectVersion(filePath, matchingProject)
 	if err != nil {
+		e.tracer.outcome("error: %v", err)
 		return &ExtractResult{
 			Success: false,
 		}, fmt.Errorf("error processing file %s: %w", filePath, err)
@@ -111,6 +270,7 @@ func (e *VersionExtractor) extractFromSpecificFile(filePath string) (*ExtractRes
 
 	// If we found a version, use it (already cleaned and validated by extractVersionFromFile)
 	if version != "" {
+		e.tracer.outcome(Selected)
 		return &ExtractResult{
 			Version:       version,
 			ProjectType:   matchingProject.Type,
@@ -127,8 +287,8 @@ func (e *VersionExtractor) extractFromSpecificFile(filePath string) (*ExtractRes
 	// enclosing project root so buildSrc/build-logic definitions are found.
 	root := e.projectRootForFile(filePath)
 	if cv, matchedBy, cerr := e.resolveVersionConstant(filePath, root,
-		matchingProject.Regex); cerr == nil && cv != "" {
-		return &ExtractResult{
+		matchingProject.Regex, e.validatorFor(matchingProject, filePath)); cerr == nil && cv != "" {
+		result := &ExtractResult{
 			Version:       cv,
 			ProjectType:   matchingProject.Type,
 			Subtype:       matchingProject.Subtype,
@@ -136,9 +296,12 @@ func (e *VersionExtractor) extractFromSpecificFile(filePath string) (*ExtractRes
 			MatchedBy:     matchedBy,
 			Success:       true,
 			VersionSource: "static-constant",
-		}, nil
+		}
+		e.traceResolved(result)
+		return result, nil
 	}
 
+	e.tracer.outcome("no valid version")
 	return &ExtractResult{
 		Success: false,
 	}, fmt.Errorf("no valid version found in file: %s", filePath)
@@ -161,7 +324,7 @@ func (e *VersionExtractor) projectRootForFile(filePath string) string {
 	}
 	for i := 0; i < 8; i++ {
 		for _, marker := range markers {
-			if _, err := os.Stat(filepath.Join(current, marker)); err == nil {
+			if _, err := e.stat(filepath.Join(current, marker)); err == nil {
 				return current
 			}
 		}
@@ -182,10 +345,13 @@ func (e *VersionExtractor) extractFromDirectory(searchPath string) (*ExtractResu
 
 	// Try each project configuration in priority order
 	for _, project := range e.config.Projects {
+		if err := e.cancelled(); err != nil {
+			return nil, err
+		}
 		result, err := e.tryExtractFromProject(searchPath, project, idx)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to extract from %s: %v\n",
-				project.Type, err)
+			e.log().Warn("failed to extract version", "project", project.Type,
+				"error", err)
 			continue
 		}
 
@@ -196,7 +362,7 @@ func (e *VersionExtractor) extractFromDirectory(searchPath string) (*ExtractResu
 
 	return &ExtractResult{
 		Success: false,
-	}, fmt.Errorf("no version found in any supported project files")
+	}, ErrNoVersion
 }
 
 // tryExtractFromProject attempts version extraction for a specific project
@@ -204,104 +370,170 @@ func (e *VersionExtractor) extractFromDirectory(searchPath string) (*ExtractResu
 func (e *VersionExtractor) tryExtractFromProject(searchPath string,
 	project config.ProjectConfig, idx *fileIndex) (*ExtractResult, error) {
 
-	// Skip projects with empty regex patterns - they should use git tags
-	if len(project.Regex) == 0 {
+	// Find matching files
+	files := idx.match(project.File)
+	e.tracer.startProject(project, files)
+
+	// Skip projects with no regex patterns or structured lookup - they
+	// should use git tags
+	if len(project.Regex) == 0 && !project.HasStructuredLookup() {
 		// Early return if dynamic fallback is not enabled or project doesn't support it
 		// This avoids unnecessary file system operations
 		if !e.dynamicFallback || !project.SupportsDynamicVersioning {
+			e.tracer.skipProject("no regex patterns or structured lookup, " +
+				"and no dynamic versioning fallback")
 			return &ExtractResult{Success: false}, nil
 		}
 
 		// Check if the project file exists (e.g., go.mod for Go projects)
-		files := idx.match(project.File)
 		if len(files) == 0 {
 			return &ExtractResult{Success: false}, nil
 		}
 
 		// File exists but no regex patterns - use git fallback for version
-		gitResult := e.tryGitFallback(searchPath)
-		if gitResult == nil || !gitResult.Success {
-			return &ExtractResult{Success: false}, nil
+		e.tracer.startFile(files[0], "")
+		if result := e.gitFallbackResult(searchPath, project, files[0]); result != nil {
+			e.tracer.outcome(Selected)
+			return result, nil
 		}
-
-		return &ExtractResult{
-			Version:       gitResult.Version,
-			ProjectType:   project.Type,
-			Subtype:       project.Subtype,
-			File:          files[0],
-			MatchedBy:     "git-fallback",
-			Success:       true,
-			VersionSource: "dynamic-git-tag",
-			GitTag:        gitResult.Tag,
-		}, nil
+		e.tracer.outcome("no version tag")
+		return &ExtractResult{Success: false}, nil
 	}
 
-	// Find matching files
-	files := idx.match(project.File)
 	if len(files) == 0 {
 		return &ExtractResult{Success: false}, nil
 	}
 
 	// Try to extract version from each found file
 	for _, file := range files {
-		version, matchedRegex, err := e.extractVersionFromFile(file,
-			project.Regex)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Error processing %s: %v\n", file, err)
-			continue
+		if result := e.extractFromProjectFile(searchPath, project, file); result != nil {
+			return result, nil
+		}
+	}
+
+	return &ExtractResult{Success: false}, nil
+}
+
+// gitFallbackResult reports the git tag version for a file belonging to a
+// project type without regex patterns (e.g. go.mod), or nil when no version
+// tag is available.
+func (e *VersionExtractor) gitFallbackResult(searchPath string,
+	project config.ProjectConfig, file string) *ExtractResult {
+	gitResult := e.tryGitFallback(searchPath, project, file)
+	if gitResult == nil || !gitResult.Success {
+		return nil
+	}
+
+	return dynamicResult(project, file, "git-fallback", gitResult)
+}
+
+// dynamicResult builds the result for a version taken from a git tag
+func dynamicResult(project config.ProjectConfig, file, matchedBy string,
+	gitResult *git.GitTagResult) *ExtractResult {
+	return &ExtractResult{
+		Version:       gitResult.Version,
+		ProjectType:   project.Type,
+		Subtype:       project.Subtype,
+		File:          file,
+		MatchedBy:     matchedBy,
+		Success:       true,
+		VersionSource: "dynamic-git-tag",
+		GitTag:        gitResult.Tag,
+		GitDistance:   gitResult.Distance,
+		GitCommit:     gitResult.Commit,
+		GitDirty:      gitResult.Dirty,
+	}
+}
+
+// extractFromProjectFile attempts version extraction from a single file
+// matched by a project type with regex patterns. It returns nil when the file
+// yields no usable version, so callers can move on to the next candidate.
+func (e *VersionExtractor) extractFromProjectFile(searchPath string,
+	project config.ProjectConfig, file string) *ExtractResult {
+	e.tracer.startFile(file, e.validationName(&project))
+
+	// A pom.xml whose version resolves through properties or its parent is
+	// static, even though its ${...} reference looks dynamic to the
+	// indicators below.
+	if result := e.propertyResult(&project, file); result != nil {
+		e.traceResolved(result)
+		return result
+	}
+
+	version, matchedRegex, err := e.extractProjectVersion(file, &project)
+	if err != nil {
+		e.log().Warn("failed to process file", "file", file, "error", err)
+		e.tracer.outcome("error: %v", err)
+		if e.failures != nil {
+			*e.failures = append(*e.failures, fileFailure{file: file, project: project, err: err})
 		}
+		return nil
+	}
 
-		// Check for dynamic versioning first if project supports it
-		if e.dynamicFallback && project.SupportsDynamicVersioning && len(project.DynamicVersionIndicators) > 0 {
-			if isDynamic, err := e.detectDynamicVersioning(file, project.DynamicVersionIndicators); err == nil && isDynamic {
-				// Attempt Git fallback
-				if gitResult := e.tryGitFallback(searchPath); gitResult != nil && gitResult.Success {
-					return &ExtractResult{
-						Version:       gitResult.Version,
-						ProjectType:   project.Type,
-						Subtype:       project.Subtype,
-						File:          file,
-						MatchedBy:     "dynamic-git-tag",
-						Success:       true,
-						VersionSource: "dynamic-git-tag",
-						GitTag:        gitResult.Tag,
-					}, nil
-				}
+	// Check for dynamic versioning first if project supports it
+	if e.dynamicFallback && project.SupportsDynamicVersioning && len(project.DynamicVersionIndicators) > 0 {
+		if isDynamic, err := e.detectDynamicVersioning(file, project.DynamicVersionIndicators); err == nil && isDynamic {
+			// Attempt Git fallback
+			if gitResult := e.tryGitFallback(searchPath, project, file); gitResult != nil && gitResult.Success {
+				e.tracer.outcome(Selected)
+				return dynamicResult(project, file, "dynamic-git-tag", gitResult)
 			}
 		}
+	}
 
-		// If no dynamic versioning detected and we found a version, use it as static
-		if version != "" {
-			// Version is already cleaned and validated by extractVersionFromFile
-			return &ExtractResult{
-				Version:       version,
-				ProjectType:   project.Type,
-				Subtype:       project.Subtype,
-				File:          file,
-				MatchedBy:     matchedRegex,
-				Success:       true,
-				VersionSource: "static",
-			}, nil
+	// If no dynamic versioning detected and we found a version, use it as static
+	if version != "" {
+		// Version is already cleaned and validated by extractVersionFromFile
+		e.tracer.outcome(Selected)
+		return &ExtractResult{
+			Version:       version,
+			ProjectType:   project.Type,
+			Subtype:       project.Subtype,
+			File:          file,
+			MatchedBy:     matchedRegex,
+			Success:       true,
+			VersionSource: "static",
 		}
+	}
 
-		// Fallback: the version may be assigned from a named Kotlin/Gradle
-		// constant (e.g. `versionName = NEWPIPE_VERSION_NAME`) rather than a
-		// literal. Resolve it from buildSrc and similar locations.
-		if cv, matchedBy, cerr := e.resolveVersionConstant(file,
-			searchPath, project.Regex); cerr == nil && cv != "" {
-			return &ExtractResult{
-				Version:       cv,
-				ProjectType:   project.Type,
-				Subtype:       project.Subtype,
-				File:          file,
-				MatchedBy:     matchedBy,
-				Success:       true,
-				VersionSource: "static-constant",
-			}, nil
+	// Fallback: the version may be assigned from a named Kotlin/Gradle
+	// constant (e.g. `versionName = NEWPIPE_VERSION_NAME`) rather than a
+	// literal. Resolve it from buildSrc and similar locations.
+	if cv, matchedBy, cerr := e.resolveVersionConstant(file, searchPath,
+		project.Regex, e.validatorFor(&project, file)); cerr == nil && cv != "" {
+		result := &ExtractResult{
+			Version:       cv,
+			ProjectType:   project.Type,
+			Subtype:       project.Subtype,
+			File:          file,
+			MatchedBy:     matchedBy,
+			Success:       true,
+			VersionSource: "static-constant",
 		}
+		e.traceResolved(result)
+		return result
 	}
 
-	return &ExtractResult{Success: false}, nil
+	e.tracer.outcome("no valid version")
+	return nil
+}
+
+// traceResolved records a version resolved from build properties or a
+// constant, rather than matched in the file itself, as selected
+func (e *VersionExtractor) traceResolved(result *ExtractResult) {
+	e.tracer.match(MatchTrace{MatchedBy: result.MatchedBy, Version: result.Version})
+	e.tracer.outcome(Selected)
+}
+
+// propertyResult resolves manifests whose version is defined through build
+// tool properties: Maven POMs and MSBuild projects. It returns nil when the
+// file has a literal version of its own or cannot be resolved.
+func (e *VersionExtractor) propertyResult(project *config.ProjectConfig,
+	file string) *ExtractResult {
+	if result := e.mavenPropertyResult(project, file); result != nil {
+		return result
+	}
+	return e.msbuildPropertyResult(project, file)
 }
 
 // findProjectFiles returns files matching the given pattern beneath searchPath.
@@ -352,20 +584,200 @@ func (e *VersionExtractor) GetSkipDirectories() []string {
 	return e.skipDirectories
 }
 
-// tryGitFallback attempts to extract version from Git tags
-func (e *VersionExtractor) tryGitFallback(searchPath string) *git.GitTagResult {
-	gitExtractor := git.New(searchPath)
+// SetGitVersionStyle selects how versions taken from git tags describe
+// commits after the tag: "exact" (the default), "semver", "pep440" or
+// "maven-snapshot".
+func (e *VersionExtractor) SetGitVersionStyle(style string) error {
+	parsed, err := git.ParseVersionStyle(style)
+	if err != nil {
+		return err
+	}
+	e.gitVersionStyle = parsed
+	return nil
+}
+
+// SetTagPrefix restricts the git tag fallback to tags starting with prefix,
+// overriding each project's tag_prefix. The prefix may be a template over the
+// project directory, as for tag_prefix.
+func (e *VersionExtractor) SetTagPrefix(prefix string) error {
+	if _, err := git.ExpandTagPrefix(prefix, ""); err != nil {
+		return err
+	}
+	e.tagPrefix = prefix
+	return nil
+}
+
+// SetTagPolicy selects which git tag the fallback takes as the latest:
+// "nearest" (the default), "highest-reachable" or "highest-any".
+func (e *VersionExtractor) SetTagPolicy(policy string) error {
+	parsed, err := git.ParseTagPolicy(policy)
+	if err != nil {
+		return err
+	}
+	e.tagPolicy = parsed
+	return nil
+}
+
+// SetExcludePrerelease makes the git tag fallback skip pre-release tags
+func (e *VersionExtractor) SetExcludePrerelease(exclude bool) {
+	e.excludePrerelease = exclude
+}
+
+// SetLogger sets the logger for diagnostics, such as files that cannot be
+// read and invalid patterns. A nil logger, the default, uses slog.Default.
+func (e *VersionExtractor) SetLogger(logger *slog.Logger) {
+	e.logger = logger
+}
+
+// log returns the logger for diagnostics
+func (e *VersionExtractor) log() *slog.Logger {
+	if e.logger == nil {
+		return slog.Default()
+	}
+	return e.logger
+}
+
+// SetTrace makes Extract record the decisions it makes in the result's
+// Trace
+func (e *VersionExtractor) SetTrace(enabled bool) {
+	e.tracing = enabled
+}
+
+// SetGitBackend selects how the git tag fallback reads the repository:
+// "auto" (the default), "exec" or "native".
+func (e *VersionExtractor) SetGitBackend(backend string) error {
+	parsed, err := git.ParseBackendKind(backend)
+	if err != nil {
+		return err
+	}
+	e.gitBackend = parsed
+	return nil
+}
+
+// newGitExtractor returns a git extractor for dir using the selected backend.
+// While a revision is read, dir is a path in its tree, so git runs in the
+// host directory the revision was opened from and looks tags up relative to
+// the revision.
+func (e *VersionExtractor) newGitExtractor(dir string) *git.GitVersionExtractor {
+	if e.revisionDir != "" {
+		dir = e.revisionDir
+	}
+	gitExtractor := git.New(dir)
+	if e.gitBackend != git.BackendAuto && e.gitBackend != "" {
+		gitExtractor.SetBackend(git.NewBackend(e.gitBackend, dir))
+	}
+	if e.revisionDir != "" {
+		gitExtractor.SetRevision(e.revision)
+	}
+	gitExtractor.SetContext(e.ctx)
+	return gitExtractor
+}
+
+// SetScheme validates every matched version against a version scheme,
+// overriding each project's version_scheme. The scheme is given as for
+// version_scheme, e.g. "semver" or "calver:YYYY.0M.MICRO"; an empty scheme
+// restores the per-project schemes.
+func (e *VersionExtractor) SetScheme(scheme string) error {
+	if scheme == "" {
+		e.schemeRule = nil
+		return nil
+	}
+	rule, err := version.ParseSchemeRule(scheme)
+	if err != nil {
+		return err
+	}
+	e.schemeRule = rule
+	return nil
+}
+
+// goModuleTagPrefix is Go's tag convention for a module in a repository
+// subdirectory: services/api/go.mod is released as services/api/vX.Y.Z.
+const goModuleTagPrefix = "{{.Path}}/"
+
+// tagPrefixFor returns the tag prefix for a project file: the --tag-prefix
+// override, the project's tag_prefix, or the Go module convention for go.mod
+// files, expanded for the file's directory. Tags without the conventional
+// prefix are used when a module has none with it.
+func (e *VersionExtractor) tagPrefixFor(searchPath string,
+	project config.ProjectConfig, file string) (string, error) {
+	prefix := e.tagPrefix
+	if prefix == "" {
+		prefix = project.TagPrefix
+	}
+	if prefix == "" && filepath.Base(file) == "go.mod" {
+		prefix = goModuleTagPrefix
+	}
+	if !strings.Contains(prefix, "{{") {
+		return prefix, nil
+	}
+
+	dir := searchPath
+	if file != "" {
+		dir = filepath.Dir(file)
+	}
+	relPath, err := e.gitRelativePath(dir)
+	if err != nil {
+		return "", err
+	}
+	return git.ExpandTagPrefix(prefix, relPath)
+}
+
+// tryGitFallback attempts to extract version from Git tags for a project
+// file, considering only the project's tags when a tag prefix applies
+func (e *VersionExtractor) tryGitFallback(searchPath string,
+	project config.ProjectConfig, file string) *git.GitTagResult {
+	trace := &GitTrace{Policy: string(e.tagPolicy)}
+	if trace.Policy == "" {
+		trace.Policy = string(git.PolicyNearest)
+	}
+	e.tracer.git(trace)
+	if !e.onHost() && e.revisionDir == "" {
+		trace.Error = "git tags are " + errNoHostFS.Error()
+		return &git.GitTagResult{Success: false}
+	}
+	gitExtractor := e.newGitExtractor(searchPath)
+	if prefix, err := e.tagPrefixFor(searchPath, project, file); err != nil {
+		if gitExtractor.IsGitRepository() {
+			e.log().Warn("cannot determine tag prefix", "file", file, "error", err)
+		}
+	} else {
+		gitExtractor.SetTagPrefix(prefix)
+		trace.TagPrefix = prefix
+	}
+	gitExtractor.SetTagPolicy(e.tagPolicy)
+	gitExtractor.SetExcludePrerelease(e.excludePrerelease)
+	if isMavenVersionFile(file) || project.VersionScheme == "maven" {
+		gitExtractor.SetTagOrdering(git.OrderMaven)
+	}
 
 	// Get the latest version tag. Local tags are tried first; if none are
 	// present (e.g. a shallow clone) the lookup falls back to `git ls-remote`,
 	// which is far cheaper than fetching tag objects over the network.
 	result, err := gitExtractor.GetLatestVersionTag()
+	if err != nil && trace.TagPrefix != "" && e.tagPrefix == "" && project.TagPrefix == "" {
+		// The Go module convention is only implied: a nested module released
+		// with the repository's plain tags uses them
+		gitExtractor.SetTagPrefix("")
+		trace.TagPrefix = ""
+		result, err = gitExtractor.GetLatestVersionTag()
+	}
 	if err != nil {
+		trace.Error = err.Error()
 		return &git.GitTagResult{
 			Success:   false,
 			IsGitRepo: gitExtractor.IsGitRepository(),
 		}
 	}
 
+	// Versions ahead of the tag are formatted in the configured style; a
+	// tag the style cannot express is reported as is.
+	version, err := result.DevVersion(e.gitVersionStyle)
+	if err != nil {
+		e.log().Warn("using tag version", "version", result.Version, "error", err)
+	} else {
+		result.Version = version
+	}
+	trace.Strategy, trace.Tag, trace.Version = result.Strategy, result.Tag, result.Version
+
 	return result
 }
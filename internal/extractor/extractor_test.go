@@ -4,7 +4,10 @@
 package extractor
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -1107,7 +1110,7 @@ func TestTryGitFallback(t *testing.T) {
 
 	// Test with non-git directory
 	tmpDir := t.TempDir()
-	result := extractor.tryGitFallback(tmpDir)
+	result := extractor.tryGitFallback(tmpDir, config.ProjectConfig{}, "")
 
 	if result == nil {
 		t.Fatal("Expected non-nil result")
@@ -1449,6 +1452,150 @@ func TestDynamicVersioningWithGitRepo(t *testing.T) {
 	}
 }
 
+// TestGitVersionStyle checks that commits after the tag are reported in the
+// configured dev version style.
+func TestGitVersionStyle(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping git integration test")
+	}
+
+	tmpDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		if err := runGitCommand(tmpDir, args...); err != nil {
+			t.Skipf("Failed to set up git repo: %v", err)
+		}
+	}
+
+	packageJSON := filepath.Join(tmpDir, "package.json")
+	writeFile(t, packageJSON, `{"name": "styled", "version": "0.0.0-development"}`)
+	if err := runGitCommand(tmpDir, "add", "package.json"); err != nil {
+		t.Skipf("Failed to add file: %v", err)
+	}
+	if err := runGitCommand(tmpDir, "commit", "-m", "Initial commit"); err != nil {
+		t.Skipf("Failed to commit: %v", err)
+	}
+	if err := runGitCommand(tmpDir, "tag", "-a", "v1.2.3", "-m", "Test tag"); err != nil {
+		t.Skipf("Failed to create tag: %v", err)
+	}
+	if err := runGitCommand(tmpDir, "commit", "--allow-empty", "-m", "Next"); err != nil {
+		t.Skipf("Failed to commit: %v", err)
+	}
+
+	extractor := NewWithOptions(createTestConfigForLanguage("JavaScript", "npm", "package.json"), true)
+	if err := extractor.SetGitVersionStyle("bogus"); err == nil {
+		t.Error("expected an unknown style to be rejected")
+	}
+
+	tests := []struct {
+		style  string
+		prefix string
+	}{
+		{"exact", "1.2.3"},
+		{"semver", "1.2.4-dev.1+g"},
+		{"pep440", "1.2.4.dev1+g"},
+		{"maven-snapshot", "1.2.4-SNAPSHOT"},
+	}
+	for _, tt := range tests {
+		if err := extractor.SetGitVersionStyle(tt.style); err != nil {
+			t.Fatalf("SetGitVersionStyle(%s) failed: %v", tt.style, err)
+		}
+		result, err := extractor.Extract(tmpDir)
+		if err != nil {
+			t.Fatalf("Expected successful extraction from git tags: %v", err)
+		}
+		if !strings.HasPrefix(result.Version, tt.prefix) {
+			t.Errorf("style %s: expected version starting %s, got %s", tt.style, tt.prefix, result.Version)
+		}
+		if result.GitTag != "v1.2.3" || result.GitDistance != 1 || result.GitCommit == "" || result.GitDirty {
+			t.Errorf("style %s: unexpected git details: %+v", tt.style, result)
+		}
+	}
+}
+
+// TestTagPrefixFallback checks that the git fallback only considers the
+// tags of the component being extracted in a monorepo.
+func TestTagPrefixFallback(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping git integration test")
+	}
+
+	tmpDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		if err := runGitCommand(tmpDir, args...); err != nil {
+			t.Skipf("Failed to set up git repo: %v", err)
+		}
+	}
+
+	writeFile(t, filepath.Join(tmpDir, "go.mod"), "module github.com/test/root\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(tmpDir, "services", "api", "go.mod"),
+		"module github.com/test/root/services/api\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(tmpDir, "tools", "go.mod"),
+		"module github.com/test/root/tools\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(tmpDir, "packages", "web", "package.json"),
+		`{"name": "web", "version": "0.0.0-development"}`)
+	if err := runGitCommand(tmpDir, "add", "."); err != nil {
+		t.Skipf("Failed to add files: %v", err)
+	}
+	if err := runGitCommand(tmpDir, "commit", "-m", "Initial commit"); err != nil {
+		t.Skipf("Failed to commit: %v", err)
+	}
+	for _, tag := range []string{"v1.0.0", "services/api/v2.3.0", "web-v1.9.0"} {
+		if err := runGitCommand(tmpDir, "tag", "-a", tag, "-m", tag); err != nil {
+			t.Skipf("Failed to create tag: %v", err)
+		}
+	}
+
+	goConfig := &config.Config{Projects: []config.ProjectConfig{{
+		Type:                      "Go",
+		Subtype:                   "Go Module",
+		File:                      "go.mod",
+		Priority:                  1,
+		SupportsDynamicVersioning: true,
+		DynamicVersionIndicators: []config.DynamicVersionIndicator{
+			{Path: "go.mod", Field: "module", Contains: []string{"github.com"}},
+		},
+	}}}
+	jsConfig := createTestConfigForLanguage("JavaScript", "npm", "package.json")
+	jsConfig.Projects[0].TagPrefix = "{{.Name}}-"
+
+	tests := []struct {
+		name      string
+		cfg       *config.Config
+		path      string
+		tagPrefix string
+		wantTag   string
+	}{
+		{"root Go module", goConfig, tmpDir, "", "v1.0.0"},
+		{"Go module in subdirectory", goConfig, filepath.Join(tmpDir, "services", "api"), "", "services/api/v2.3.0"},
+		{"Go module without prefixed tags", goConfig, filepath.Join(tmpDir, "tools"), "", "v1.0.0"},
+		{"templated tag_prefix", jsConfig, filepath.Join(tmpDir, "packages", "web"), "", "web-v1.9.0"},
+		{"--tag-prefix overrides", jsConfig, filepath.Join(tmpDir, "packages", "web"), "services/api/", "services/api/v2.3.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor := New(tt.cfg)
+			if err := extractor.SetTagPrefix(tt.tagPrefix); err != nil {
+				t.Fatal(err)
+			}
+			result, err := extractor.Extract(tt.path)
+			if err != nil {
+				t.Fatalf("Expected successful extraction from git tags: %v", err)
+			}
+			if result.GitTag != tt.wantTag || result.VersionSource != "dynamic-git-tag" {
+				t.Errorf("expected tag %s, got %+v", tt.wantTag, result)
+			}
+		})
+	}
+}
+
 func createTestConfigForLanguage(language, subtype, filename string) *config.Config {
 	var dynamicIndicators []config.DynamicVersionIndicator
 	var supportsDynamic bool
@@ -2247,3 +2394,100 @@ func runGitCommand(dir string, args ...string) error {
 	cmd.Dir = dir
 	return cmd.Run()
 }
+
+// TestVersionScheme checks that matches are validated against the scheme of
+// the project type they were matched for, and against the --scheme override.
+func TestVersionScheme(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "lib.h"),
+		"#define BUILD_YEAR_VERSION \"2024\"\n#define LIB_VERSION \"1.4\"\n")
+	writeFile(t, filepath.Join(tmpDir, "package.json"), `{"version": "1.0"}`)
+
+	header := config.ProjectConfig{
+		Type:  "C",
+		File:  "*.h",
+		Regex: []string{`#define\s+\w*VERSION\w*\s+"([^"]+)"`},
+	}
+	npm := config.ProjectConfig{
+		Type:     "JavaScript",
+		File:     "package.json",
+		Path:     "$.version",
+		Regex:    []string{`"version":\s*"([^"]+)"`},
+		Priority: 1,
+	}
+	extract := func(project config.ProjectConfig, scheme string) string {
+		t.Helper()
+		ext := New(&config.Config{Projects: []config.ProjectConfig{project}})
+		if err := ext.SetScheme(scheme); err != nil {
+			t.Fatalf("SetScheme(%q) failed: %v", scheme, err)
+		}
+		result, err := ext.Extract(tmpDir)
+		if err != nil {
+			return ""
+		}
+		return result.Version
+	}
+
+	// Without a scheme the bare year is accepted as CalVer
+	if got := extract(header, ""); got != "2024" {
+		t.Errorf("default validation: got %q, want 2024", got)
+	}
+	header.VersionScheme = "loose"
+	if got := extract(header, ""); got != "1.4" {
+		t.Errorf("loose scheme: got %q, want 1.4", got)
+	}
+	header.VersionScheme = "calver:YYYY"
+	if got := extract(header, ""); got != "2024" {
+		t.Errorf("calver scheme: got %q, want 2024", got)
+	}
+	if got := extract(header, "semver"); got != "" {
+		t.Errorf("semver override: got %q, want no version", got)
+	}
+
+	// Structured lookups and regex patterns are validated alike
+	if got := extract(npm, ""); got != "1.0" {
+		t.Errorf("default validation: got %q, want 1.0", got)
+	}
+	npm.VersionScheme = "semver"
+	if got := extract(npm, ""); got != "" {
+		t.Errorf("semver scheme: got %q, want no version", got)
+	}
+	if got := extract(npm, "loose"); got != "1.0" {
+		t.Errorf("loose override: got %q, want 1.0", got)
+	}
+
+	if err := New(&config.Config{}).SetScheme("calver:YYY"); err == nil {
+		t.Error("SetScheme should reject an invalid calver format")
+	}
+}
+
+// TestSetLogger checks that diagnostics go to the injected logger
+func TestSetLogger(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "VERSION"), []byte("1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Projects: []config.ProjectConfig{
+		{Type: "Generic", File: "VERSION", Regex: []string{`^(\S+`, `^(\S+)$`}},
+	}}
+
+	var logs bytes.Buffer
+	ext := New(cfg)
+	ext.SetLogger(slog.New(slog.NewJSONHandler(&logs, nil)))
+	result, err := ext.Extract(tmpDir)
+	if err != nil || result.Version != "1.0.0" {
+		t.Fatalf("Expected version 1.0.0, got %+v, %v", result, err)
+	}
+
+	var event struct {
+		Level   string `json:"level"`
+		Msg     string `json:"msg"`
+		Pattern string `json:"pattern"`
+	}
+	if err := json.Unmarshal(logs.Bytes(), &event); err != nil {
+		t.Fatalf("Expected one JSON log event, got %q: %v", logs.String(), err)
+	}
+	if event.Level != "WARN" || event.Msg != "invalid regex pattern" || event.Pattern != `^(\S+` {
+		t.Errorf("Unexpected log event: %+v", event)
+	}
+}
@@ -4,7 +4,7 @@
 package extractor
 
 import (
-	"os"
+	"io/fs"
 	"path/filepath"
 	"strings"
 )
@@ -29,25 +29,28 @@ func (e *VersionExtractor) buildFileIndex(searchPath string) *fileIndex {
 	// otherwise unclean path.
 	searchPath = filepath.Clean(searchPath)
 	idx := &fileIndex{root: searchPath, byName: make(map[string][]string)}
-	_ = filepath.Walk(searchPath, func(path string, info os.FileInfo,
+	_ = fs.WalkDir(e.filesystem(), searchPath, func(path string, d fs.DirEntry,
 		err error) error {
 		if err != nil {
 			return nil // continue despite errors, matching prior behaviour
 		}
-		if info.IsDir() {
+		if e.cancelled() != nil {
+			return fs.SkipAll // The caller reports the cancellation
+		}
+		if d.IsDir() {
 			// Never skip the search root itself, even if its name is dotted.
-			if path != searchPath && strings.HasPrefix(info.Name(), ".") {
-				return filepath.SkipDir
+			if path != searchPath && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
 			}
 			for _, skip := range e.skipDirectories {
-				if info.Name() == skip {
-					return filepath.SkipDir
+				if d.Name() == skip {
+					return fs.SkipDir
 				}
 			}
 			return nil
 		}
 		idx.all = append(idx.all, path)
-		idx.byName[info.Name()] = append(idx.byName[info.Name()], path)
+		idx.byName[d.Name()] = append(idx.byName[d.Name()], path)
 		return nil
 	})
 	return idx
@@ -6,7 +6,7 @@ package extractor
 import (
 	"bufio"
 	"fmt"
-	"os"
+	"io/fs"
 	"strings"
 )
 
@@ -21,19 +21,35 @@ type FileReaderInterface interface {
 }
 
 // FileReader provides centralized file reading utilities
-type FileReader struct{}
+type FileReader struct {
+	fsys fs.FS // The file system files are read from; nil for the host's
+}
 
-// NewFileReader creates a new FileReader instance
+// NewFileReader creates a new FileReader instance reading the host file
+// system
 func NewFileReader() FileReaderInterface {
 	return &FileReader{}
 }
 
+// NewFileReaderFS creates a new FileReader instance reading fsys
+func NewFileReaderFS(fsys fs.FS) FileReaderInterface {
+	return &FileReader{fsys: fsys}
+}
+
+// filesystem returns the file system files are read from
+func (fr *FileReader) filesystem() fs.FS {
+	if fr.fsys == nil {
+		return hostFS{}
+	}
+	return fr.fsys
+}
+
 // Global instance for use throughout the package
 var fileReader FileReaderInterface = NewFileReader()
 
 // ValidateFileSize checks if file size is within acceptable limits
 func (fr *FileReader) ValidateFileSize(filePath string) error {
-	fileInfo, err := os.Stat(filePath)
+	fileInfo, err := fs.Stat(fr.filesystem(), filePath)
 	if err != nil {
 		return fmt.Errorf("failed to stat file: %w", err)
 	}
@@ -51,7 +67,7 @@ func (fr *FileReader) ReadFileContent(filePath string, normalizeContent bool) (s
 		return "", err
 	}
 
-	content, err := os.ReadFile(filePath)
+	content, err := fs.ReadFile(fr.filesystem(), filePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
@@ -75,7 +91,7 @@ func (fr *FileReader) ProcessFileLineByLine(filePath string, processor func(stri
 		return "", err
 	}
 
-	file, err := os.Open(filePath)
+	file, err := fr.filesystem().Open(filePath)
 	if err != nil {
 		return "", fmt.Errorf("failed to open file: %w", err)
 	}
@@ -126,7 +142,7 @@ func (fr *FileReader) ReadFileContentWithFallback(filePath string, lineProcessor
 
 // GetFileSize returns the size of the file in bytes
 func (fr *FileReader) GetFileSize(filePath string) (int64, error) {
-	fileInfo, err := os.Stat(filePath)
+	fileInfo, err := fs.Stat(fr.filesystem(), filePath)
 	if err != nil {
 		return 0, fmt.Errorf("failed to stat file: %w", err)
 	}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// errNoHostFS is returned for operations that need files on disk, such as
+// the git tag fallback, when the extractor reads from another file system
+var errNoHostFS = errors.New("not available when reading from a file system other than the host's")
+
+// hostFS serves the host file system as os.DirFS does, except that names
+// are paths as the operating system spells them, relative to the working
+// directory or absolute. Extraction follows references above the search
+// path, such as a Maven parent's relativePath or a Directory.Build.props in
+// a parent directory, which a file system rooted at the search path would
+// not reach.
+type hostFS struct{}
+
+// Open opens the named file
+func (hostFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+// Stat describes the named file
+func (hostFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// ReadFile reads the named file
+func (hostFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// ReadDir lists the named directory, sorted by file name
+func (hostFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// slashFS serves an fs.FS to code written for operating system paths. The
+// wrapped file system only accepts clean, slash-separated paths, so names
+// are converted on the way in; paths above its root, such as a parent
+// POM's, do not exist.
+type slashFS struct {
+	fsys fs.FS
+}
+
+// name converts an operating system path to a path in the file system
+func (s slashFS) name(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
+
+// Open opens the named file
+func (s slashFS) Open(name string) (fs.File, error) {
+	return s.fsys.Open(s.name(name))
+}
+
+// Stat describes the named file
+func (s slashFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(s.fsys, s.name(name))
+}
+
+// ReadFile reads the named file
+func (s slashFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, s.name(name))
+}
+
+// ReadDir lists the named directory, sorted by file name
+func (s slashFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(s.fsys, s.name(name))
+}
+
+// SetFS makes the extractor read project files from fsys instead of the
+// host file system, e.g. a release archive or an fstest.MapFS. Paths given
+// to Extract, ExtractAll and Check are then paths in fsys, such as "." for
+// its root. The git tag fallback needs a repository on disk, so it finds no
+// tags, and Bump can only preview changes. A nil fsys restores the host
+// file system.
+func (e *VersionExtractor) SetFS(fsys fs.FS) {
+	if fsys == nil {
+		e.fsys = nil
+		return
+	}
+	e.fsys = slashFS{fsys: fsys}
+}
+
+// filesystem returns the file system project files are read from. It is
+// safe to call on a nil extractor, which reads the host file system.
+func (e *VersionExtractor) filesystem() fs.FS {
+	if e == nil || e.fsys == nil {
+		return hostFS{}
+	}
+	return e.fsys
+}
+
+// onHost reports whether project files are read from the host file system
+func (e *VersionExtractor) onHost() bool {
+	return e == nil || e.fsys == nil
+}
+
+// files returns the reader for project files: the package's file reader
+// for the host file system, or one reading from the extractor's file system
+func (e *VersionExtractor) files() FileReaderInterface {
+	if e.onHost() {
+		return fileReader
+	}
+	return NewFileReaderFS(e.fsys)
+}
+
+// stat describes a file in the extractor's file system
+func (e *VersionExtractor) stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(e.filesystem(), name)
+}
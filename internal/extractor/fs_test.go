@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// mapFS returns an in-memory file system holding files
+func mapFS(files map[string]string) fstest.MapFS {
+	fsys := make(fstest.MapFS, len(files))
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content), Mode: 0o644}
+	}
+	return fsys
+}
+
+func TestExtractFS(t *testing.T) {
+	fsys := mapFS(map[string]string{
+		"package.json":                `{"name": "web", "version": "1.9.0"}`,
+		"node_modules/x/package.json": `{"version": "0.0.1"}`,
+		"sdk/pyproject.toml":          "[project]\nname = \"sdk\"\nversion = \"0.4.2\"\n",
+	})
+	e := New(monorepoConfig())
+	e.SetFS(fsys)
+
+	result, err := e.Extract(".")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "1.9.0" || result.File != "package.json" {
+		t.Errorf("Expected 1.9.0 from package.json, got %s from %s", result.Version, result.File)
+	}
+
+	result, err = e.Extract("sdk/pyproject.toml")
+	if err != nil {
+		t.Fatalf("Extract of a file failed: %v", err)
+	}
+	if result.Version != "0.4.2" {
+		t.Errorf("Expected 0.4.2, got %s", result.Version)
+	}
+
+	if _, err := e.Extract("missing"); err == nil || !strings.Contains(err.Error(), "path does not exist") {
+		t.Errorf("Expected a missing path error, got %v", err)
+	}
+}
+
+func TestExtractAllFS(t *testing.T) {
+	// Hidden and skipped directories are not searched
+	e := New(monorepoConfig())
+	e.SetFS(mapFS(map[string]string{
+		"web/package.json":      `{"name": "web", "version": "1.9.0"}`,
+		"charts/api/Chart.yaml": "apiVersion: v2\nname: api\nversion: 2.3.0\n",
+		".hidden/package.json":  `{"version": "9.9.9"}`,
+		"web/dist/package.json": `{"version": "9.9.9"}`,
+		"sdk/pyproject.toml":    "[project]\nname = \"sdk\"\nversion = \"0.4.2\"\n",
+	}))
+
+	results, err := e.ExtractAll(".")
+	if err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+	found := make(map[string]string)
+	for _, result := range results {
+		found[result.RelativePath] = result.Version
+	}
+	want := map[string]string{
+		"web/package.json":      "1.9.0",
+		"charts/api/Chart.yaml": "2.3.0",
+		"sdk/pyproject.toml":    "0.4.2",
+	}
+	if len(found) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, found)
+	}
+	for file, version := range want {
+		if found[file] != version {
+			t.Errorf("%s: expected %s, got %s", file, version, found[file])
+		}
+	}
+}
+
+// TestMavenParentFS resolves a module's version through its parent POM,
+// which is found by relativePath in the same file system
+func TestMavenParentFS(t *testing.T) {
+	e := NewWithOptions(mavenConfig(), false)
+	e.SetFS(mapFS(map[string]string{
+		"pom.xml": `<project>
+  <artifactId>parent</artifactId>
+  <version>${revision}</version>
+  <properties><revision>3.0.2</revision></properties>
+</project>`,
+		"core/pom.xml": `<project>
+  <parent>
+    <artifactId>parent</artifactId>
+    <version>${revision}</version>
+  </parent>
+  <artifactId>core</artifactId>
+</project>`,
+	}))
+
+	result, err := e.Extract("core/pom.xml")
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "3.0.2" || result.VersionSource != "static-property" {
+		t.Errorf("Expected static-property 3.0.2, got %s %s", result.VersionSource, result.Version)
+	}
+}
+
+func TestGitFallbackFS(t *testing.T) {
+	cfg := &config.Config{
+		Projects: []config.ProjectConfig{
+			{
+				Type:                      "Go",
+				File:                      "go.mod",
+				SupportsDynamicVersioning: true,
+				Priority:                  1,
+			},
+		},
+	}
+	e := New(cfg)
+	e.SetFS(mapFS(map[string]string{"go.mod": "module example.com/app\n"}))
+	e.SetTrace(true)
+
+	result, err := e.Extract(".")
+	if err == nil {
+		t.Fatalf("Expected no version, got %s", result.Version)
+	}
+	attempts := result.Trace.Projects[0].Attempts
+	if len(attempts) != 1 || attempts[0].Git == nil ||
+		!strings.Contains(attempts[0].Git.Error, "not available") {
+		t.Errorf("Expected the git fallback to be unavailable, got %+v", attempts)
+	}
+}
+
+func TestBumpFS(t *testing.T) {
+	e := New(monorepoConfig())
+	e.SetFS(mapFS(map[string]string{"package.json": `{"version": "1.9.0"}`}))
+
+	result, err := e.Bump(".", BumpOptions{Level: BumpMinor, DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if result.NewVersion != "1.10.0" || result.Diff == "" {
+		t.Errorf("Expected a diff to 1.10.0, got %+v", result)
+	}
+
+	if _, err := e.Bump(".", BumpOptions{Level: BumpMinor}); err == nil {
+		t.Error("Expected bumping in a file system other than the host's to fail")
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// findGradleProperty looks up name in gradle.properties content, which uses
+// the Java properties format: `key=value`, `key: value` or `key value`, with
+// `#` and `!` comment lines. It returns the value and its span in content.
+// Line continuations are not supported; version properties do not use them.
+func findGradleProperty(content, name string) (string, int, int, bool) {
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		lineStart := offset
+		offset += len(line)
+
+		text := strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimLeft(text, " \t\f")
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+			continue
+		}
+		keyStart := len(text) - len(trimmed)
+
+		// The key ends at the first unescaped separator or whitespace.
+		keyEnd := strings.IndexAny(trimmed, "=: \t\f")
+		if keyEnd < 0 {
+			keyEnd = len(trimmed)
+		}
+		if trimmed[:keyEnd] != name {
+			continue
+		}
+
+		// Skip whitespace, at most one separator, then whitespace again.
+		rest := keyStart + keyEnd
+		for rest < len(text) && strings.ContainsRune(" \t\f", rune(text[rest])) {
+			rest++
+		}
+		if rest < len(text) && (text[rest] == '=' || text[rest] == ':') {
+			rest++
+		}
+		for rest < len(text) && strings.ContainsRune(" \t\f", rune(text[rest])) {
+			rest++
+		}
+
+		value := strings.TrimRight(text[rest:], " \t\f")
+		return value, lineStart + rest, lineStart + rest + len(value), true
+	}
+	return "", -1, -1, false
+}
+
+// findCatalogVersion looks up a version accessor alias (e.g. "kotlin.core"
+// from libs.versions.kotlin.core) in the [versions] table of a Gradle version
+// catalog, returning the matching key as written and its value. Only plain
+// string versions are supported, not rich {strictly = ...} declarations.
+func findCatalogVersion(content, alias string) (string, string, bool) {
+	var catalog struct {
+		Versions map[string]interface{} `toml:"versions"`
+	}
+	if _, err := toml.Decode(content, &catalog); err != nil {
+		return "", "", false
+	}
+
+	want := normalizeCatalogAlias(alias)
+	for key, raw := range catalog.Versions {
+		value, ok := raw.(string)
+		if ok && normalizeCatalogAlias(key) == want {
+			return key, value, true
+		}
+	}
+	return "", "", false
+}
+
+// normalizeCatalogAlias maps the separators Gradle treats as equivalent in
+// catalog aliases ("-", "_" and ".") to a single form.
+func normalizeCatalogAlias(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
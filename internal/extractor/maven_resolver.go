@@ -0,0 +1,373 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// Bounds for Maven resolution, so a malformed or cyclic project cannot make
+// extraction loop: parent POMs followed, nested ${...} substitutions, and
+// directories searched upwards for .mvn/maven.config.
+const (
+	maxMavenParentDepth   = 10
+	maxMavenPropertyDepth = 10
+	maxMavenConfigSearch  = 8
+)
+
+// mavenPropertyRef matches a ${name} property reference
+var mavenPropertyRef = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// mavenCIFriendlyProperties are the CI-friendly version properties. Maven
+// projects routinely leave sha1 and changelist undefined, meaning empty.
+var mavenCIFriendlyProperties = map[string]bool{
+	"revision": true, "sha1": true, "changelist": true,
+}
+
+// mavenPOM holds the parts of a pom.xml needed to resolve its version
+type mavenPOM struct {
+	GroupID    string          `xml:"groupId"`
+	ArtifactID string          `xml:"artifactId"`
+	Version    string          `xml:"version"`
+	Parent     *mavenParent    `xml:"parent"`
+	Properties mavenProperties `xml:"properties"`
+}
+
+// mavenParent is the <parent> element of a pom.xml
+type mavenParent struct {
+	GroupID      string  `xml:"groupId"`
+	ArtifactID   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"`
+}
+
+// mavenProperties decodes <properties> into an ordered list of name/value
+// pairs; the element names are the property names.
+type mavenProperties []mavenProperty
+
+// mavenProperty is a single <properties> entry
+type mavenProperty struct {
+	name, value string
+}
+
+// UnmarshalXML collects each child element of <properties> as a property
+func (p *mavenProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			*p = append(*p, mavenProperty{t.Name.Local, strings.TrimSpace(value)})
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// mavenPropertyValue is a resolved property value and where it came from
+type mavenPropertyValue struct {
+	value  string
+	source string // file that defined the property
+	config bool   // defined by -D in .mvn/maven.config
+}
+
+// mavenResolution is the outcome of resolving a pom.xml version
+type mavenResolution struct {
+	version string
+	chain   []string // human-readable resolution steps, in order
+	// Where the version is defined, when it is a single literal that can be
+	// rewritten: the file, and the XML path or maven.config property holding
+	// the value.
+	defFile     string
+	defXPath    string
+	defProperty string
+	// inherited is set when the version is a literal <parent> version, with
+	// parentFile the parent POM, or "" when it is not on disk
+	inherited  bool
+	parentFile string
+}
+
+// resolveMavenVersion resolves the effective version of a pom.xml whose
+// <version> is a ${...} expression or is inherited from <parent>. It
+// evaluates <properties> from the POM and its parents (walked through
+// <relativePath>, defaulting to ../pom.xml), -D definitions in the nearest
+// .mvn/maven.config, and the CI-friendly ${revision}${sha1}${changelist}
+// properties. It returns nil when the POM declares a literal version of its
+// own, which regular extraction handles.
+func (e *VersionExtractor) resolveMavenVersion(pomPath string,
+	valid versionValidator) (*mavenResolution, error) {
+	pom, err := e.parseMavenPOM(pomPath)
+	if err != nil {
+		return nil, err
+	}
+	raw := strings.TrimSpace(pom.Version)
+	if raw != "" && !strings.Contains(raw, "${") {
+		return nil, nil
+	}
+	poms := e.loadMavenHierarchy(pomPath, pom)
+
+	res := &mavenResolution{}
+	switch {
+	case raw != "":
+		res.chain = append(res.chain, fmt.Sprintf("%s <version>%s</version>",
+			filepath.Base(pomPath), raw))
+	case pom.Parent != nil && strings.TrimSpace(pom.Parent.Version) != "":
+		raw = strings.TrimSpace(pom.Parent.Version)
+		res.chain = append(res.chain, fmt.Sprintf(
+			"%s inherits <parent> version %s", filepath.Base(pomPath), raw))
+		if !strings.Contains(raw, "${") {
+			res.inherited = true
+			if len(poms) > 1 {
+				res.parentFile = poms[1].path
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%s declares no version and no parent version",
+			pomPath)
+	}
+
+	props := e.collectMavenProperties(poms, e.findMavenConfig(filepath.Dir(pomPath)))
+
+	// A version that is exactly one property reference can be rewritten at
+	// the property's definition.
+	if m := mavenPropertyRef.FindStringSubmatch(raw); m != nil && m[0] == raw {
+		if pv, ok := props[m[1]]; ok {
+			res.defFile = pv.source
+			if pv.config {
+				res.defProperty = m[1]
+			} else {
+				res.defXPath = "/project/properties/" + m[1]
+			}
+		}
+	}
+
+	version, err := expandMavenProperties(raw, props, pom, &res.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	version = e.cleanVersion(version)
+	if !valid(version) {
+		return nil, fmt.Errorf("resolved Maven version %q is not valid", version)
+	}
+	res.version = version
+	return res, nil
+}
+
+// mavenPropertyResult resolves a pom.xml whose version comes from a property
+// or its parent, returning nil when the POM has a literal version or cannot
+// be resolved so regular extraction (and the dynamic fallback) proceeds.
+func (e *VersionExtractor) mavenPropertyResult(project *config.ProjectConfig,
+	pomPath string) *ExtractResult {
+	if filepath.Base(pomPath) != "pom.xml" {
+		return nil
+	}
+	res, err := e.resolveMavenVersion(pomPath, e.validatorFor(project, pomPath))
+	if err != nil || res == nil {
+		return nil
+	}
+	return &ExtractResult{
+		Version:       res.version,
+		ProjectType:   project.Type,
+		Subtype:       project.Subtype,
+		File:          pomPath,
+		MatchedBy:     "maven: " + strings.Join(res.chain, " -> "),
+		Success:       true,
+		VersionSource: "static-property",
+	}
+}
+
+// mavenHierarchyEntry is one POM in a child-to-ancestor chain
+type mavenHierarchyEntry struct {
+	path string
+	pom  *mavenPOM
+}
+
+// loadMavenHierarchy returns the parsed pomPath and its local parent POMs,
+// child first.
+// A parent is only followed when it exists on disk and its coordinates match
+// the child's <parent> declaration; an empty <relativePath/> stops the walk,
+// as it does in Maven.
+func (e *VersionExtractor) loadMavenHierarchy(pomPath string, pom *mavenPOM) []mavenHierarchyEntry {
+	poms := []mavenHierarchyEntry{{pomPath, pom}}
+
+	seen := map[string]bool{filepath.Clean(pomPath): true}
+	current := poms[0]
+	for i := 0; i < maxMavenParentDepth && current.pom.Parent != nil; i++ {
+		parent := current.pom.Parent
+		rel := "../pom.xml"
+		if parent.RelativePath != nil {
+			rel = strings.TrimSpace(*parent.RelativePath)
+		}
+		if rel == "" {
+			break
+		}
+		parentPath := filepath.Join(filepath.Dir(current.path),
+			filepath.FromSlash(rel))
+		if info, statErr := e.stat(parentPath); statErr == nil && info.IsDir() {
+			parentPath = filepath.Join(parentPath, "pom.xml")
+		}
+		parentPath = filepath.Clean(parentPath)
+		if seen[parentPath] {
+			break
+		}
+		seen[parentPath] = true
+
+		parentPOM, err := e.parseMavenPOM(parentPath)
+		if err != nil {
+			break
+		}
+		if parent.ArtifactID != "" && parentPOM.ArtifactID != parent.ArtifactID {
+			break
+		}
+		current = mavenHierarchyEntry{parentPath, parentPOM}
+		poms = append(poms, current)
+	}
+
+	return poms
+}
+
+// parseMavenPOM decodes the version-related parts of a pom.xml
+func (e *VersionExtractor) parseMavenPOM(path string) (*mavenPOM, error) {
+	content, err := e.files().ReadFileContent(path, false)
+	if err != nil {
+		return nil, err
+	}
+	var pom mavenPOM
+	if err := xml.Unmarshal([]byte(content), &pom); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &pom, nil
+}
+
+// findMavenConfig returns the nearest .mvn/maven.config at or above dir, or
+// an empty string if there is none within maxMavenConfigSearch levels.
+func (e *VersionExtractor) findMavenConfig(dir string) string {
+	current := dir
+	for i := 0; i < maxMavenConfigSearch; i++ {
+		candidate := filepath.Join(current, ".mvn", "maven.config")
+		if _, err := e.stat(candidate); err == nil {
+			return candidate
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	return ""
+}
+
+// parseMavenConfig returns the -Dname=value definitions in a maven.config
+// file. Both "-Dname=value" and "-D name=value" forms are accepted.
+func (e *VersionExtractor) parseMavenConfig(path string) map[string]string {
+	defs := make(map[string]string)
+	content, err := e.files().ReadFileContent(path, true)
+	if err != nil {
+		return defs
+	}
+	fields := strings.Fields(content)
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case (field == "-D" || field == "--define") && i+1 < len(fields):
+			i++
+			field = fields[i]
+		case strings.HasPrefix(field, "-D"):
+			field = field[2:]
+		case strings.HasPrefix(field, "--define="):
+			field = strings.TrimPrefix(field, "--define=")
+		default:
+			continue
+		}
+		if name, value, ok := strings.Cut(field, "="); ok {
+			defs[name] = strings.Trim(value, `"'`)
+		}
+	}
+	return defs
+}
+
+// collectMavenProperties merges property definitions by precedence: -D
+// entries in maven.config override the POM's own <properties>, which override
+// those inherited from parents.
+func (e *VersionExtractor) collectMavenProperties(poms []mavenHierarchyEntry,
+	configPath string) map[string]mavenPropertyValue {
+	props := make(map[string]mavenPropertyValue)
+	for i := len(poms) - 1; i >= 0; i-- {
+		for _, p := range poms[i].pom.Properties {
+			props[p.name] = mavenPropertyValue{value: p.value, source: poms[i].path}
+		}
+	}
+	if configPath != "" {
+		for name, value := range e.parseMavenConfig(configPath) {
+			props[name] = mavenPropertyValue{value: value, source: configPath,
+				config: true}
+		}
+	}
+	return props
+}
+
+// expandMavenProperties substitutes ${...} references in raw, recording each
+// substitution in chain. Built-in project.* and parent.* references resolve
+// against the POM itself.
+func expandMavenProperties(raw string, props map[string]mavenPropertyValue,
+	pom *mavenPOM, chain *[]string) (string, error) {
+	value := raw
+	for depth := 0; strings.Contains(value, "${"); depth++ {
+		if depth >= maxMavenPropertyDepth {
+			return "", fmt.Errorf("maven property expansion of %q is too deep "+
+				"or cyclic", raw)
+		}
+		var unresolved []string
+		value = mavenPropertyRef.ReplaceAllStringFunc(value, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			if pv, ok := props[name]; ok {
+				*chain = append(*chain, fmt.Sprintf("%s=%s from %s", name,
+					pv.value, filepath.Base(pv.source)))
+				return pv.value
+			}
+			if builtin, ok := mavenBuiltinProperty(name, pom); ok {
+				*chain = append(*chain, fmt.Sprintf("%s=%s", name, builtin))
+				return builtin
+			}
+			if mavenCIFriendlyProperties[name] {
+				*chain = append(*chain, fmt.Sprintf("%s undefined (empty)", name))
+				return ""
+			}
+			unresolved = append(unresolved, name)
+			return ref
+		})
+		if len(unresolved) > 0 {
+			return "", fmt.Errorf("unresolved maven properties: %s",
+				strings.Join(unresolved, ", "))
+		}
+	}
+	return value, nil
+}
+
+// mavenBuiltinProperty resolves the project model references Maven provides
+// without a <properties> entry. ${project.version} is deliberately absent: in
+// a <version> element it would refer to itself.
+func mavenBuiltinProperty(name string, pom *mavenPOM) (string, bool) {
+	if pom.Parent == nil {
+		return "", false
+	}
+	switch name {
+	case "project.parent.version", "parent.version":
+		return strings.TrimSpace(pom.Parent.Version), pom.Parent.Version != ""
+	}
+	return "", false
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// mavenConfig mirrors the default Maven project type, including the dynamic
+// indicators that a ${revision} reference would otherwise trip.
+func mavenConfig() *config.Config {
+	return &config.Config{
+		Projects: []config.ProjectConfig{
+			{
+				Type:    "Java",
+				Subtype: "Maven",
+				File:    "pom.xml",
+				XPath:   "/project/version",
+				Regex: []string{
+					`<project>[\s\S]*?<version>([^<]+)</version>`,
+					`<version>([^<]+)</version>`,
+				},
+				Priority:                  1,
+				SupportsDynamicVersioning: true,
+				DynamicVersionIndicators: []config.DynamicVersionIndicator{
+					{Field: "version", Contains: []string{"${revision}"}},
+					{Path: "<properties>", Exists: true},
+				},
+			},
+		},
+	}
+}
+
+func TestMavenPropertyResolution(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		path      string
+		want      string
+		wantChain []string
+	}{
+		{
+			name: "revision from properties",
+			files: map[string]string{
+				"pom.xml": `<project>
+  <artifactId>app</artifactId>
+  <version>${revision}</version>
+  <properties><revision>1.4.0</revision></properties>
+  <dependencies><dependency><version>9.9.9</version></dependency></dependencies>
+</project>`,
+			},
+			want:      "1.4.0",
+			wantChain: []string{"pom.xml <version>${revision}</version>", "revision=1.4.0 from pom.xml"},
+		},
+		{
+			name: "CI-friendly versions with maven.config",
+			files: map[string]string{
+				"pom.xml": `<project>
+  <version>${revision}${sha1}${changelist}</version>
+  <properties>
+    <revision>0.0.0</revision>
+    <changelist>-SNAPSHOT</changelist>
+  </properties>
+</project>`,
+				".mvn/maven.config": "-Drevision=2.3.1 -B\n",
+			},
+			want: "2.3.1-SNAPSHOT",
+			wantChain: []string{
+				"pom.xml <version>${revision}${sha1}${changelist}</version>",
+				"revision=2.3.1 from maven.config",
+				"sha1 undefined (empty)",
+				"changelist=-SNAPSHOT from pom.xml",
+			},
+		},
+		{
+			name: "module inherits the parent version",
+			files: map[string]string{
+				"pom.xml": `<project>
+  <artifactId>parent</artifactId>
+  <version>${revision}</version>
+  <properties><revision>3.0.2</revision></properties>
+</project>`,
+				"core/pom.xml": `<project>
+  <parent>
+    <artifactId>parent</artifactId>
+    <version>${revision}</version>
+  </parent>
+  <artifactId>core</artifactId>
+</project>`,
+			},
+			path: "core/pom.xml",
+			want: "3.0.2",
+			wantChain: []string{
+				"pom.xml inherits <parent> version ${revision}",
+				"revision=3.0.2 from pom.xml",
+			},
+		},
+		{
+			name: "parent version through a custom relativePath",
+			files: map[string]string{
+				"build/parent/pom.xml": `<project>
+  <artifactId>bom</artifactId>
+  <version>5.1.0</version>
+</project>`,
+				"pom.xml": `<project>
+  <parent>
+    <artifactId>bom</artifactId>
+    <version>5.1.0</version>
+    <relativePath>build/parent</relativePath>
+  </parent>
+  <artifactId>svc</artifactId>
+  <version>${project.parent.version}</version>
+</project>`,
+			},
+			want: "5.1.0",
+			wantChain: []string{
+				"pom.xml <version>${project.parent.version}</version>",
+				"project.parent.version=5.1.0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(tmpDir, name), content)
+			}
+			target := tmpDir
+			if tt.path != "" {
+				target = filepath.Join(tmpDir, tt.path)
+			}
+
+			result, err := NewWithOptions(mavenConfig(), false).Extract(target)
+			if err != nil {
+				t.Fatalf("expected success, got error: %v", err)
+			}
+			if result.Version != tt.want {
+				t.Errorf("expected version %s, got %s", tt.want, result.Version)
+			}
+			if result.VersionSource != "static-property" {
+				t.Errorf("expected version_source static-property, got %s", result.VersionSource)
+			}
+			wantMatchedBy := "maven: " + strings.Join(tt.wantChain, " -> ")
+			if result.MatchedBy != wantMatchedBy {
+				t.Errorf("expected matched_by %q, got %q", wantMatchedBy, result.MatchedBy)
+			}
+		})
+	}
+}
+
+// TestMavenLiteralVersionUnchanged ensures a POM with its own literal version
+// keeps using the regular static extraction.
+func TestMavenLiteralVersionUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "pom.xml"),
+		"<project>\n  <version>1.0.0</version>\n</project>\n")
+
+	result, err := NewWithOptions(mavenConfig(), false).Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result.VersionSource != "static" || result.MatchedBy != "xpath: /project/version" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestMavenUnresolvedProperty(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "pom.xml"),
+		"<project>\n  <version>${undefined.prop}</version>\n</project>\n")
+
+	e := New(mavenConfig())
+	pom := filepath.Join(tmpDir, "pom.xml")
+	if _, err := e.resolveMavenVersion(pom, e.validatorFor(nil, pom)); err == nil ||
+		!strings.Contains(err.Error(), "undefined.prop") {
+		t.Errorf("expected unresolved property error, got %v", err)
+	}
+}
+
+func TestParseMavenConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "maven.config")
+	writeFile(t, file, "-Drevision=1.0.0\n-D changelist=-SNAPSHOT\n--define=sha1=abc\n-T 4\n")
+
+	defs := New(nil).parseMavenConfig(file)
+	want := map[string]string{"revision": "1.0.0", "changelist": "-SNAPSHOT", "sha1": "abc"}
+	if len(defs) != len(want) {
+		t.Fatalf("expected %d definitions, got %v", len(want), defs)
+	}
+	for k, v := range want {
+		if defs[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, defs[k])
+		}
+	}
+}
+
+// TestBumpMavenProperty rewrites the definition a ${revision} version
+// resolves to, in the POM or in maven.config.
+func TestBumpMavenProperty(t *testing.T) {
+	t.Run("properties", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		pom := filepath.Join(tmpDir, "pom.xml")
+		writeFile(t, pom, "<project>\n  <version>${revision}</version>\n"+
+			"  <properties>\n    <revision>1.4.0</revision>\n  </properties>\n</project>\n")
+
+		if _, err := NewWithOptions(mavenConfig(), false).Bump(tmpDir, BumpOptions{Level: BumpMinor}); err != nil {
+			t.Fatalf("bump failed: %v", err)
+		}
+		data, _ := os.ReadFile(pom)
+		if !strings.Contains(string(data), "<revision>1.5.0</revision>") ||
+			!strings.Contains(string(data), "<version>${revision}</version>") {
+			t.Errorf("unexpected POM after bump:\n%s", data)
+		}
+	})
+
+	t.Run("maven.config", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFile(t, filepath.Join(tmpDir, "pom.xml"),
+			"<project>\n  <version>${revision}</version>\n</project>\n")
+		cfgFile := filepath.Join(tmpDir, ".mvn", "maven.config")
+		writeFile(t, cfgFile, "-B -Drevision=2.0.0\n")
+
+		if _, err := NewWithOptions(mavenConfig(), false).Bump(tmpDir, BumpOptions{Set: "2.1.0"}); err != nil {
+			t.Fatalf("bump failed: %v", err)
+		}
+		data, _ := os.ReadFile(cfgFile)
+		if string(data) != "-B -Drevision=2.1.0\n" {
+			t.Errorf("unexpected maven.config after bump: %q", data)
+		}
+	})
+
+	t.Run("inherited refused", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		parent := "<project>\n  <artifactId>parent</artifactId>\n  <version>1.2.3</version>\n</project>\n"
+		child := "<project>\n  <parent>\n    <artifactId>parent</artifactId>\n" +
+			"    <version>1.2.3</version>\n  </parent>\n  <artifactId>child</artifactId>\n</project>\n"
+		writeFile(t, filepath.Join(tmpDir, "pom.xml"), parent)
+		writeFile(t, filepath.Join(tmpDir, "child", "pom.xml"), child)
+
+		ext := NewWithOptions(mavenConfig(), false)
+		_, err := ext.Bump(filepath.Join(tmpDir, "child", "pom.xml"), BumpOptions{Level: BumpMinor})
+		if err == nil || !strings.Contains(err.Error(), "inherited from "+filepath.Join(tmpDir, "pom.xml")) {
+			t.Errorf("expected an inherited version bump to be refused, got %v", err)
+		}
+		for file, want := range map[string]string{"pom.xml": parent, "child/pom.xml": child} {
+			if data, _ := os.ReadFile(filepath.Join(tmpDir, file)); string(data) != want {
+				t.Errorf("expected %s to be untouched, got:\n%s", file, data)
+			}
+		}
+
+		// Without the parent on disk the missing parent is reported
+		if err := os.Remove(filepath.Join(tmpDir, "pom.xml")); err != nil {
+			t.Fatal(err)
+		}
+		_, err = ext.Bump(filepath.Join(tmpDir, "child", "pom.xml"), BumpOptions{Level: BumpMinor})
+		if err == nil || !strings.Contains(err.Error(), "not on disk") {
+			t.Errorf("expected the missing parent to be reported, got %v", err)
+		}
+	})
+
+	t.Run("composite refused", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFile(t, filepath.Join(tmpDir, "pom.xml"),
+			"<project>\n  <version>${revision}${changelist}</version>\n"+
+				"  <properties><revision>1.0.0</revision><changelist>-SNAPSHOT</changelist></properties>\n</project>\n")
+
+		if _, err := NewWithOptions(mavenConfig(), false).Bump(tmpDir, BumpOptions{Level: BumpPatch}); err == nil {
+			t.Error("expected composite version bump to be refused")
+		}
+	})
+}
@@ -0,0 +1,447 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// Bounds for MSBuild evaluation: directories searched upwards for
+// Directory.Build.props (or a GetPathOfFileAbove target), and nested imports.
+const (
+	maxMSBuildPropsSearch = 8
+	maxMSBuildImportDepth = 10
+)
+
+// msbuildPropertyRef matches a $(Name) property reference
+var msbuildPropertyRef = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_.-]*)\)`)
+
+// Property functions commonly used in Directory.Build.props imports to chain
+// to the next Directory.Build.props further up the tree.
+var (
+	msbuildPathOfFileAbove = regexp.MustCompile(
+		`\$\(\[MSBuild\]::GetPathOfFileAbove\(\s*'?([^',)]+)'?\s*(?:,\s*'?([^')]*)'?\s*)?\)\)`)
+	msbuildDirOfFileAbove = regexp.MustCompile(
+		`\$\(\[MSBuild\]::GetDirectoryNameOfFileAbove\(\s*'?([^',)]*)'?\s*,\s*'?([^')]+)'?\s*\)\)`)
+)
+
+// Simple MSBuild condition clauses: 'a' == 'b', 'a' != 'b' and
+// [!]Exists('path').
+var (
+	msbuildCompareClause = regexp.MustCompile(`^'([^']*)'\s*(==|!=)\s*'([^']*)'$`)
+	msbuildExistsClause  = regexp.MustCompile(`^(!?)\s*Exists\(\s*'([^']*)'\s*\)$`)
+	msbuildLogicalOp     = regexp.MustCompile(`(?i)\s+(and|or)\s+`)
+)
+
+// msbuildProjectExtensions are the project files evaluated together with the
+// Directory.Build.props found above them.
+var msbuildProjectExtensions = []string{".csproj", ".fsproj", ".vbproj"}
+
+// msbuildProperty is one evaluated property definition
+type msbuildProperty struct {
+	name  string
+	value string // evaluated value
+	raw   string // value as written
+	file  string
+	// Span of raw in file, or -1 when the text cannot be mapped back
+	start, end int
+	// Resolution steps for the properties raw referenced, in order
+	chain []string
+	// The property raw consists of, when raw is a single $(Name) reference
+	single *msbuildProperty
+}
+
+// literal returns the property whose literal text this property's value comes
+// from, following single $(Name) references, or nil if the value is composed.
+func (p *msbuildProperty) literal() *msbuildProperty {
+	for depth := 0; p != nil && depth < maxMSBuildImportDepth; depth++ {
+		if !strings.Contains(p.raw, "$(") {
+			if p.start < 0 {
+				return nil
+			}
+			return p
+		}
+		p = p.single
+	}
+	return nil
+}
+
+// msbuildEvaluator evaluates property definitions across a project and the
+// files it imports, in MSBuild's document order.
+type msbuildEvaluator struct {
+	e        *VersionExtractor // Reads the files
+	props    map[string]*msbuildProperty
+	imported map[string]bool
+	baseDir  string // directory paths in MatchedBy are relative to
+}
+
+// msbuildResolution is the effective version of an MSBuild project
+type msbuildResolution struct {
+	version string
+	chain   []string
+	// Definition to rewrite, or nil when the version is composed from
+	// several properties.
+	def *msbuildProperty
+}
+
+// resolveMSBuildVersion evaluates an MSBuild project (or props file) and
+// returns its effective version. A project file is evaluated after the
+// nearest Directory.Build.props above it, following <Import> elements in
+// both. $(Prop) references are substituted, and when Version is not set the
+// SDK's composition of VersionPrefix and VersionSuffix applies. It returns
+// nil when the version is a literal in filePath itself, which regular
+// extraction already handles.
+func (e *VersionExtractor) resolveMSBuildVersion(filePath string,
+	valid versionValidator) (*msbuildResolution, error) {
+	ev := &msbuildEvaluator{
+		e:        e,
+		props:    make(map[string]*msbuildProperty),
+		imported: make(map[string]bool),
+		baseDir:  filepath.Dir(filePath),
+	}
+
+	if isMSBuildProject(filePath) {
+		if props := e.findFileAbove("Directory.Build.props",
+			filepath.Dir(filePath)); props != "" {
+			if err := ev.evaluateFile(props, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := ev.evaluateFile(filePath, 0); err != nil {
+		return nil, err
+	}
+
+	res := &msbuildResolution{}
+	var final *msbuildProperty
+	if v := ev.props["Version"]; v != nil && v.value != "" {
+		final = v
+		res.chain = append(res.chain, ev.describe(v))
+		res.chain = append(res.chain, v.chain...)
+	} else if prefix := ev.props["VersionPrefix"]; prefix != nil && prefix.value != "" {
+		final = prefix
+		res.version = prefix.value
+		res.chain = append(res.chain, ev.describe(prefix))
+		res.chain = append(res.chain, prefix.chain...)
+		if suffix := ev.props["VersionSuffix"]; suffix != nil && suffix.value != "" {
+			res.version += "-" + suffix.value
+			res.chain = append(res.chain, ev.describe(suffix))
+			res.chain = append(res.chain, suffix.chain...)
+			res.chain = append(res.chain, "Version="+res.version)
+			final = nil
+		}
+	} else {
+		return nil, nil
+	}
+
+	if final != nil {
+		res.version = final.value
+		if final.file == filepath.Clean(filePath) && !strings.Contains(final.raw, "$(") {
+			return nil, nil
+		}
+		res.def = final.literal()
+	}
+
+	version := e.cleanVersion(res.version)
+	if !valid(version) {
+		return nil, fmt.Errorf("resolved MSBuild version %q is not valid",
+			res.version)
+	}
+	res.version = version
+	return res, nil
+}
+
+// msbuildPropertyResult resolves an MSBuild project or props file whose
+// version comes from imported props, $(Prop) references or
+// VersionPrefix/VersionSuffix composition. It returns nil when the version
+// is a literal in the file or cannot be resolved, so regular extraction
+// proceeds.
+func (e *VersionExtractor) msbuildPropertyResult(project *config.ProjectConfig,
+	filePath string) *ExtractResult {
+	if !isMSBuildProject(filePath) && !strings.HasSuffix(filePath, ".props") {
+		return nil
+	}
+	res, err := e.resolveMSBuildVersion(filePath, e.validatorFor(project, filePath))
+	if err != nil || res == nil {
+		return nil
+	}
+	return &ExtractResult{
+		Version:       res.version,
+		ProjectType:   project.Type,
+		Subtype:       project.Subtype,
+		File:          filePath,
+		MatchedBy:     "msbuild: " + strings.Join(res.chain, " -> "),
+		Success:       true,
+		VersionSource: "static-property",
+	}
+}
+
+// describe formats a property definition for the resolution chain
+func (ev *msbuildEvaluator) describe(p *msbuildProperty) string {
+	return fmt.Sprintf("%s=%s from %s", p.name, p.raw,
+		relativeTo(ev.baseDir, p.file))
+}
+
+// evaluateFile evaluates the top-level <PropertyGroup> and <Import> elements
+// of an MSBuild file in document order. Imports that do not exist, or whose
+// conditions are false or not understood, are skipped.
+func (ev *msbuildEvaluator) evaluateFile(path string, depth int) error {
+	path = filepath.Clean(path)
+	if depth > maxMSBuildImportDepth || ev.imported[path] {
+		return nil
+	}
+	ev.imported[path] = true
+
+	content, err := ev.e.files().ReadFileContent(path, false)
+	if err != nil {
+		return err
+	}
+	data := []byte(content)
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	var stack []string
+	groupActive := true
+	var current *msbuildProperty
+	var text strings.Builder
+	textStart := -1
+
+	for {
+		before := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			condition := xmlAttr(t, "Condition")
+			switch {
+			case len(stack) == 2 && t.Name.Local == "PropertyGroup":
+				groupActive = ev.condition(condition, path)
+			case len(stack) == 3 && stack[1] == "PropertyGroup":
+				current = nil
+				if groupActive && ev.condition(condition, path) {
+					current = &msbuildProperty{name: t.Name.Local, file: path}
+					text.Reset()
+					textStart = int(dec.InputOffset())
+				}
+			case t.Name.Local == "Import" && (len(stack) == 2 ||
+				(len(stack) == 3 && stack[1] == "ImportGroup")):
+				if ev.condition(condition, path) {
+					if target := ev.importPath(xmlAttr(t, "Project"), path); target != "" {
+						if err := ev.evaluateFile(target, depth+1); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		case xml.CharData:
+			if current != nil && len(stack) == 3 {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if current != nil && len(stack) == 3 {
+				found := xmlTextResult(data, text.String(), textStart, before)
+				current.raw = found.value
+				current.start, current.end = found.start, found.end
+				ev.define(current)
+				current = nil
+			}
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+}
+
+// define evaluates a property's raw value against the properties defined so
+// far and records it, replacing any earlier definition.
+func (ev *msbuildEvaluator) define(p *msbuildProperty) {
+	refs := msbuildPropertyRef.FindAllStringSubmatch(p.raw, -1)
+	if len(refs) == 1 && refs[0][0] == p.raw {
+		p.single = ev.props[refs[0][1]]
+	}
+	for _, ref := range refs {
+		if prev := ev.props[ref[1]]; prev != nil {
+			p.chain = append(p.chain, ev.describe(prev))
+			p.chain = append(p.chain, prev.chain...)
+		}
+	}
+	p.value = ev.expand(p.raw, p.file)
+	ev.props[p.name] = p
+}
+
+// expand substitutes $(Name) references. Undefined properties expand to an
+// empty string, as in MSBuild; MSBuildThisFileDirectory is the directory of
+// the file being evaluated, with a trailing separator.
+func (ev *msbuildEvaluator) expand(value, file string) string {
+	return msbuildPropertyRef.ReplaceAllStringFunc(value, func(ref string) string {
+		name := ref[2 : len(ref)-1]
+		switch name {
+		case "MSBuildThisFileDirectory":
+			return filepath.Dir(file) + string(filepath.Separator)
+		case "MSBuildThisFile":
+			return filepath.Base(file)
+		}
+		if p := ev.props[name]; p != nil {
+			return p.value
+		}
+		return ""
+	})
+}
+
+// importPath evaluates an <Import Project="..."> value to an existing file,
+// or returns an empty string. The GetPathOfFileAbove and
+// GetDirectoryNameOfFileAbove property functions are supported.
+func (ev *msbuildEvaluator) importPath(project, file string) string {
+	thisDir := filepath.Dir(file)
+	// Plain $(Name) references first, so function arguments such as
+	// '$(MSBuildThisFileDirectory)../' are literal paths.
+	project = ev.expand(project, file)
+	project = msbuildPathOfFileAbove.ReplaceAllStringFunc(project, func(call string) string {
+		m := msbuildPathOfFileAbove.FindStringSubmatch(call)
+		start := thisDir
+		if strings.TrimSpace(m[2]) != "" {
+			start = ev.msbuildPath(m[2], file, thisDir)
+		}
+		return ev.e.findFileAbove(strings.TrimSpace(m[1]), start)
+	})
+	project = msbuildDirOfFileAbove.ReplaceAllStringFunc(project, func(call string) string {
+		m := msbuildDirOfFileAbove.FindStringSubmatch(call)
+		found := ev.e.findFileAbove(strings.TrimSpace(m[2]),
+			ev.msbuildPath(m[1], file, thisDir))
+		if found == "" {
+			return ""
+		}
+		return filepath.Dir(found)
+	})
+
+	target := ev.msbuildPath(project, file, thisDir)
+	if strings.TrimSpace(project) == "" || target == "" {
+		return ""
+	}
+	if info, err := ev.e.stat(target); err != nil || info.IsDir() {
+		return ""
+	}
+	return target
+}
+
+// msbuildPath expands and normalises a path written in an MSBuild file,
+// resolving it relative to baseDir. MSBuild paths use backslashes on every
+// platform.
+func (ev *msbuildEvaluator) msbuildPath(value, file, baseDir string) string {
+	value = strings.TrimSpace(ev.expand(value, file))
+	if value == "" {
+		return ""
+	}
+	value = filepath.FromSlash(strings.ReplaceAll(value, `\`, "/"))
+	if !filepath.IsAbs(value) {
+		value = filepath.Join(baseDir, value)
+	}
+	return filepath.Clean(value)
+}
+
+// condition evaluates a Condition attribute. An empty condition is true; a
+// condition built from anything other than string comparisons and Exists()
+// joined by a single kind of and/or is treated as false, so the element is
+// skipped rather than guessed at.
+func (ev *msbuildEvaluator) condition(cond, file string) bool {
+	cond = strings.TrimSpace(cond)
+	if cond == "" {
+		return true
+	}
+
+	ops := msbuildLogicalOp.FindAllStringSubmatch(cond, -1)
+	clauses := msbuildLogicalOp.Split(cond, -1)
+	isOr := false
+	for i, op := range ops {
+		or := strings.EqualFold(op[1], "or")
+		if i > 0 && or != isOr {
+			return false // mixed and/or without grouping
+		}
+		isOr = or
+	}
+
+	for _, clause := range clauses {
+		ok, known := ev.clause(strings.TrimSpace(clause), file)
+		if !known {
+			return false
+		}
+		if isOr && ok {
+			return true
+		}
+		if !isOr && !ok {
+			return false
+		}
+	}
+	return !isOr
+}
+
+// clause evaluates one condition clause, reporting whether it is understood
+func (ev *msbuildEvaluator) clause(clause, file string) (bool, bool) {
+	if m := msbuildCompareClause.FindStringSubmatch(clause); m != nil {
+		equal := strings.EqualFold(strings.TrimSpace(ev.expand(m[1], file)),
+			strings.TrimSpace(ev.expand(m[3], file)))
+		return equal == (m[2] == "=="), true
+	}
+	if m := msbuildExistsClause.FindStringSubmatch(clause); m != nil {
+		target := ev.msbuildPath(m[2], file, filepath.Dir(file))
+		_, err := ev.e.stat(target)
+		exists := target != "" && err == nil
+		return exists != (m[1] == "!"), true
+	}
+	return false, false
+}
+
+// findFileAbove returns the nearest file called name in dir or one of its
+// parents, or an empty string.
+func (e *VersionExtractor) findFileAbove(name, dir string) string {
+	current := filepath.Clean(dir)
+	for i := 0; i < maxMSBuildPropsSearch; i++ {
+		candidate := filepath.Join(current, name)
+		if info, err := e.stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	return ""
+}
+
+// isMSBuildProject reports whether filePath is an MSBuild project file
+func isMSBuildProject(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, projectExt := range msbuildProjectExtensions {
+		if ext == projectExt {
+			return true
+		}
+	}
+	return false
+}
+
+// xmlAttr returns the value of the named attribute, or an empty string
+func xmlAttr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// dotnetConfig mirrors the default .NET project type
+func dotnetConfig() *config.Config {
+	return &config.Config{
+		Projects: []config.ProjectConfig{
+			{
+				Type:    "CSharp",
+				Subtype: ".NET Project",
+				File:    "*.csproj",
+				Regex: []string{
+					`<Version[^>]*>([^<]+)</Version>`,
+					`<VersionPrefix[^>]*>([^<]+)</VersionPrefix>`,
+					`<AssemblyVersion[^>]*>([^<]+)</AssemblyVersion>`,
+				},
+				Priority: 1,
+			},
+		},
+	}
+}
+
+const sdkProject = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+</Project>
+`
+
+func TestMSBuildVersionResolution(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		want      string
+		wantChain []string
+	}{
+		{
+			name: "property substitution in Directory.Build.props",
+			files: map[string]string{
+				"Directory.Build.props": `<Project>
+  <PropertyGroup>
+    <MajorVersion>1</MajorVersion>
+    <MinorVersion>4</MinorVersion>
+    <PatchVersion>2</PatchVersion>
+    <Version>$(MajorVersion).$(MinorVersion).$(PatchVersion)</Version>
+  </PropertyGroup>
+</Project>`,
+				"src/App/App.csproj": sdkProject,
+			},
+			want: "1.4.2",
+			wantChain: []string{
+				"Version=$(MajorVersion).$(MinorVersion).$(PatchVersion) from ../../Directory.Build.props",
+				"MajorVersion=1 from ../../Directory.Build.props",
+				"MinorVersion=4 from ../../Directory.Build.props",
+				"PatchVersion=2 from ../../Directory.Build.props",
+			},
+		},
+		{
+			name: "VersionPrefix and VersionSuffix",
+			files: map[string]string{
+				"Directory.Build.props": `<Project>
+  <PropertyGroup>
+    <VersionPrefix>2.0.0</VersionPrefix>
+  </PropertyGroup>
+</Project>`,
+				"src/App/App.csproj": `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <VersionSuffix>beta.1</VersionSuffix>
+  </PropertyGroup>
+</Project>`,
+			},
+			want: "2.0.0-beta.1",
+			wantChain: []string{
+				"VersionPrefix=2.0.0 from ../../Directory.Build.props",
+				"VersionSuffix=beta.1 from App.csproj",
+				"Version=2.0.0-beta.1",
+			},
+		},
+		{
+			name: "chained Directory.Build.props and imports",
+			files: map[string]string{
+				"Directory.Build.props": `<Project>
+  <Import Project="build\version.props" Condition="Exists('build\version.props')" />
+</Project>`,
+				"build/version.props": `<Project>
+  <PropertyGroup>
+    <ProductVersion>3.2.0</ProductVersion>
+  </PropertyGroup>
+</Project>`,
+				"src/Directory.Build.props": `<Project>
+  <Import Project="$([MSBuild]::GetPathOfFileAbove('Directory.Build.props', '$(MSBuildThisFileDirectory)../'))" />
+  <PropertyGroup>
+    <Version>$(ProductVersion)</Version>
+  </PropertyGroup>
+</Project>`,
+				"src/App/App.csproj": sdkProject,
+			},
+			want: "3.2.0",
+			wantChain: []string{
+				"Version=$(ProductVersion) from ../Directory.Build.props",
+				"ProductVersion=3.2.0 from ../../build/version.props",
+			},
+		},
+		{
+			name: "conditions",
+			files: map[string]string{
+				"Directory.Build.props": `<Project>
+  <PropertyGroup>
+    <VersionPrefix>1.1.0</VersionPrefix>
+    <VersionSuffix Condition="'$(Configuration)' == 'Release'">ignored</VersionSuffix>
+    <VersionSuffix Condition="'$(VersionSuffix)' == '' and '$(CI)' != 'true'">dev</VersionSuffix>
+    <Version Condition="$(Unknown.Function())">9.9.9</Version>
+  </PropertyGroup>
+  <PropertyGroup Condition="'$(Configuration)' == 'Release'">
+    <VersionPrefix>9.9.9</VersionPrefix>
+  </PropertyGroup>
+</Project>`,
+				"App.csproj": sdkProject,
+			},
+			want: "1.1.0-dev",
+			wantChain: []string{
+				"VersionPrefix=1.1.0 from Directory.Build.props",
+				"VersionSuffix=dev from Directory.Build.props",
+				"Version=1.1.0-dev",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			var project string
+			for name, content := range tt.files {
+				writeFile(t, filepath.Join(tmpDir, name), content)
+				if strings.HasSuffix(name, ".csproj") {
+					project = filepath.Join(tmpDir, name)
+				}
+			}
+
+			result, err := New(dotnetConfig()).Extract(project)
+			if err != nil {
+				t.Fatalf("expected success, got error: %v", err)
+			}
+			if result.Version != tt.want {
+				t.Errorf("expected version %s, got %s", tt.want, result.Version)
+			}
+			if result.VersionSource != "static-property" {
+				t.Errorf("expected version_source static-property, got %s", result.VersionSource)
+			}
+			wantMatchedBy := "msbuild: " + strings.Join(tt.wantChain, " -> ")
+			if result.MatchedBy != wantMatchedBy {
+				t.Errorf("expected matched_by %q, got %q", wantMatchedBy, result.MatchedBy)
+			}
+		})
+	}
+}
+
+// TestMSBuildLiteralVersionUnchanged ensures a project with its own literal
+// <Version> keeps using regular extraction, overriding Directory.Build.props.
+func TestMSBuildLiteralVersionUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, "Directory.Build.props"),
+		"<Project><PropertyGroup><Version>9.9.9</Version></PropertyGroup></Project>\n")
+	writeFile(t, filepath.Join(tmpDir, "App.csproj"),
+		"<Project><PropertyGroup><Version>1.0.0</Version></PropertyGroup></Project>\n")
+
+	result, err := New(dotnetConfig()).Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result.Version != "1.0.0" || result.VersionSource != "static" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+// TestBumpMSBuildProperty rewrites the property definition the version was
+// resolved from, and refuses composed versions.
+func TestBumpMSBuildProperty(t *testing.T) {
+	t.Run("single property reference", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		props := filepath.Join(tmpDir, "Directory.Build.props")
+		writeFile(t, props, "<Project>\n  <PropertyGroup>\n    <ProductVersion>3.2.0</ProductVersion>\n"+
+			"    <Version>$(ProductVersion)</Version>\n  </PropertyGroup>\n</Project>\n")
+		writeFile(t, filepath.Join(tmpDir, "src", "App.csproj"), sdkProject)
+
+		result, err := New(dotnetConfig()).Bump(tmpDir, BumpOptions{Level: BumpMinor})
+		if err != nil {
+			t.Fatalf("bump failed: %v", err)
+		}
+		if result.File != props {
+			t.Errorf("expected %s to be rewritten, got %s", props, result.File)
+		}
+		data, _ := os.ReadFile(props)
+		if !strings.Contains(string(data), "<ProductVersion>3.3.0</ProductVersion>") ||
+			!strings.Contains(string(data), "<Version>$(ProductVersion)</Version>") {
+			t.Errorf("unexpected props after bump:\n%s", data)
+		}
+	})
+
+	t.Run("composed version refused", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFile(t, filepath.Join(tmpDir, "Directory.Build.props"),
+			"<Project><PropertyGroup><VersionPrefix>1.0.0</VersionPrefix>"+
+				"<VersionSuffix>rc.1</VersionSuffix></PropertyGroup></Project>\n")
+		writeFile(t, filepath.Join(tmpDir, "App.csproj"), sdkProject)
+
+		_, err := New(dotnetConfig()).Bump(tmpDir, BumpOptions{Level: BumpPrerelease})
+		if err == nil || !strings.Contains(err.Error(), "composed") {
+			t.Errorf("expected composed version bump to be refused, got %v", err)
+		}
+	})
+}
+
+func TestMSBuildCondition(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "Directory.Build.props")
+	writeFile(t, file, "<Project/>")
+
+	ev := &msbuildEvaluator{props: map[string]*msbuildProperty{
+		"Configuration": {name: "Configuration", value: "Release"},
+	}}
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"", true},
+		{"'$(Configuration)' == 'release'", true},
+		{"'$(Configuration)' != 'Release'", false},
+		{"'$(Missing)' == ''", true},
+		{"'$(Missing)' == 'x' or '$(Configuration)' == 'Release'", true},
+		{"'$(Missing)' == '' AND '$(Configuration)' == 'Debug'", false},
+		{"Exists('Directory.Build.props')", true},
+		{"!Exists('missing.props')", true},
+		{"'a' == 'a' and 'b' == 'b' or 'c' == 'c'", false},
+		{"$(Flag)", false},
+	}
+	for _, tt := range tests {
+		if got := ev.condition(tt.cond, file); got != tt.want {
+			t.Errorf("condition(%q) = %v, want %v", tt.cond, got, tt.want)
+		}
+	}
+}
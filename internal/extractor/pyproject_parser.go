@@ -15,8 +15,9 @@ const projectVersionPattern = `^version\s*=\s*["']([^"']+)["']`
 var dunderVersionPatterns = []string{`__version__\s*=\s*["']([^"']+)["']`}
 
 // extractFromPyprojectToml handles pyproject.toml with section-aware parsing
-func (e *VersionExtractor) extractFromPyprojectToml(filePath string) (string, string, error) {
-	fileContent, err := fileReader.ReadFileContent(filePath, false)
+func (e *VersionExtractor) extractFromPyprojectToml(filePath string,
+	valid versionValidator) (string, string, error) {
+	fileContent, err := e.files().ReadFileContent(filePath, false)
 	if err != nil {
 		return "", "", err
 	}
@@ -52,7 +53,7 @@ func (e *VersionExtractor) extractFromPyprojectToml(filePath string) (string, st
 			matches := versionRe.FindStringSubmatch(trimmed)
 			if len(matches) == 2 {
 				version := matches[1]
-				if version != "" && e.isValidVersion(version) {
+				if version != "" && valid(version) {
 					return version, "[project] section version", nil
 				}
 			}
@@ -60,6 +61,18 @@ func (e *VersionExtractor) extractFromPyprojectToml(filePath string) (string, st
 	}
 
 	// If no version found in [project] section, try to find __version__.py files
+	if version, _ := e.findDunderVersionFile(filePath, valid); version != "" {
+		return version, "__version__.py", nil
+	}
+
+	return "", "", nil
+}
+
+// findDunderVersionFile looks for a __version__.py file alongside a
+// pyproject.toml (directly, or in a src/ or top-level package) and returns
+// the first valid version found and the file it came from.
+func (e *VersionExtractor) findDunderVersionFile(filePath string,
+	valid versionValidator) (string, string) {
 	// Limit search to prevent performance issues in large projects
 	projectDir := filepath.Dir(filePath)
 	versionFiles := []string{
@@ -84,8 +97,8 @@ func (e *VersionExtractor) extractFromPyprojectToml(filePath string) (string, st
 			// extractVersionWithPatterns rather than extractVersionFromFile:
 			// the latter routes any file whose basename is "pyproject.toml"
 			// back into the section-aware parser above.
-			if version, _, err := e.extractVersionWithPatterns(versionFile, dunderVersionPatterns); err == nil && version != "" {
-				return version, "__version__.py", nil
+			if version, _, err := e.extractVersionWithPatterns(versionFile, dunderVersionPatterns, valid); err == nil && version != "" {
+				return version, versionFile
 			}
 		}
 		// Break outer loop if limit reached
@@ -94,5 +107,5 @@ func (e *VersionExtractor) extractFromPyprojectToml(filePath string) (string, st
 		}
 	}
 
-	return "", "", nil
+	return "", ""
 }
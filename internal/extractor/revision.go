@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SetRevision makes the extractor read project files from the tree of a git
+// commit, without checking it out: rev is a tag, branch or commit ID, which
+// may be abbreviated or followed by ~N and ^N steps such as HEAD~3, or, with
+// the exec git backend, any revision git understands.
+// The path given to Extract, ExtractAll or Check is a path on disk within
+// the repository, but it need only exist in the revision. Results name files
+// by their path in the revision, relative to the top of the work tree. The
+// git tag fallback looks tags up relative to the revision instead of HEAD,
+// and Bump can only preview changes. An empty rev restores the work tree.
+func (e *VersionExtractor) SetRevision(rev string) error {
+	if strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("invalid git revision %q", rev)
+	}
+	e.revision = rev
+	return nil
+}
+
+// openRevision makes the extractor, a call's copy, read project files from
+// the revision's tree, and returns hostPath as a path in that tree
+func (e *VersionExtractor) openRevision(hostPath string) (string, error) {
+	if !e.onHost() {
+		return "", fmt.Errorf("reading git revision %s is %w", e.revision, errNoHostFS)
+	}
+
+	// The path may have been removed from the work tree since the revision,
+	// so git runs in the nearest directory above it that exists
+	dir, err := filepath.Abs(hostPath)
+	if err != nil {
+		return "", err
+	}
+	rest := "."
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("path does not exist: %s", hostPath)
+		}
+		rest = path.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+
+	gitExtractor := e.newGitExtractor(dir)
+	gitExtractor.SetRevision(e.revision)
+	if !gitExtractor.IsGitRepository() {
+		return "", fmt.Errorf("cannot read git revision %s: not a git repository: %s",
+			e.revision, dir)
+	}
+	relPath, err := gitExtractor.RelativePath()
+	if err != nil {
+		return "", fmt.Errorf("cannot read git revision %s: %w", e.revision, err)
+	}
+	tree, err := gitExtractor.Tree()
+	if err != nil {
+		return "", fmt.Errorf("cannot read git revision %s: %w", e.revision, err)
+	}
+
+	e.fsys, e.revisionDir = slashFS{fsys: tree}, dir
+	return filepath.FromSlash(path.Join(relPath, rest)), nil
+}
+
+// gitRelativePath returns a directory relative to the top of the work tree,
+// using "/" separators, or "" at the top. Paths in a revision's tree already
+// are.
+func (e *VersionExtractor) gitRelativePath(dir string) (string, error) {
+	if e.revisionDir == "" {
+		return e.newGitExtractor(dir).RelativePath()
+	}
+	relPath := path.Clean(filepath.ToSlash(dir))
+	if relPath == "." {
+		return "", nil
+	}
+	return relPath, nil
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+func TestExtractRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping git integration test")
+	}
+
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		if err := runGitCommand(tmpDir, args...); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+
+	writeFile(t, filepath.Join(tmpDir, "web", "package.json"), `{"name": "web", "version": "1.0.0"}`)
+	writeFile(t, filepath.Join(tmpDir, "old", "pyproject.toml"),
+		"[project]\nname = \"old\"\nversion = \"0.1.0\"\n")
+	writeFile(t, filepath.Join(tmpDir, "services", "api", "go.mod"),
+		"module github.com/test/root/services/api\n\ngo 1.22\n")
+	git("add", ".")
+	git("commit", "-m", "one")
+	git("tag", "-a", "services/api/v1.0.0", "-m", "api 1.0.0")
+	git("commit", "--allow-empty", "-m", "two")
+	git("branch", "release")
+
+	git("rm", "-r", "-q", "old")
+	writeFile(t, filepath.Join(tmpDir, "web", "package.json"), `{"name": "web", "version": "2.0.0"}`)
+	git("commit", "-am", "three")
+	git("tag", "-a", "services/api/v2.0.0", "-m", "api 2.0.0")
+	// Uncommitted changes are not read
+	writeFile(t, filepath.Join(tmpDir, "web", "package.json"), `{"name": "web", "version": "9.9.9"}`)
+
+	e := New(monorepoConfig())
+	if err := e.SetRevision("release"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := e.Extract(filepath.Join(tmpDir, "web"))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "1.0.0" || result.File != filepath.Join("web", "package.json") {
+		t.Errorf("Expected 1.0.0 from web/package.json at release, got %s from %s",
+			result.Version, result.File)
+	}
+
+	// A directory removed from the work tree is still read from the revision
+	result, err = e.Extract(filepath.Join(tmpDir, "old"))
+	if err != nil {
+		t.Fatalf("Extract of a removed directory failed: %v", err)
+	}
+	if result.Version != "0.1.0" {
+		t.Errorf("Expected 0.1.0, got %s", result.Version)
+	}
+
+	results, err := e.ExtractAll(tmpDir)
+	if err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected web and old at release, got %d results", len(results))
+	}
+
+	// The git fallback describes the revision, not HEAD
+	goConfig := &config.Config{Projects: []config.ProjectConfig{{
+		Type:                      "Go",
+		File:                      "go.mod",
+		Priority:                  1,
+		SupportsDynamicVersioning: true,
+		DynamicVersionIndicators: []config.DynamicVersionIndicator{
+			{Path: "go.mod", Field: "module", Contains: []string{"github.com"}},
+		},
+	}}}
+	e = New(goConfig)
+	if err := e.SetRevision("release"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SetGitVersionStyle("semver"); err != nil {
+		t.Fatal(err)
+	}
+	result, err = e.Extract(filepath.Join(tmpDir, "services", "api"))
+	if err != nil {
+		t.Fatalf("Extract from git tags failed: %v", err)
+	}
+	if result.GitTag != "services/api/v1.0.0" || !strings.HasPrefix(result.Version, "1.0.1-dev.1+g") {
+		t.Errorf("Expected a dev version one commit after services/api/v1.0.0, got %s from %s",
+			result.Version, result.GitTag)
+	}
+
+	if err := e.SetRevision("no-such-branch"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Extract(tmpDir); err == nil || !strings.Contains(err.Error(), "no-such-branch") {
+		t.Errorf("Expected an unknown revision error, got %v", err)
+	}
+	if err := e.SetRevision("--output=x"); err == nil {
+		t.Error("Expected a revision starting with - to be rejected")
+	}
+}
+
+func TestBumpRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping git integration test")
+	}
+
+	tmpDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		if err := runGitCommand(tmpDir, args...); err != nil {
+			t.Skipf("Failed to set up git repo: %v", err)
+		}
+	}
+	manifest := filepath.Join(tmpDir, "package.json")
+	writeFile(t, manifest, `{"version": "1.0.0"}`)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", "one"}} {
+		if err := runGitCommand(tmpDir, args...); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+
+	e := New(monorepoConfig())
+	if err := e.SetRevision("HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	result, err := e.Bump(tmpDir, BumpOptions{Level: BumpMinor, DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if result.NewVersion != "1.1.0" {
+		t.Errorf("Expected 1.1.0, got %s", result.NewVersion)
+	}
+	if _, err := e.Bump(tmpDir, BumpOptions{Level: BumpMinor}); err == nil {
+		t.Error("Expected bumping a revision to fail")
+	}
+	if data, _ := os.ReadFile(manifest); string(data) != `{"version": "1.0.0"}` {
+		t.Errorf("Expected the work tree to be untouched, got %s", data)
+	}
+}
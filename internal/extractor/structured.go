@@ -0,0 +1,534 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// Structured lookup kinds, matching the ProjectConfig YAML keys. They prefix
+// the MatchedBy description of a structured match, e.g. "path: $.version".
+const (
+	lookupPath    = "path"
+	lookupXPath   = "xpath"
+	lookupTomlKey = "toml_key"
+)
+
+// errLookupNotFound reports that a structured lookup found no value
+var errLookupNotFound = errors.New("lookup not found")
+
+// lookupResult is the value found by a structured lookup. start and end are
+// byte offsets of the value's text in the raw document, or -1 when the value
+// cannot be mapped back to the source (e.g. it contains escapes).
+type lookupResult struct {
+	value      string
+	start, end int
+}
+
+// pathSegment is one step of a parsed path expression: a key or an index
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// structuredLookup returns the kind and expression of the project's
+// structured lookup, or empty strings if it declares none.
+func structuredLookup(project *config.ProjectConfig) (string, string) {
+	switch {
+	case project.Path != "":
+		return lookupPath, project.Path
+	case project.XPath != "":
+		return lookupXPath, project.XPath
+	case project.TomlKey != "":
+		return lookupTomlKey, project.TomlKey
+	}
+	return "", ""
+}
+
+// structuredMatchedBy describes a structured match for ExtractResult.MatchedBy
+func structuredMatchedBy(project *config.ProjectConfig) string {
+	kind, expr := structuredLookup(project)
+	return kind + ": " + expr
+}
+
+// extractProjectVersion extracts a version from file for project, trying the
+// project's structured lookup (if any) before its regex patterns. A document
+// that fails to parse falls through to the regexes, which tolerate the
+// comments and templating that real manifests sometimes contain.
+func (e *VersionExtractor) extractProjectVersion(filePath string,
+	project *config.ProjectConfig) (string, string, error) {
+	if project.HasStructuredLookup() {
+		version, err := e.extractStructuredVersion(filePath, project)
+		if err == nil && version != "" {
+			return version, structuredMatchedBy(project), nil
+		}
+		if len(project.Regex) == 0 {
+			if err != nil && !errors.Is(err, errLookupNotFound) {
+				return "", "", err
+			}
+			return "", "", nil
+		}
+	}
+
+	return e.extractValidVersion(filePath, project.Regex, e.validatorFor(project, filePath))
+}
+
+// extractStructuredVersion evaluates the project's structured lookup against
+// the parsed file and returns the cleaned, validated version.
+func (e *VersionExtractor) extractStructuredVersion(filePath string,
+	project *config.ProjectConfig) (string, error) {
+	matchedBy := structuredMatchedBy(project)
+	found, err := e.lookupStructured(filePath, project)
+	if err != nil {
+		e.tracer.match(MatchTrace{MatchedBy: matchedBy, Rejected: err.Error()})
+		return "", err
+	}
+
+	version := e.cleanVersion(found.value)
+	if !e.validatorFor(project, filePath)(version) {
+		e.tracer.match(MatchTrace{MatchedBy: matchedBy, Capture: found.value,
+			Version: version, Rejected: "not a valid version"})
+		return "", errLookupNotFound
+	}
+	e.tracer.match(MatchTrace{MatchedBy: matchedBy, Capture: found.value, Version: version})
+	return version, nil
+}
+
+// lookupStructured parses filePath with the decoder implied by the project's
+// lookup kind and evaluates the lookup expression.
+func (e *VersionExtractor) lookupStructured(filePath string,
+	project *config.ProjectConfig) (*lookupResult, error) {
+	// Raw content: offsets must index into the file exactly as stored.
+	content, err := e.files().ReadFileContent(filePath, false)
+	if err != nil {
+		return nil, err
+	}
+	data := []byte(content)
+
+	kind, expr := structuredLookup(project)
+	switch kind {
+	case lookupPath:
+		segments, err := parsePathExpression(expr)
+		if err != nil {
+			return nil, err
+		}
+		if isJSONDocument(filePath, data) {
+			return lookupJSON(data, segments)
+		}
+		return lookupYAML(data, segments)
+	case lookupXPath:
+		return lookupXML(data, expr)
+	case lookupTomlKey:
+		return lookupTOML(data, expr)
+	}
+	return nil, fmt.Errorf("project %s has no structured lookup", project.Type)
+}
+
+// isJSONDocument reports whether a path lookup should use the JSON decoder.
+// Everything else goes through the YAML decoder.
+func isJSONDocument(filePath string, data []byte) bool {
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		return true
+	}
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// pathSegmentPattern matches one step of a path expression: .key, ['key'],
+// ["key"] or [index]
+var pathSegmentPattern = regexp.MustCompile(
+	`^(?:\.([A-Za-z0-9_$@-]+)|\[(?:'([^']*)'|"([^"]*)"|([0-9]+))\])`)
+
+// parsePathExpression parses a JSONPath-style expression such as
+// "$.version", "$.packages[0].version" or "$['tool.poetry'].version". Only
+// child keys and array indexes are supported; the leading "$" is optional.
+func parsePathExpression(expr string) ([]pathSegment, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	var segments []pathSegment
+	for rest != "" {
+		m := pathSegmentPattern.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, fmt.Errorf("invalid path expression %q near %q",
+				expr, rest)
+		}
+		switch {
+		case m[1] != "":
+			segments = append(segments, pathSegment{key: m[1]})
+		case m[4] != "":
+			index, _ := strconv.Atoi(m[4])
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+		default:
+			segments = append(segments, pathSegment{key: m[2] + m[3]})
+		}
+		rest = rest[len(m[0]):]
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("empty path expression %q", expr)
+	}
+	return segments, nil
+}
+
+// lookupJSON walks a JSON document token by token, so the byte offsets of
+// the matched value are known without re-serialising the document.
+func lookupJSON(data []byte, segments []pathSegment) (*lookupResult, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var found *lookupResult
+	// walk consumes one value. matching means every segment so far matched,
+	// and depth is the number of segments consumed.
+	var walk func(depth int, matching bool) error
+	walk = func(depth int, matching bool) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{':
+				for dec.More() {
+					keyTok, err := dec.Token()
+					if err != nil {
+						return err
+					}
+					key, _ := keyTok.(string)
+					child := matching && depth < len(segments) &&
+						!segments[depth].isIndex && segments[depth].key == key
+					if err := walk(depth+1, child); err != nil || found != nil {
+						return err
+					}
+				}
+			case '[':
+				for i := 0; dec.More(); i++ {
+					child := matching && depth < len(segments) &&
+						segments[depth].isIndex && segments[depth].index == i
+					if err := walk(depth+1, child); err != nil || found != nil {
+						return err
+					}
+				}
+			}
+			_, err := dec.Token() // closing delimiter
+			return err
+		case string, json.Number:
+			if !matching || depth != len(segments) {
+				return nil
+			}
+			value := fmt.Sprint(v)
+			end := int(dec.InputOffset())
+			found = &lookupResult{value: value, start: -1, end: -1}
+			if _, isString := v.(string); isString {
+				end-- // closing quote
+			}
+			if start := end - len(value); start >= 0 && string(data[start:end]) == value {
+				found.start, found.end = start, end
+			}
+		}
+		return nil
+	}
+
+	if err := walk(0, true); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if found == nil {
+		return nil, errLookupNotFound
+	}
+	return found, nil
+}
+
+// lookupYAML evaluates a path against a YAML document. Scalars are read as
+// their source text, so "version: 1.10" yields "1.10" rather than a float.
+func lookupYAML(data []byte, segments []pathSegment) (*lookupResult, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, errLookupNotFound
+	}
+
+	node := doc.Content[0]
+	for _, seg := range segments {
+		node = yamlChild(node, seg)
+		if node == nil {
+			return nil, errLookupNotFound
+		}
+	}
+	if node.Kind != yaml.ScalarNode {
+		return nil, errLookupNotFound
+	}
+
+	found := &lookupResult{value: node.Value, start: -1, end: -1}
+	if start := yamlOffset(data, node); start >= 0 {
+		end := start + len(node.Value)
+		if end <= len(data) && string(data[start:end]) == node.Value {
+			found.start, found.end = start, end
+		}
+	}
+	return found, nil
+}
+
+// yamlChild returns the child of node selected by seg, following aliases
+func yamlChild(node *yaml.Node, seg pathSegment) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch {
+	case node.Kind == yaml.MappingNode && !seg.isIndex:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == seg.key {
+				return node.Content[i+1]
+			}
+		}
+	case node.Kind == yaml.SequenceNode && seg.isIndex:
+		if seg.index < len(node.Content) {
+			return node.Content[seg.index]
+		}
+	}
+	return nil
+}
+
+// yamlOffset converts a scalar node's line and column to a byte offset of its
+// value, skipping the opening quote of quoted scalars. It returns -1 for
+// block scalars, whose text does not start at the node position.
+func yamlOffset(data []byte, node *yaml.Node) int {
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return -1
+	}
+	offset := 0
+	for line := 1; line < node.Line; line++ {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return -1
+		}
+		offset += i + 1
+	}
+	// Columns count characters, not bytes.
+	lineText := data[offset:]
+	if i := bytes.IndexByte(lineText, '\n'); i >= 0 {
+		lineText = lineText[:i]
+	}
+	runes := []rune(string(lineText))
+	if node.Column-1 > len(runes) {
+		return -1
+	}
+	offset += len(string(runes[:node.Column-1]))
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+		offset++
+	}
+	return offset
+}
+
+// lookupXML evaluates a simple absolute element path such as
+// "/project/version", optionally ending in an attribute step such as
+// "/manifest/@versionName". Steps match local names, so namespace prefixes
+// in the document or the expression are ignored. The first match wins.
+func lookupXML(data []byte, expr string) (*lookupResult, error) {
+	steps := strings.Split(strings.Trim(strings.TrimSpace(expr), "/"), "/")
+	if len(steps) == 0 || steps[0] == "" || !strings.HasPrefix(expr, "/") {
+		return nil, fmt.Errorf("invalid xpath %q: expected an absolute path",
+			expr)
+	}
+	attr := ""
+	if last := steps[len(steps)-1]; strings.HasPrefix(last, "@") {
+		attr = localName(last[1:])
+		steps = steps[:len(steps)-1]
+	}
+	for i, step := range steps {
+		steps[i] = localName(step)
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	var stack []string
+	var text strings.Builder
+	textStart := -1
+
+	matches := func() bool {
+		if len(stack) != len(steps) {
+			return false
+		}
+		for i := range steps {
+			if stack[i] != steps[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for {
+		before := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, errLookupNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			if !matches() {
+				continue
+			}
+			if attr != "" {
+				for _, a := range t.Attr {
+					if a.Name.Local == attr {
+						return xmlAttrResult(data[before:dec.InputOffset()],
+							before, a), nil
+					}
+				}
+				continue
+			}
+			text.Reset()
+			textStart = int(dec.InputOffset())
+		case xml.CharData:
+			if attr == "" && matches() {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if attr == "" && matches() && textStart >= 0 {
+				return xmlTextResult(data, text.String(), textStart,
+					before), nil
+			}
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+}
+
+// xmlTextResult builds the result for an element's text content, mapping it
+// back to the source when the raw text equals the decoded text (no entities,
+// comments or CDATA).
+func xmlTextResult(data []byte, text string, start, end int) *lookupResult {
+	value := strings.TrimSpace(text)
+	found := &lookupResult{value: value, start: -1, end: -1}
+	if raw := string(data[start:end]); raw == text {
+		if i := strings.Index(raw, value); i >= 0 {
+			found.start, found.end = start+i, start+i+len(value)
+		}
+	}
+	return found
+}
+
+// xmlAttrResult builds the result for an attribute, locating its value in
+// the raw start tag.
+func xmlAttrResult(tag []byte, tagStart int, a xml.Attr) *lookupResult {
+	found := &lookupResult{value: strings.TrimSpace(a.Value), start: -1, end: -1}
+	name := a.Name.Local
+	if a.Name.Space != "" {
+		name = `[A-Za-z0-9_.-]+:` + regexp.QuoteMeta(name)
+	} else {
+		name = regexp.QuoteMeta(name)
+	}
+	re := regexp.MustCompile(`\s` + name + `\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	if m := re.FindSubmatchIndex(tag); m != nil {
+		s, e := m[2], m[3]
+		if s < 0 {
+			s, e = m[4], m[5]
+		}
+		if raw := string(tag[s:e]); raw == a.Value {
+			i := strings.Index(raw, found.value)
+			found.start, found.end = tagStart+s+i, tagStart+s+i+len(found.value)
+		}
+	}
+	return found
+}
+
+// localName strips a namespace prefix such as "android:" from a name
+func localName(name string) string {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// lookupTOML evaluates a dotted key such as "package.version" against a TOML
+// document.
+func lookupTOML(data []byte, key string) (*lookupResult, error) {
+	var doc map[string]interface{}
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse TOML: %w", err)
+	}
+
+	keys := strings.Split(key, ".")
+	var node interface{} = doc
+	for _, k := range keys {
+		table, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, errLookupNotFound
+		}
+		if node, ok = table[k]; !ok {
+			return nil, errLookupNotFound
+		}
+	}
+
+	var value string
+	switch v := node.(type) {
+	case string:
+		value = v
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	default:
+		return nil, errLookupNotFound
+	}
+
+	start, end := tomlValueOffset(data, keys, value)
+	return &lookupResult{value: value, start: start, end: end}, nil
+}
+
+// tomlValueOffset locates a decoded TOML value in the source. The key may be
+// written under a table header ([package] + version) or as a dotted key
+// (package.version) in an enclosing table, so each split point is tried.
+// It returns -1, -1 if the value cannot be found verbatim.
+func tomlValueOffset(data []byte, keys []string, value string) (int, int) {
+	content := string(data)
+	for split := len(keys) - 1; split >= 0; split-- {
+		table := strings.Join(keys[:split], ".")
+		keyRe := regexp.MustCompile(`^\s*` +
+			regexp.QuoteMeta(strings.Join(keys[split:], ".")) +
+			`\s*=\s*(?:"|'|)(` + regexp.QuoteMeta(value) + `)(?:"|'|)\s*(?:#.*)?$`)
+
+		current := ""
+		offset := 0
+		for _, line := range strings.SplitAfter(content, "\n") {
+			text := strings.TrimRight(line, "\r\n")
+			trimmed := strings.TrimSpace(text)
+			if strings.HasPrefix(trimmed, "[") {
+				// "[package] # comment" and "[[bin]]" both name their table
+				// before the first closing bracket.
+				header := strings.SplitN(trimmed, "]", 2)[0]
+				current = strings.TrimSpace(strings.TrimLeft(header, "["))
+			} else if current == table {
+				if m := keyRe.FindStringSubmatchIndex(text); m != nil {
+					return offset + m[2], offset + m[3]
+				}
+			}
+			offset += len(line)
+		}
+	}
+	return -1, -1
+}
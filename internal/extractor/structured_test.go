@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+func TestParsePathExpression(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []pathSegment
+		wantErr bool
+	}{
+		{"$.version", []pathSegment{{key: "version"}}, false},
+		{"version", []pathSegment{{key: "version"}}, false},
+		{"$.packages[1].version", []pathSegment{
+			{key: "packages"}, {index: 1, isIndex: true}, {key: "version"}}, false},
+		{"$['tool.poetry'].version", []pathSegment{
+			{key: "tool.poetry"}, {key: "version"}}, false},
+		{`$["a b"]`, []pathSegment{{key: "a b"}}, false},
+		{"$", nil, true},
+		{"$.a[x]", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePathExpression(tt.expr)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parsePathExpression(%q): expected error", tt.expr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parsePathExpression(%q): unexpected error: %v", tt.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePathExpression(%q) = %+v, want %+v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestStructuredLookups(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		project   config.ProjectConfig
+		want      string
+		wantBy    string
+		wantSpan  bool
+		wantFound bool
+	}{
+		{
+			name: "JSON ignores a nested dependency version",
+			file: "package.json",
+			content: `{
+  "dependencies": {"lib": {"version": "9.9.9"}},
+  "version": "1.2.3"
+}`,
+			project:   config.ProjectConfig{Path: "$.version", Regex: []string{`"version":\s*"([^"]+)"`}},
+			want:      "1.2.3",
+			wantBy:    "path: $.version",
+			wantFound: true,
+		},
+		{
+			name:      "YAML keeps scalar text",
+			file:      "Chart.yaml",
+			content:   "apiVersion: v2\nname: chart\nversion: 1.10\n",
+			project:   config.ProjectConfig{Path: "$.version"},
+			want:      "1.10",
+			wantBy:    "path: $.version",
+			wantFound: true,
+		},
+		{
+			name:      "YAML nested sequence",
+			file:      "release.yaml",
+			content:   "components:\n  - name: api\n    version: '2.0.1'\n",
+			project:   config.ProjectConfig{Path: "$.components[0].version"},
+			want:      "2.0.1",
+			wantBy:    "path: $.components[0].version",
+			wantFound: true,
+		},
+		{
+			name: "XML skips the parent version",
+			file: "pom.xml",
+			content: `<?xml version="1.0"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <parent><version>5.0.0</version></parent>
+  <version>1.4.0</version>
+</project>`,
+			project:   config.ProjectConfig{XPath: "/project/version"},
+			want:      "1.4.0",
+			wantBy:    "xpath: /project/version",
+			wantFound: true,
+		},
+		{
+			name: "XML attribute with namespace prefix",
+			file: "AndroidManifest.xml",
+			content: `<manifest xmlns:android="http://schemas.android.com/apk/res/android"
+    android:versionName="3.2.1">
+</manifest>`,
+			project:   config.ProjectConfig{XPath: "/manifest/@android:versionName"},
+			want:      "3.2.1",
+			wantBy:    "xpath: /manifest/@android:versionName",
+			wantFound: true,
+		},
+		{
+			name: "TOML table key",
+			file: "Cargo.toml",
+			content: `[dependencies]
+serde = { version = "1.0.0" }
+
+[package]
+name = "crate"
+version = "0.7.3"
+`,
+			project:   config.ProjectConfig{TomlKey: "package.version"},
+			want:      "0.7.3",
+			wantBy:    "toml_key: package.version",
+			wantFound: true,
+		},
+		{
+			name:      "TOML dotted key",
+			file:      "tool.toml",
+			content:   "package.version = \"4.5.6\"\n",
+			project:   config.ProjectConfig{TomlKey: "package.version"},
+			want:      "4.5.6",
+			wantBy:    "toml_key: package.version",
+			wantFound: true,
+		},
+		{
+			name:    "missing key without regex finds nothing",
+			file:    "package.json",
+			content: `{"name": "x"}`,
+			project: config.ProjectConfig{Path: "$.version"},
+		},
+		{
+			name:      "unparseable document falls back to regex",
+			file:      "package.json",
+			content:   "{\n  // comment\n  \"version\": \"0.1.0\"\n}\n",
+			project:   config.ProjectConfig{Path: "$.version", Regex: []string{`"version":\s*"([^"]+)"`}},
+			want:      "0.1.0",
+			wantBy:    `"version":\s*"([^"]+)"`,
+			wantFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tt.file)
+			writeFile(t, file, tt.content)
+
+			e := New(&config.Config{})
+			version, matchedBy, err := e.extractProjectVersion(file, &tt.project)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantFound {
+				if version != "" {
+					t.Errorf("expected no version, got %q", version)
+				}
+				return
+			}
+			if version != tt.want {
+				t.Errorf("expected version %q, got %q", tt.want, version)
+			}
+			if matchedBy != tt.wantBy {
+				t.Errorf("expected matched_by %q, got %q", tt.wantBy, matchedBy)
+			}
+
+			if !tt.project.HasStructuredLookup() || matchedBy != structuredMatchedBy(&tt.project) {
+				return
+			}
+			// The reported offsets must point at the value in the source.
+			found, err := e.lookupStructured(file, &tt.project)
+			if err != nil {
+				t.Fatalf("lookup: %v", err)
+			}
+			if found.start < 0 || tt.content[found.start:found.end] != found.value {
+				t.Errorf("bad span [%d:%d] for %q", found.start, found.end, found.value)
+			}
+		})
+	}
+}
+
+// TestStructuredLookupExtractAndBump exercises a structured lookup through
+// Extract and Bump with a dependency version that a regex would hit first.
+func TestStructuredLookupExtractAndBump(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "package.json")
+	writeFile(t, file, "{\n  \"engines\": {\"version\": \"18.0.0\"},\n  \"version\": \"2.0.0\"\n}\n")
+
+	cfg := &config.Config{
+		Projects: []config.ProjectConfig{
+			{
+				Type:     "JavaScript",
+				Subtype:  "npm",
+				File:     "package.json",
+				Path:     "$.version",
+				Regex:    []string{`"version":\s*"([^"]+)"`},
+				Priority: 1,
+			},
+		},
+	}
+
+	result, err := New(cfg).Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result.Version != "2.0.0" || result.MatchedBy != "path: $.version" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if _, err := New(cfg).Bump(tmpDir, BumpOptions{Level: BumpMajor}); err != nil {
+		t.Fatalf("bump failed: %v", err)
+	}
+	data, _ := os.ReadFile(file)
+	want := "{\n  \"engines\": {\"version\": \"18.0.0\"},\n  \"version\": \"3.0.0\"\n}\n"
+	if string(data) != want {
+		t.Errorf("unexpected content after bump:\n%s", data)
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// Trace records the decisions Extract made, for the explain command: each
+// project type tried in priority order, the files found for it and how each
+// file was examined. Project types after the one that produced the version
+// are not tried, so they do not appear.
+type Trace struct {
+	Projects []*ProjectTrace `json:"projects"`
+}
+
+// ProjectTrace records how a project type was tried
+type ProjectTrace struct {
+	ProjectType string       `json:"project_type"`
+	Subtype     string       `json:"subtype,omitempty"`
+	Pattern     string       `json:"pattern"`
+	Files       []string     `json:"files"`             // Files found in the file index
+	Skipped     string       `json:"skipped,omitempty"` // Why the files were not examined
+	Attempts    []*FileTrace `json:"attempts,omitempty"`
+}
+
+// FileTrace records how a file was examined
+type FileTrace struct {
+	File       string        `json:"file"`
+	Validation string        `json:"validation,omitempty"` // The rules captured versions must follow
+	Matches    []*MatchTrace `json:"matches,omitempty"`
+	Dynamic    string        `json:"dynamic_indicator,omitempty"` // The dynamic version indicator that fired
+	Git        *GitTrace     `json:"git,omitempty"`
+	Outcome    string        `json:"outcome"` // "selected", or why the file was passed over
+}
+
+// MatchTrace records one attempt to find a version in a file: a regex
+// pattern, a structured lookup or a manifest parser
+type MatchTrace struct {
+	MatchedBy string `json:"matched_by"`
+	Capture   string `json:"capture,omitempty"`  // The raw capture
+	Version   string `json:"version,omitempty"`  // The capture after cleaning
+	Rejected  string `json:"rejected,omitempty"` // Why the capture was not used
+}
+
+// GitTrace records the git tag fallback
+type GitTrace struct {
+	TagPrefix string `json:"tag_prefix,omitempty"`
+	Policy    string `json:"policy"`
+	Strategy  string `json:"strategy,omitempty"` // The lookup that found the tag
+	Tag       string `json:"tag,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Selected is the outcome of the file that produced the version
+const Selected = "selected"
+
+// tracer collects a Trace during one extraction. Its methods do nothing on
+// a nil tracer, so extraction code records unconditionally.
+type tracer struct {
+	trace   Trace
+	project *ProjectTrace
+	file    *FileTrace
+}
+
+// startProject records that a project type is tried with the files found
+// for it
+func (t *tracer) startProject(project config.ProjectConfig, files []string) {
+	if t == nil {
+		return
+	}
+	t.project = &ProjectTrace{
+		ProjectType: project.Type,
+		Subtype:     project.Subtype,
+		Pattern:     project.File,
+		Files:       append([]string{}, files...),
+	}
+	t.file = nil
+	t.trace.Projects = append(t.trace.Projects, t.project)
+}
+
+// skipProject records why the current project type's files were not
+// examined
+func (t *tracer) skipProject(reason string) {
+	if t == nil || t.project == nil {
+		return
+	}
+	t.project.Skipped = reason
+}
+
+// startFile records that a file is examined for the current project type
+func (t *tracer) startFile(file, validation string) {
+	if t == nil || t.project == nil {
+		return
+	}
+	t.file = &FileTrace{File: file, Validation: validation}
+	t.project.Attempts = append(t.project.Attempts, t.file)
+}
+
+// match records an attempt to find a version in the current file. An
+// attempt repeating the one before, as when a multi-line pattern is tried
+// against the original and the whitespace-collapsed content, is dropped.
+func (t *tracer) match(m MatchTrace) {
+	if t == nil || t.file == nil {
+		return
+	}
+	if n := len(t.file.Matches); n > 0 && *t.file.Matches[n-1] == m {
+		return
+	}
+	t.file.Matches = append(t.file.Matches, &m)
+}
+
+// dynamic records the dynamic version indicator that fired for the current
+// file
+func (t *tracer) dynamic(indicator config.DynamicVersionIndicator) {
+	if t == nil || t.file == nil {
+		return
+	}
+	var conditions []string
+	if indicator.Exists && indicator.Path != "" {
+		conditions = append(conditions, fmt.Sprintf("section %s exists", indicator.Path))
+	}
+	if indicator.Field != "" && len(indicator.Contains) > 0 {
+		conditions = append(conditions, fmt.Sprintf("%s contains %s",
+			indicator.Field, strings.Join(indicator.Contains, " or ")))
+	}
+	t.file.Dynamic = strings.Join(conditions, ", or ")
+}
+
+// git records the git tag fallback for the current file
+func (t *tracer) git(g *GitTrace) {
+	if t == nil || t.file == nil {
+		return
+	}
+	t.file.Git = g
+}
+
+// outcome records what became of the current file
+func (t *tracer) outcome(format string, args ...any) {
+	if t == nil || t.file == nil {
+		return
+	}
+	t.file.Outcome = fmt.Sprintf(format, args...)
+}
+
+// validationName describes the rules validatorFor applies for a project,
+// for the trace
+func (e *VersionExtractor) validationName(project *config.ProjectConfig) string {
+	if e.schemeRule != nil {
+		return "scheme " + e.schemeRule.String()
+	}
+	if project != nil {
+		if rule, err := project.SchemeRule(); err == nil && rule != nil {
+			return "version_scheme " + rule.String()
+		}
+	}
+	return "default version forms"
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package extractor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+func TestExtractTrace(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"package.json": `{"name": "app", "version": "latest"}`,
+		"VERSION":      "v2.0.1\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{Projects: []config.ProjectConfig{
+		{Type: "JavaScript", File: "package.json", Regex: []string{`"version":\s*"([^"]+)"`}},
+		{Type: "Ruby", File: "Gemfile", Regex: []string{`VERSION = "([^"]+)"`}},
+		{Type: "Generic", File: "VERSION", Regex: []string{`^release (\S+)`, `^(\S+)$`}},
+	}}
+
+	ext := New(cfg)
+	result, err := ext.Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("Expected extraction to succeed: %v", err)
+	}
+	if result.Trace != nil {
+		t.Error("Expected no trace unless tracing is enabled")
+	}
+
+	ext.SetTrace(true)
+	result, err = ext.Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("Expected extraction to succeed: %v", err)
+	}
+	if result.Version != "2.0.1" || result.Trace == nil {
+		t.Fatalf("Expected version 2.0.1 with a trace, got %+v", result)
+	}
+
+	projects := result.Trace.Projects
+	if len(projects) != 3 {
+		t.Fatalf("Expected 3 project types in the trace, got %d", len(projects))
+	}
+
+	js := projects[0]
+	if js.ProjectType != "JavaScript" || len(js.Files) != 1 || len(js.Attempts) != 1 {
+		t.Fatalf("Unexpected JavaScript trace: %+v", js)
+	}
+	attempt := js.Attempts[0]
+	if attempt.Outcome != "no valid version" || len(attempt.Matches) != 1 {
+		t.Fatalf("Unexpected package.json trace: %+v", attempt)
+	}
+	if m := attempt.Matches[0]; m.Capture != "latest" || m.Rejected != "not a valid version" {
+		t.Errorf("Expected the capture latest to be rejected, got %+v", m)
+	}
+
+	if ruby := projects[1]; len(ruby.Files) != 0 || len(ruby.Attempts) != 0 {
+		t.Errorf("Expected no Gemfile to be found, got %+v", ruby)
+	}
+
+	generic := projects[2].Attempts[0]
+	if generic.Outcome != Selected || len(generic.Matches) != 2 {
+		t.Fatalf("Unexpected VERSION trace: %+v", generic)
+	}
+	if m := generic.Matches[0]; m.Rejected != "no match" {
+		t.Errorf("Expected the first pattern not to match, got %+v", m)
+	}
+	if m := generic.Matches[1]; m.Capture != "v2.0.1" || m.Version != "2.0.1" || m.Rejected != "" {
+		t.Errorf("Expected v2.0.1 to be cleaned to 2.0.1 and accepted, got %+v", m)
+	}
+}
+
+func TestExtractTraceDynamic(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping git integration test")
+	}
+
+	tmpDir := t.TempDir()
+	content := `{"name": "app", "version": "0.0.0-development"}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+		{"add", "package.json"},
+		{"commit", "-m", "Initial commit"},
+		{"tag", "-a", "v3.0.0", "-m", "Test tag"},
+	} {
+		if err := runGitCommand(tmpDir, args...); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+
+	ext := NewWithOptions(createTestConfigForLanguage("JavaScript", "npm", "package.json"), true)
+	ext.SetTrace(true)
+	result, err := ext.Extract(tmpDir)
+	if err != nil {
+		t.Fatalf("Expected extraction to succeed: %v", err)
+	}
+
+	attempt := result.Trace.Projects[0].Attempts[0]
+	if attempt.Dynamic != "version contains 0.0.0-development or 0.0.0-semantic-release" {
+		t.Errorf("Expected the version indicator to fire, got %q", attempt.Dynamic)
+	}
+	if attempt.Git == nil || attempt.Git.Strategy != "describe --match v*" ||
+		attempt.Git.Tag != "v3.0.0" || attempt.Git.Policy != "nearest" {
+		t.Errorf("Unexpected git trace: %+v", attempt.Git)
+	}
+	if attempt.Outcome != Selected {
+		t.Errorf("Expected package.json to be selected, got %q", attempt.Outcome)
+	}
+}
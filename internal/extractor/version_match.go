@@ -4,11 +4,12 @@
 package extractor
 
 import (
-	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+	"github.com/lfreleng-actions/version-extract-action/internal/version"
 )
 
 // Version validation patterns
@@ -31,9 +32,18 @@ const (
 // the substitution runs against whole file contents.
 var whitespaceRun = regexp.MustCompile(`\s+`)
 
-// extractVersionFromFile attempts to extract version using regex patterns
+// extractVersionFromFile attempts to extract version using regex patterns,
+// validating matches with the default rules of a project type that sets no
+// version_scheme
 func (e *VersionExtractor) extractVersionFromFile(filePath string,
 	patterns []string) (string, string, error) {
+	return e.extractValidVersion(filePath, patterns, e.validatorFor(nil, filePath))
+}
+
+// extractValidVersion attempts to extract a version accepted by valid using
+// regex patterns
+func (e *VersionExtractor) extractValidVersion(filePath string,
+	patterns []string, valid versionValidator) (string, string, error) {
 
 	// Special handling for pyproject.toml files
 	// The special handler is authoritative - don't fall back to regex patterns
@@ -41,17 +51,59 @@ func (e *VersionExtractor) extractVersionFromFile(filePath string,
 	// Match on the basename, not a suffix: "my-pyproject.toml" is a
 	// different file and must go through the configured patterns.
 	if filepath.Base(filePath) == "pyproject.toml" {
-		return e.extractFromPyprojectToml(filePath)
+		version, matchedBy, err := e.extractFromPyprojectToml(filePath, valid)
+		e.traceParser("pyproject.toml parser", version, matchedBy, err)
+		return version, matchedBy, err
+	}
+
+	// Cargo.toml is likewise section-aware: dependency tables carry version
+	// keys too, and members may inherit the workspace version.
+	if filepath.Base(filePath) == "Cargo.toml" {
+		version, matchedBy, err := e.extractFromCargoToml(filePath, patterns, valid)
+		e.traceParser("Cargo.toml parser", version, matchedBy, err)
+		return version, matchedBy, err
 	}
 
-	return e.extractVersionWithPatterns(filePath, patterns)
+	return e.extractVersionWithPatterns(filePath, patterns, valid)
+}
+
+// traceParser records the outcome of a manifest-specific parser
+func (e *VersionExtractor) traceParser(parser, version, matchedBy string, err error) {
+	switch {
+	case err != nil:
+		e.tracer.match(MatchTrace{MatchedBy: parser, Rejected: err.Error()})
+	case version == "":
+		e.tracer.match(MatchTrace{MatchedBy: parser, Rejected: "no valid version"})
+	default:
+		e.tracer.match(MatchTrace{MatchedBy: matchedBy, Version: version})
+	}
+}
+
+// acceptCapture cleans a version captured by pattern and validates it,
+// returning "" when it is rejected
+func (e *VersionExtractor) acceptCapture(pattern, capture string,
+	valid versionValidator) string {
+	version := strings.TrimSpace(capture)
+	if version == "" {
+		e.tracer.match(MatchTrace{MatchedBy: pattern, Capture: capture,
+			Rejected: "empty capture"})
+		return ""
+	}
+	cleaned := e.cleanVersion(version)
+	if !valid(cleaned) {
+		e.tracer.match(MatchTrace{MatchedBy: pattern, Capture: capture,
+			Version: cleaned, Rejected: "not a valid version"})
+		return ""
+	}
+	e.tracer.match(MatchTrace{MatchedBy: pattern, Capture: capture, Version: cleaned})
+	return cleaned
 }
 
 // extractVersionWithPatterns extracts version from a file using regex patterns
 // This is separated from extractVersionFromFile to avoid recursive issues when
 // called from extractFromPyprojectToml for __version__.py files
 func (e *VersionExtractor) extractVersionWithPatterns(filePath string,
-	patterns []string) (string, string, error) {
+	patterns []string, valid versionValidator) (string, string, error) {
 
 	// Detect patterns that need multi-line processing
 	needsMultiLine := false
@@ -64,9 +116,9 @@ func (e *VersionExtractor) extractVersionWithPatterns(filePath string,
 
 	// Use different processing approaches based on pattern complexity
 	if needsMultiLine {
-		return e.extractWithMultiLineSupport(filePath, patterns)
+		return e.extractWithMultiLineSupport(filePath, patterns, valid)
 	}
-	return e.extractWithLineByLine(filePath, patterns)
+	return e.extractWithLineByLine(filePath, patterns, valid)
 }
 
 // Check if a pattern likely needs multi-line matching
@@ -104,8 +156,9 @@ func (e *VersionExtractor) isMultiLinePattern(pattern string) bool {
 }
 
 // Extract using full file content (for multi-line patterns)
-func (e *VersionExtractor) extractWithMultiLineSupport(filePath string, patterns []string) (string, string, error) {
-	fileContent, err := fileReader.ReadFileContent(filePath, true)
+func (e *VersionExtractor) extractWithMultiLineSupport(filePath string, patterns []string,
+	valid versionValidator) (string, string, error) {
+	fileContent, err := e.files().ReadFileContent(filePath, true)
 	if err != nil {
 		return "", "", err
 	}
@@ -119,57 +172,55 @@ func (e *VersionExtractor) extractWithMultiLineSupport(filePath string, patterns
 	for _, pattern := range patterns {
 		re, err := getCompiledRegex(pattern)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Invalid regex pattern '%s': %v\n", pattern, err)
+			e.log().Warn("invalid regex pattern", "pattern", pattern, "error", err)
+			e.tracer.match(MatchTrace{MatchedBy: pattern, Rejected: "invalid regex: " + err.Error()})
 			continue
 		}
 
 		matches := re.FindStringSubmatch(normalizedContent)
-		if len(matches) > 1 {
-			version := strings.TrimSpace(matches[1])
-			if version != "" {
-				cleanVersion := e.cleanVersion(version)
-				if e.isValidVersion(cleanVersion) {
-					return cleanVersion, pattern, nil
-				}
+		matched := len(matches) > 1
+		if matched {
+			if version := e.acceptCapture(pattern, matches[1], valid); version != "" {
+				return version, pattern, nil
 			}
 		}
 
 		// Also try matching against original content (preserving formatting)
 		matches = re.FindStringSubmatch(fileContent)
 		if len(matches) > 1 {
-			version := strings.TrimSpace(matches[1])
-			if version != "" {
-				cleanVersion := e.cleanVersion(version)
-				if e.isValidVersion(cleanVersion) {
-					return cleanVersion, pattern, nil
-				}
+			matched = true
+			if version := e.acceptCapture(pattern, matches[1], valid); version != "" {
+				return version, pattern, nil
 			}
 		}
+		if !matched {
+			e.tracer.match(MatchTrace{MatchedBy: pattern, Rejected: "no match"})
+		}
 	}
 
 	return "", "", nil
 }
 
 // Extract using line-by-line processing (for simple patterns)
-func (e *VersionExtractor) extractWithLineByLine(filePath string, patterns []string) (string, string, error) {
+func (e *VersionExtractor) extractWithLineByLine(filePath string, patterns []string,
+	valid versionValidator) (string, string, error) {
 	// Try each regex pattern and return first valid version
 	for _, pattern := range patterns {
 		re, err := getCompiledRegex(pattern)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Invalid regex pattern '%s': %v\n", pattern, err)
+			e.log().Warn("invalid regex pattern", "pattern", pattern, "error", err)
+			e.tracer.match(MatchTrace{MatchedBy: pattern, Rejected: "invalid regex: " + err.Error()})
 			continue
 		}
 
 		// Use centralized line processing
-		result, err := fileReader.ProcessFileLineByLine(filePath, func(line string) (string, bool) {
+		matched := false
+		result, err := e.files().ProcessFileLineByLine(filePath, func(line string) (string, bool) {
 			matches := re.FindStringSubmatch(line)
 			if len(matches) > 1 {
-				version := strings.TrimSpace(matches[1])
-				if version != "" {
-					cleanVersion := e.cleanVersion(version)
-					if e.isValidVersion(cleanVersion) {
-						return cleanVersion, true
-					}
+				matched = true
+				if version := e.acceptCapture(pattern, matches[1], valid); version != "" {
+					return version, true
 				}
 			}
 			return "", false
@@ -182,6 +233,9 @@ func (e *VersionExtractor) extractWithLineByLine(filePath string, patterns []str
 		if result != "" {
 			return result, pattern, nil
 		}
+		if !matched {
+			e.tracer.match(MatchTrace{MatchedBy: pattern, Rejected: "no match"})
+		}
 	}
 
 	return "", "", nil
@@ -210,6 +264,79 @@ func (e *VersionExtractor) cleanVersion(version string) string {
 	return version
 }
 
+// pythonVersionFiles lists the Python manifests whose versions follow PEP 440
+var pythonVersionFiles = map[string]bool{
+	"pyproject.toml": true,
+	"setup.py":       true,
+	"setup.cfg":      true,
+	"__init__.py":    true,
+	"__version__.py": true,
+	"_version.py":    true,
+}
+
+// isPythonVersionFile reports whether filePath is a Python manifest
+func isPythonVersionFile(filePath string) bool {
+	return pythonVersionFiles[filepath.Base(filePath)]
+}
+
+// mavenVersionFiles lists the Maven and Gradle build files whose versions
+// follow Maven's conventions
+var mavenVersionFiles = map[string]bool{
+	"pom.xml":           true,
+	"build.gradle":      true,
+	"build.gradle.kts":  true,
+	"gradle.properties": true,
+}
+
+// isMavenVersionFile reports whether filePath is a Maven or Gradle build file
+func isMavenVersionFile(filePath string) bool {
+	return mavenVersionFiles[filepath.Base(filePath)]
+}
+
+// versionValidator reports whether a matched version is acceptable for the
+// project type it was matched for
+type versionValidator func(string) bool
+
+// validatorFor returns the validation for versions matched in filePath for
+// project: the --scheme override, the project's version_scheme, or when
+// neither is set the default rules. project may be nil.
+func (e *VersionExtractor) validatorFor(project *config.ProjectConfig,
+	filePath string) versionValidator {
+	if e.schemeRule != nil {
+		return e.schemeRule.Match
+	}
+	if project != nil {
+		rule, err := project.SchemeRule()
+		if err != nil {
+			e.log().Warn("ignoring invalid version_scheme", "project", project.Type,
+				"error", err)
+		} else if rule != nil {
+			return rule.Match
+		}
+	}
+	return func(v string) bool {
+		return e.isValidVersionFor(filePath, v)
+	}
+}
+
+// isValidVersionFor validates a version found in filePath. Python manifests
+// also accept any PEP 440 version, such as 1.0rc1 or 2.0.post3, and Maven
+// and Gradle builds any Maven version, such as 2.0-M1 or 1.2.3.Final.
+func (e *VersionExtractor) isValidVersionFor(filePath, v string) bool {
+	if e.isValidVersion(v) {
+		return true
+	}
+	switch {
+	case isPythonVersionFile(filePath):
+		_, err := version.ParsePEP440(v)
+		return err == nil
+	case isMavenVersionFile(filePath):
+		rule, err := version.ParseSchemeRule("maven")
+		return err == nil && rule.Match(v)
+	}
+	return false
+}
+
 // isValidVersion performs basic validation on version strings
 func (e *VersionExtractor) isValidVersion(version string) bool {
 	if version == "" {
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+)
+
+// Backend answers the repository queries version extraction needs. The exec
+// backend runs the git binary; the native backend reads the .git directory
+// itself, for environments without git or where running it is slow.
+//
+// Queries about HEAD answer for the revision set with SetRevision instead,
+// when one is set.
+type Backend interface {
+	// IsRepository reports whether the working directory is inside a
+	// repository
+	IsRepository() bool
+	// RelativePath returns the working directory relative to the top of the
+	// work tree, using "/" separators, or "" at the top
+	RelativePath() (string, error)
+	// SetRevision makes queries about HEAD answer for rev, a tag, branch or
+	// commit ID, instead. An empty rev restores HEAD.
+	SetRevision(rev string)
+	// SetContext stops queries once ctx is done. A nil ctx never is.
+	SetContext(ctx context.Context)
+	// Tree returns the files committed at HEAD. Symbolic links and
+	// submodules are left out.
+	Tree() (fs.FS, error)
+	// Head returns the ID of the commit HEAD points at
+	Head() (string, error)
+	// Describe returns the tag nearest to HEAD whose name matches the glob
+	// match, or any tag when match is empty, skipping tags matching any of
+	// excludes
+	Describe(match string, excludes []string) (string, error)
+	// PointsAt returns the tags matching the glob match, or every tag when
+	// match is empty, on the commit tag points at
+	PointsAt(tag, match string) ([]string, error)
+	// ListTags returns the tag names in descending version sort, only those
+	// reachable from HEAD when merged is set
+	ListTags(merged bool) ([]string, error)
+	// RemoteTags returns the names of origin's tags in descending version
+	// sort
+	RemoteTags() ([]string, error)
+	// Distance returns the number of commits reachable from HEAD but not
+	// from tag
+	Distance(tag string) (int, error)
+	// Dirty reports whether tracked files have uncommitted changes, staged
+	// or not. A revision has none.
+	Dirty() (bool, error)
+	// FetchTags fetches origin's tags
+	FetchTags() error
+}
+
+// BackendKind names a Backend implementation
+type BackendKind string
+
+// Supported backends
+const (
+	// BackendAuto uses the exec backend when git is installed, otherwise
+	// the native backend
+	BackendAuto BackendKind = "auto"
+	// BackendExec runs the git binary
+	BackendExec BackendKind = "exec"
+	// BackendNative reads the .git directory directly. It cannot contact
+	// remotes, so the ls-remote fallback and FetchTags are unavailable.
+	BackendNative BackendKind = "native"
+)
+
+// BackendKinds returns the supported backend names
+func BackendKinds() []string {
+	return []string{string(BackendAuto), string(BackendExec), string(BackendNative)}
+}
+
+// ParseBackendKind validates a backend name. An empty name selects
+// BackendAuto.
+func ParseBackendKind(name string) (BackendKind, error) {
+	if name == "" {
+		return BackendAuto, nil
+	}
+	for _, kind := range BackendKinds() {
+		if name == kind {
+			return BackendKind(name), nil
+		}
+	}
+	return "", fmt.Errorf("unknown git backend %q (expected one of: %s)",
+		name, strings.Join(BackendKinds(), ", "))
+}
+
+// NewBackend returns a backend of the given kind for a working directory
+func NewBackend(kind BackendKind, workingDir string) Backend {
+	switch kind {
+	case BackendNative:
+		return newNativeBackend(workingDir)
+	case BackendExec:
+		return &execBackend{workingDir: workingDir}
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return newNativeBackend(workingDir)
+	}
+	return &execBackend{workingDir: workingDir}
+}
+
+// SetBackend replaces the backend answering repository queries, which is
+// chosen as for BackendAuto by default
+func (g *GitVersionExtractor) SetBackend(backend Backend) {
+	backend.SetRevision(g.revision)
+	backend.SetContext(g.ctx)
+	g.backend = backend
+}
+
+// SetRevision looks up tags relative to rev, a tag, branch or commit ID,
+// instead of HEAD, as if rev were checked out with no local changes. An
+// empty rev restores HEAD.
+func (g *GitVersionExtractor) SetRevision(rev string) {
+	g.revision = rev
+	g.backend.SetRevision(rev)
+}
+
+// SetContext stops git commands once ctx is done, failing the lookup in
+// progress with ctx's error
+func (g *GitVersionExtractor) SetContext(ctx context.Context) {
+	g.ctx = ctx
+	g.backend.SetContext(ctx)
+}
+
+// Tree returns the files committed at the revision, or at HEAD when none is
+// set, with paths relative to the top of the work tree
+func (g *GitVersionExtractor) Tree() (fs.FS, error) {
+	return g.backend.Tree()
+}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionStyle selects how a version derived from a git tag describes
+// commits made after the tag.
+type VersionStyle string
+
+// Supported version styles. Every style reports the tag version unchanged
+// when HEAD is the tagged commit and the working tree is clean.
+const (
+	// StyleExact reports the tag version regardless of later commits
+	StyleExact VersionStyle = "exact"
+	// StyleSemver reports e.g. 1.2.4-dev.7+g1a2b3c4
+	StyleSemver VersionStyle = "semver"
+	// StylePEP440 reports e.g. 1.2.4.dev7+g1a2b3c4
+	StylePEP440 VersionStyle = "pep440"
+	// StyleMavenSnapshot reports e.g. 1.2.4-SNAPSHOT
+	StyleMavenSnapshot VersionStyle = "maven-snapshot"
+)
+
+// shortCommitLength is the abbreviated commit length used in dev versions
+const shortCommitLength = 7
+
+// pep440PreRelease matches semver pre-release identifiers that have a PEP 440
+// equivalent, such as rc.1, beta2 or alpha
+var pep440PreRelease = regexp.MustCompile(
+	`^(?i)(a|alpha|b|beta|c|rc|pre|preview)[.-]?([0-9]+)?$`)
+
+// VersionStyles returns the supported version style names
+func VersionStyles() []string {
+	return []string{string(StyleExact), string(StyleSemver),
+		string(StylePEP440), string(StyleMavenSnapshot)}
+}
+
+// ParseVersionStyle validates a version style name. An empty name selects
+// StyleExact.
+func ParseVersionStyle(name string) (VersionStyle, error) {
+	if name == "" {
+		return StyleExact, nil
+	}
+	for _, style := range VersionStyles() {
+		if name == style {
+			return VersionStyle(name), nil
+		}
+	}
+	return "", fmt.Errorf("unknown git version style %q (expected one of: %s)",
+		name, strings.Join(VersionStyles(), ", "))
+}
+
+// DevVersion formats the result's version in the given style, using the
+// distance from the tag, the commit and the dirty state in the way
+// setuptools_scm and dunamai do: the next patch release is marked as a
+// development version of it. A tag with a pre-release keeps its release
+// numbers and marks the pre-release instead.
+func (r *GitTagResult) DevVersion(style VersionStyle) (string, error) {
+	if style == StyleExact || style == "" || (r.Distance == 0 && !r.Dirty) {
+		return r.Version, nil
+	}
+
+	release, pre := splitTagVersion(r.Version)
+	short := r.Commit
+	if len(short) > shortCommitLength {
+		short = short[:shortCommitLength]
+	}
+	var local []string
+	if short != "" {
+		local = append(local, "g"+short)
+	}
+	if r.Dirty {
+		local = append(local, "dirty")
+	}
+	withLocal := func(version string) string {
+		if len(local) == 0 {
+			return version
+		}
+		return version + "+" + strings.Join(local, ".")
+	}
+
+	switch style {
+	case StyleSemver:
+		if pre != "" {
+			return withLocal(fmt.Sprintf("%s-%s.dev.%d", release, pre,
+				r.Distance)), nil
+		}
+		next, err := nextRelease(release)
+		if err != nil {
+			return "", err
+		}
+		return withLocal(fmt.Sprintf("%s-dev.%d", next, r.Distance)), nil
+
+	case StylePEP440:
+		base := ""
+		if pre != "" {
+			m := pep440PreRelease.FindStringSubmatch(pre)
+			if m == nil {
+				return "", fmt.Errorf("pre-release %q of %s has no PEP 440 "+
+					"equivalent", pre, r.Version)
+			}
+			n := 0
+			if m[2] != "" {
+				n, _ = strconv.Atoi(m[2])
+			}
+			base = fmt.Sprintf("%s%s%d", release, pep440PreLabel(m[1]), n+1)
+		} else {
+			next, err := nextRelease(release)
+			if err != nil {
+				return "", err
+			}
+			base = next
+		}
+		return withLocal(fmt.Sprintf("%s.dev%d", base, r.Distance)), nil
+
+	case StyleMavenSnapshot:
+		if pre != "" {
+			return fmt.Sprintf("%s-%s-SNAPSHOT", release, pre), nil
+		}
+		next, err := nextRelease(release)
+		if err != nil {
+			return "", err
+		}
+		return next + "-SNAPSHOT", nil
+	}
+
+	return "", fmt.Errorf("unknown git version style %q", style)
+}
+
+// splitTagVersion splits a tag version into its release numbers and
+// pre-release, dropping any build metadata.
+func splitTagVersion(version string) (string, string) {
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+	release, pre, _ := strings.Cut(version, "-")
+	return release, pre
+}
+
+// nextRelease increments the last component of a dotted release, so 1.2.3
+// becomes 1.2.4 and 2024.05 becomes 2024.06.
+func nextRelease(release string) (string, error) {
+	parts := strings.Split(release, ".")
+	last, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return "", fmt.Errorf("cannot increment release %q", release)
+	}
+	parts[len(parts)-1] = strconv.Itoa(last + 1)
+	return strings.Join(parts, "."), nil
+}
+
+// pep440PreLabel maps a pre-release label to its PEP 440 normal form
+func pep440PreLabel(label string) string {
+	switch strings.ToLower(label) {
+	case "a", "alpha":
+		return "a"
+	case "b", "beta":
+		return "b"
+	default:
+		return "rc"
+	}
+}
+
+// describeHead fills in the result's distance from tag, HEAD's commit and
+// whether tracked files have uncommitted changes. A tag that is not present
+// locally (found through ls-remote) leaves the distance at zero.
+func (g *GitVersionExtractor) describeHead(result *GitTagResult) {
+	if commit, err := g.backend.Head(); err == nil {
+		result.Commit = commit
+	}
+	if distance, err := g.backend.Distance(result.Tag); err == nil {
+		result.Distance = distance
+	}
+	if dirty, err := g.backend.Dirty(); err == nil {
+		result.Dirty = dirty
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDevVersion(t *testing.T) {
+	const sha = "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b"
+
+	tests := []struct {
+		name     string
+		version  string
+		distance int
+		dirty    bool
+		style    VersionStyle
+		want     string
+	}{
+		{"exact ignores distance", "1.2.3", 7, true, StyleExact, "1.2.3"},
+		{"on tag", "1.2.3", 0, false, StyleSemver, "1.2.3"},
+		{"semver", "1.2.3", 7, false, StyleSemver, "1.2.4-dev.7+g1a2b3c4"},
+		{"semver dirty", "1.2.3", 7, true, StyleSemver, "1.2.4-dev.7+g1a2b3c4.dirty"},
+		{"semver dirty on tag", "1.2.3", 0, true, StyleSemver, "1.2.4-dev.0+g1a2b3c4.dirty"},
+		{"semver pre-release", "1.2.3-rc.1", 2, false, StyleSemver, "1.2.3-rc.1.dev.2+g1a2b3c4"},
+		{"pep440", "1.2.3", 7, false, StylePEP440, "1.2.4.dev7+g1a2b3c4"},
+		{"pep440 dirty", "1.2.3", 7, true, StylePEP440, "1.2.4.dev7+g1a2b3c4.dirty"},
+		{"pep440 rc", "1.2.3-rc.1", 3, false, StylePEP440, "1.2.3rc2.dev3+g1a2b3c4"},
+		{"pep440 beta", "2.0.0-beta", 1, false, StylePEP440, "2.0.0b1.dev1+g1a2b3c4"},
+		{"pep440 drops build metadata", "1.2.3+build.5", 1, false, StylePEP440, "1.2.4.dev1+g1a2b3c4"},
+		{"maven snapshot", "1.2.3", 7, true, StyleMavenSnapshot, "1.2.4-SNAPSHOT"},
+		{"maven snapshot pre-release", "1.2.3-rc.1", 7, false, StyleMavenSnapshot, "1.2.3-rc.1-SNAPSHOT"},
+		{"calver", "2024.05", 4, false, StyleSemver, "2024.6-dev.4+g1a2b3c4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &GitTagResult{Version: tt.version, Distance: tt.distance,
+				Commit: sha, Dirty: tt.dirty, Success: true}
+			got, err := result.DevVersion(tt.style)
+			if err != nil {
+				t.Fatalf("DevVersion(%s) failed: %v", tt.style, err)
+			}
+			if got != tt.want {
+				t.Errorf("DevVersion(%s) = %q, want %q", tt.style, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDevVersionErrors(t *testing.T) {
+	result := &GitTagResult{Version: "1.2.3-nightly.4", Distance: 1, Commit: "abc"}
+	if _, err := result.DevVersion(StylePEP440); err == nil {
+		t.Error("expected an error for a pre-release without a PEP 440 equivalent")
+	}
+
+	if _, err := ParseVersionStyle("calver"); err == nil {
+		t.Error("expected an error for an unknown version style")
+	}
+	if style, err := ParseVersionStyle(""); err != nil || style != StyleExact {
+		t.Errorf("ParseVersionStyle(\"\") = %q, %v; want exact", style, err)
+	}
+}
+
+func TestGetLatestVersionTag_Describe(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping integration test")
+	}
+
+	tempDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		if err := runGitCommand(tempDir, args...); err != nil {
+			t.Skipf("Failed to set up git repo: %v", err)
+		}
+	}
+
+	testFile := filepath.Join(tempDir, "test.txt")
+	commit := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := runGitCommand(tempDir, "add", "test.txt"); err != nil {
+			t.Skipf("Failed to add file: %v", err)
+		}
+		if err := runGitCommand(tempDir, "commit", "-m", content); err != nil {
+			t.Skipf("Failed to commit: %v", err)
+		}
+	}
+
+	commit("tagged")
+	if err := runGitCommand(tempDir, "tag", "-a", "v1.2.3", "-m", "v1.2.3"); err != nil {
+		t.Skipf("Failed to create tag: %v", err)
+	}
+
+	extractor := New(tempDir)
+	result, err := extractor.GetLatestVersionTag()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Distance != 0 || result.Dirty {
+		t.Errorf("expected a clean tagged checkout, got %+v", result)
+	}
+
+	commit("second")
+	commit("third")
+	if err := os.WriteFile(testFile, []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Untracked files do not make the tree dirty
+	if err := os.WriteFile(filepath.Join(tempDir, "untracked.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = extractor.GetLatestVersionTag()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	head, err := exec.Command("git", "-C", tempDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Distance != 2 || !result.Dirty || result.Commit != strings.TrimSpace(string(head)) {
+		t.Errorf("unexpected describe result: %+v", result)
+	}
+
+	want := "1.2.4-dev.2+g" + result.Commit[:7] + ".dirty"
+	if got, _ := result.DevVersion(StyleSemver); got != want {
+		t.Errorf("DevVersion(semver) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timeouts bound git subprocess calls so version extraction can never hang on
+// a slow or pathological repository. Local commands are quick; the remote
+// lookup (ls-remote) contacts origin for refs only (no object download).
+const (
+	gitLocalTimeout  = 15 * time.Second
+	gitRemoteTimeout = 45 * time.Second
+)
+
+// execBackend answers repository queries by running the git binary
+type execBackend struct {
+	workingDir string
+	rev        string          // Revision standing in for HEAD; "" for HEAD
+	ctx        context.Context // Stops commands when done; nil for never
+}
+
+// SetRevision makes queries about HEAD answer for rev instead
+func (b *execBackend) SetRevision(rev string) {
+	b.rev = rev
+}
+
+// SetContext stops running commands once ctx is done
+func (b *execBackend) SetContext(ctx context.Context) {
+	b.ctx = ctx
+}
+
+// head returns the revision queries about HEAD answer for
+func (b *execBackend) head() string {
+	if b.rev == "" {
+		return "HEAD"
+	}
+	return b.rev
+}
+
+// runGit runs a git command in the working directory, bounded by a timeout
+// and the backend's context, and returns its standard output. On failure it surfaces the git arguments,
+// the captured stderr, and distinguishes timeouts, so callers (and logs) get
+// actionable diagnostics instead of a bare "exit status 128".
+func (b *execBackend) runGit(timeout time.Duration,
+	args ...string) ([]byte, error) {
+	parent := b.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = b.workingDir
+	out, err := cmd.Output()
+	if err == nil {
+		return out, nil
+	}
+	if parent.Err() != nil {
+		return out, fmt.Errorf("git %s: %w", strings.Join(args, " "), parent.Err())
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return out, fmt.Errorf("git %s timed out after %s",
+			strings.Join(args, " "), timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+			return out, fmt.Errorf("git %s: %w: %s",
+				strings.Join(args, " "), err, stderr)
+		}
+	}
+	return out, fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+}
+
+// IsRepository checks for a .git directory, then asks git
+func (b *execBackend) IsRepository() bool {
+	gitDir := filepath.Join(b.workingDir, ".git")
+	if _, err := os.Stat(gitDir); err == nil {
+		return true
+	}
+
+	_, err := b.runGit(gitLocalTimeout, "rev-parse", "--git-dir")
+	return err == nil
+}
+
+// RelativePath runs git rev-parse --show-prefix
+func (b *execBackend) RelativePath() (string, error) {
+	out, err := b.runGit(gitLocalTimeout, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSpace(string(out)), "/"), nil
+}
+
+// Head runs git rev-parse HEAD
+func (b *execBackend) Head() (string, error) {
+	out, err := b.runGit(gitLocalTimeout, "rev-parse", "--verify", "--quiet",
+		b.head()+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Describe runs git describe --tags --abbrev=0
+func (b *execBackend) Describe(match string, excludes []string) (string, error) {
+	args := []string{"describe", "--tags", "--abbrev=0"}
+	if match != "" {
+		args = append(args, fmt.Sprintf("--match=%s", match))
+	}
+	for _, exclude := range excludes {
+		args = append(args, fmt.Sprintf("--exclude=%s", exclude))
+	}
+	if b.rev != "" {
+		args = append(args, b.rev)
+	}
+
+	out, err := b.runGit(gitLocalTimeout, args...)
+	if err != nil {
+		return "", err
+	}
+	tag := strings.TrimSpace(string(out))
+	if tag == "" {
+		return "", fmt.Errorf("empty tag output")
+	}
+	return tag, nil
+}
+
+// PointsAt runs git tag --list --points-at
+func (b *execBackend) PointsAt(tag, match string) ([]string, error) {
+	args := []string{"tag", "--list", "--points-at", tag + "^{commit}"}
+	if match != "" {
+		args = append(args, match)
+	}
+	out, err := b.runGit(gitLocalTimeout, args...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// ListTags runs git tag --list --sort=-version:refname
+func (b *execBackend) ListTags(merged bool) ([]string, error) {
+	args := []string{"tag", "--list", "--sort=-version:refname"}
+	if merged {
+		args = append(args, "--merged", b.head())
+	}
+	out, err := b.runGit(gitLocalTimeout, args...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// RemoteTags lists the origin's tags via `git ls-remote` (ref names only,
+// no object download), which is dramatically cheaper than `git fetch
+// --tags` on large repositories, where fetching tag objects onto a shallow
+// clone can take many minutes.
+func (b *execBackend) RemoteTags() ([]string, error) {
+	out, err := b.runGit(gitRemoteTimeout, "ls-remote", "--tags",
+		"--sort=-version:refname", "origin")
+	if err != nil {
+		return nil, err
+	}
+
+	const marker = "refs/tags/"
+	seen := make(map[string]bool)
+	var tags []string
+	for _, line := range strings.Split(string(out), "\n") {
+		i := strings.Index(line, marker)
+		if i < 0 {
+			continue
+		}
+		// Strip the peeled-tag suffix ls-remote emits for annotated tags.
+		tag := strings.TrimSuffix(strings.TrimSpace(line[i+len(marker):]), "^{}")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// Distance runs git rev-list --count tag..HEAD
+func (b *execBackend) Distance(tag string) (int, error) {
+	out, err := b.runGit(gitLocalTimeout, "rev-list", "--count", tag+".."+b.head())
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// Dirty runs git status --porcelain, ignoring untracked files
+func (b *execBackend) Dirty() (bool, error) {
+	if b.rev != "" {
+		return false, nil
+	}
+	out, err := b.runGit(gitLocalTimeout, "status", "--porcelain",
+		"--untracked-files=no")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
+// Tree lists the revision's files with git ls-tree; their content is read
+// with git cat-file when a file is opened
+func (b *execBackend) Tree() (fs.FS, error) {
+	out, err := b.runGit(gitLocalTimeout, "ls-tree", "-r", "-z", "-l",
+		"--full-tree", b.head()+"^{commit}")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*treeBlob)
+	for _, record := range strings.Split(string(out), "\x00") {
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		meta, name, ok := strings.Cut(record, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		mode, err := strconv.ParseUint(fields[0], 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("git ls-tree: invalid mode in %q", record)
+		}
+		fileMode, ok := blobMode(uint32(mode))
+		if !ok {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("git ls-tree: invalid size in %q", record)
+		}
+		files[name] = &treeBlob{id: fields[2], size: size, mode: fileMode}
+	}
+	return newTreeFS(files, func(id string) ([]byte, error) {
+		return b.runGit(gitLocalTimeout, "cat-file", "blob", id)
+	}), nil
+}
+
+// FetchTags runs git fetch --tags, bounded by the remote timeout
+func (b *execBackend) FetchTags() error {
+	_, err := b.runGit(gitRemoteTimeout, "fetch", "--tags", "--quiet")
+	return err
+}
@@ -5,16 +5,11 @@ package git
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"log/slog"
 	"regexp"
 	"strings"
 	"sync"
-	"time"
 )
 
 // Version tag regex patterns for Git tag validation
@@ -28,9 +23,6 @@ const (
 	// Date-based version pattern (YYYY.MM.DD format)
 	dateVersionPattern = `^[0-9]{4}\.[0-9]{2}(?:\.[0-9]{2})?$`
 
-	// Maven SNAPSHOT version pattern
-	snapshotVersionPattern = `^[0-9]+\.[0-9]+(?:\.[0-9]+)?-SNAPSHOT$`
-
 	// Beta version pattern
 	betaVersionPattern = `^[0-9]+\.[0-9]+(?:\.[0-9]+)?-beta\.[0-9]+$`
 
@@ -59,7 +51,6 @@ func compileVersionTagPatterns() ([]*regexp.Regexp, error) {
 		semanticVersionPattern,
 		simpleVersionPattern,
 		dateVersionPattern,
-		snapshotVersionPattern,
 		betaVersionPattern,
 		alphaVersionPattern,
 		rcVersionPattern,
@@ -69,7 +60,7 @@ func compileVersionTagPatterns() ([]*regexp.Regexp, error) {
 	for _, pattern := range patterns {
 		re, err := regexp.Compile(pattern)
 		if err != nil {
-			log.Printf("warning: failed to compile version regex pattern '%s': %v", pattern, err)
+			slog.Warn("failed to compile version regex pattern", "pattern", pattern, "error", err)
 			continue
 		}
 		compiled = append(compiled, re)
@@ -79,7 +70,7 @@ func compileVersionTagPatterns() ([]*regexp.Regexp, error) {
 		return compiled, nil
 	}
 
-	log.Printf("error: no version regex patterns compiled successfully, using fallback pattern")
+	slog.Error("no version regex patterns compiled successfully, using fallback pattern")
 	re, err := regexp.Compile(fallbackVersionPattern)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -96,67 +87,36 @@ type GitTagResult struct {
 	Tag       string `json:"tag"`
 	Success   bool   `json:"success"`
 	IsGitRepo bool   `json:"is_git_repo"`
+	Distance  int    `json:"distance"`           // Commits from the tag to HEAD
+	Commit    string `json:"commit,omitempty"`   // HEAD commit SHA
+	Dirty     bool   `json:"dirty"`              // Tracked files have uncommitted changes
+	Strategy  string `json:"strategy,omitempty"` // How the tag was found, e.g. "describe --match v*"
 }
 
 // GitVersionExtractor handles Git-based version extraction
 type GitVersionExtractor struct {
-	workingDir string
+	workingDir        string
+	tagPrefix         string
+	tagPolicy         TagPolicy
+	tagOrdering       TagOrdering
+	excludePrerelease bool
+	revision          string          // Commit tags are looked up from; "" for HEAD
+	ctx               context.Context // Stops git commands when done; nil for never
+	backend           Backend
 }
 
-// New creates a new GitVersionExtractor
+// New creates a new GitVersionExtractor, using the exec backend when git is
+// installed and the native backend otherwise
 func New(workingDir string) *GitVersionExtractor {
 	return &GitVersionExtractor{
 		workingDir: workingDir,
+		backend:    NewBackend(BackendAuto, workingDir),
 	}
 }
 
-// Timeouts bound git subprocess calls so version extraction can never hang on
-// a slow or pathological repository. Local commands are quick; the remote
-// lookup (ls-remote) contacts origin for refs only (no object download).
-const (
-	gitLocalTimeout  = 15 * time.Second
-	gitRemoteTimeout = 45 * time.Second
-)
-
-// runGit runs a git command in the working directory, bounded by a timeout,
-// and returns its standard output. On failure it surfaces the git arguments,
-// the captured stderr, and distinguishes timeouts, so callers (and logs) get
-// actionable diagnostics instead of a bare "exit status 128".
-func (g *GitVersionExtractor) runGit(timeout time.Duration,
-	args ...string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	cmd := exec.CommandContext(ctx, "git", args...)
-	cmd.Dir = g.workingDir
-	out, err := cmd.Output()
-	if err == nil {
-		return out, nil
-	}
-	if ctx.Err() == context.DeadlineExceeded {
-		return out, fmt.Errorf("git %s timed out after %s",
-			strings.Join(args, " "), timeout)
-	}
-	var exitErr *exec.ExitError
-	if errors.As(err, &exitErr) {
-		if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
-			return out, fmt.Errorf("git %s: %w: %s",
-				strings.Join(args, " "), err, stderr)
-		}
-	}
-	return out, fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
-}
-
 // IsGitRepository checks if the working directory is a Git repository
 func (g *GitVersionExtractor) IsGitRepository() bool {
-	// Check if .git directory exists
-	gitDir := filepath.Join(g.workingDir, ".git")
-	if _, err := os.Stat(gitDir); err == nil {
-		return true
-	}
-
-	// Alternative: try git rev-parse command
-	_, err := g.runGit(gitLocalTimeout, "rev-parse", "--git-dir")
-	return err == nil
+	return g.backend.IsRepository()
 }
 
 // GetLatestVersionTag extracts the latest version tag from Git
@@ -170,7 +130,7 @@ func (g *GitVersionExtractor) GetLatestVersionTag() (*GitTagResult, error) {
 	}
 
 	// Try different strategies to get version tags
-	version, tag, err := g.tryGetLatestTag()
+	version, tag, strategy, err := g.tryGetLatestTag()
 	if err != nil {
 		return result, fmt.Errorf("failed to get git tags: %w", err)
 	}
@@ -181,36 +141,62 @@ func (g *GitVersionExtractor) GetLatestVersionTag() (*GitTagResult, error) {
 
 	result.Version = version
 	result.Tag = tag
+	result.Strategy = strategy
 	result.Success = true
+	g.describeHead(result)
 
 	return result, nil
 }
 
-// tryGetLatestTag attempts multiple strategies to get the latest version tag
-func (g *GitVersionExtractor) tryGetLatestTag() (string, string, error) {
-	// Strategy 1: git describe --tags --abbrev=0 --match="v*" (semantic versioning)
-	if version, tag, err := g.getTagWithDescribe("v*"); err == nil && version != "" {
-		return version, tag, nil
+// Tag lookup strategies, as reported in GitTagResult.Strategy
+const (
+	strategyMerged = "tag --merged"
+	strategyList   = "tag --list"
+	strategyRemote = "ls-remote"
+)
+
+// describeStrategy names the describe strategy for a match pattern
+func describeStrategy(matchPattern string) string {
+	if matchPattern == "" {
+		return "describe"
 	}
+	return "describe --match " + matchPattern
+}
 
-	// Strategy 2: git describe --tags --abbrev=0 --match="*.*.*" (version patterns)
-	if version, tag, err := g.getTagWithDescribe("*.*.*"); err == nil && version != "" {
-		return version, tag, nil
+// tryGetLatestTag attempts multiple strategies to get the latest version tag
+// under the tag selection policy, returning the version, the tag and the
+// strategy that found it
+func (g *GitVersionExtractor) tryGetLatestTag() (string, string, string, error) {
+	switch g.tagPolicy {
+	case PolicyHighestReachable:
+		// Tags on a shallow clone's remote cannot be known to be reachable,
+		// so there is no ls-remote fallback
+		version, tag, err := g.getTagWithList(true)
+		return version, tag, strategyMerged, err
+	case PolicyHighestAny:
+		if version, tag, err := g.getTagWithList(false); err == nil && version != "" {
+			return version, tag, strategyList, nil
+		}
+		version, tag, err := g.getTagFromRemote()
+		return version, tag, strategyRemote, err
 	}
 
-	// Strategy 3: git describe --tags --abbrev=0 --match="release-*" (release prefixes)
-	if version, tag, err := g.getTagWithDescribe("release-*"); err == nil && version != "" {
-		return version, tag, nil
+	if g.tagPrefix != "" {
+		return g.tryGetLatestPrefixedTag()
 	}
 
-	// Strategy 4: git describe --tags --abbrev=0 (any tag)
-	if version, tag, err := g.getTagWithDescribe(""); err == nil && version != "" {
-		return version, tag, nil
+	// Strategies 1-4: git describe --tags --abbrev=0, for tags matching
+	// "v*" (semantic versioning), "*.*.*" (version patterns), "release-*"
+	// (release prefixes), then any tag
+	for _, pattern := range []string{"v*", "*.*.*", "release-*", ""} {
+		if version, tag, err := g.getNearestTag(pattern); err == nil && version != "" {
+			return version, tag, describeStrategy(pattern), nil
+		}
 	}
 
-	// Strategy 5: git tag --list --sort=-version:refname
-	if version, tag, err := g.getTagWithList(); err == nil && version != "" {
-		return version, tag, nil
+	// Strategy 5: the highest tag by version precedence
+	if version, tag, err := g.getTagWithList(false); err == nil && version != "" {
+		return version, tag, strategyList, nil
 	}
 
 	// Strategy 6: no usable local tags (e.g. a shallow clone) — list the
@@ -218,102 +204,83 @@ func (g *GitVersionExtractor) tryGetLatestTag() (string, string, error) {
 	// downloading objects. This avoids the very slow `git fetch --tags` on
 	// large repositories.
 	if version, tag, err := g.getTagFromRemote(); err == nil && version != "" {
-		return version, tag, nil
+		return version, tag, strategyRemote, nil
 	}
 
-	return "", "", fmt.Errorf("no tags found with any strategy")
+	return "", "", "", fmt.Errorf("no tags found with any strategy")
 }
 
-// getTagWithDescribe uses git describe to get the latest tag
-func (g *GitVersionExtractor) getTagWithDescribe(matchPattern string) (string, string, error) {
-	args := []string{"describe", "--tags", "--abbrev=0"}
-	if matchPattern != "" {
-		args = append(args, fmt.Sprintf("--match=%s", matchPattern))
+// tryGetLatestPrefixedTag finds the nearest tag carrying the configured tag
+// prefix, using the same describe, list and ls-remote strategies restricted
+// to that prefix. Describe results are validated, because the prefix glob
+// also matches other components sharing it (web-* matches web-ui-v1.0).
+func (g *GitVersionExtractor) tryGetLatestPrefixedTag() (string, string, string, error) {
+	if version, tag, err := g.getNearestTag(g.tagPrefix + "*"); err == nil && version != "" {
+		return version, tag, describeStrategy(g.tagPrefix + "*"), nil
 	}
 
-	output, err := g.runGit(gitLocalTimeout, args...)
-	if err != nil {
-		return "", "", err
+	if version, tag, err := g.getTagWithList(false); err == nil && version != "" {
+		return version, tag, strategyList, nil
+	}
+
+	if version, tag, err := g.getTagFromRemote(); err == nil && version != "" {
+		return version, tag, strategyRemote, nil
 	}
 
-	tag := strings.TrimSpace(string(output))
-	if tag == "" {
-		return "", "", fmt.Errorf("empty tag output")
+	return "", "", "", fmt.Errorf("no tags found with prefix %q", g.tagPrefix)
+}
+
+// getTagWithDescribe uses git describe to get the nearest tag, skipping
+// tags matching any of excludes
+func (g *GitVersionExtractor) getTagWithDescribe(matchPattern string,
+	excludes ...string) (string, string, error) {
+	tag, err := g.backend.Describe(matchPattern, excludes)
+	if err != nil {
+		return "", "", err
 	}
 
 	version := g.cleanVersionFromTag(tag)
 	return version, tag, nil
 }
 
-// getTagWithList uses git tag --list with sorting to get the latest tag
-func (g *GitVersionExtractor) getTagWithList() (string, string, error) {
-	output, err := g.runGit(gitLocalTimeout, "tag", "--list",
-		"--sort=-version:refname")
+// getTagWithList lists the tags, only those reachable from HEAD when merged
+// is set, and returns the highest valid version tag. Listing in version sort
+// keeps the choice among tags of equal precedence stable.
+func (g *GitVersionExtractor) getTagWithList(merged bool) (string, string, error) {
+	tags, err := g.backend.ListTags(merged)
 	if err != nil {
 		return "", "", err
 	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 0 || lines[0] == "" {
+	if len(tags) == 0 {
 		return "", "", fmt.Errorf("no tags found")
 	}
 
-	// Find the first tag that looks like a version
-	for _, tag := range lines {
-		tag = strings.TrimSpace(tag)
-		if tag == "" {
-			continue
-		}
-
-		version := g.cleanVersionFromTag(tag)
-		valid, err := g.isValidVersionTag(version)
-		if err != nil {
-			return "", "", err
-		}
-		if valid {
-			return version, tag, nil
-		}
+	version, tag, err := g.selectTag(tags)
+	if err != nil {
+		return "", "", err
 	}
-
-	return "", "", fmt.Errorf("no valid version tags found")
+	if version == "" {
+		return "", "", fmt.Errorf("no valid version tags found")
+	}
+	return version, tag, nil
 }
 
-// getTagFromRemote lists the origin's tags via `git ls-remote` (ref names
-// only, no object download) and returns the highest-sorted valid version tag.
-// This is dramatically cheaper than `git fetch --tags` on large repositories,
-// where fetching tag objects onto a shallow clone can take many minutes.
+// getTagFromRemote lists the origin's tags, without downloading objects,
+// and returns the highest valid version tag
 func (g *GitVersionExtractor) getTagFromRemote() (string, string, error) {
-	output, err := g.runGit(gitRemoteTimeout, "ls-remote", "--tags",
-		"--sort=-version:refname", "origin")
+	tags, err := g.backend.RemoteTags()
 	if err != nil {
 		return "", "", err
 	}
 
-	const marker = "refs/tags/"
-	seen := make(map[string]bool)
-	for _, line := range strings.Split(string(output), "\n") {
-		i := strings.Index(line, marker)
-		if i < 0 {
-			continue
-		}
-		// Strip the peeled-tag suffix ls-remote emits for annotated tags.
-		tag := strings.TrimSuffix(strings.TrimSpace(line[i+len(marker):]), "^{}")
-		if tag == "" || seen[tag] {
-			continue
-		}
-		seen[tag] = true
-
-		version := g.cleanVersionFromTag(tag)
-		valid, err := g.isValidVersionTag(version)
-		if err != nil {
-			return "", "", err
-		}
-		if valid {
-			return version, tag, nil
-		}
+	version, tag, err := g.selectTag(tags)
+	if err != nil {
+		return "", "", err
 	}
-
-	return "", "", fmt.Errorf("no valid version tags found on remote")
+	if version == "" {
+		return "", "", fmt.Errorf("no valid version tags found on remote")
+	}
+	return version, tag, nil
 }
 
 // cleanVersionFromTag extracts version from a git tag
@@ -354,7 +321,7 @@ func (g *GitVersionExtractor) isValidVersionTag(version string) (bool, error) {
 		}
 	}
 
-	return false, nil
+	return g.isMavenVersionTag(version), nil
 }
 
 // FetchTags attempts to fetch remote tags (useful in CI environments)
@@ -366,7 +333,7 @@ func (g *GitVersionExtractor) FetchTags() error {
 	// Try to fetch tags quietly, bounded by a timeout. The default extraction
 	// path now prefers getTagFromRemote (ls-remote), which is far cheaper than
 	// fetching tag objects on large repositories.
-	_, err := g.runGit(gitRemoteTimeout, "fetch", "--tags", "--quiet")
+	err := g.backend.FetchTags()
 
 	// Don't treat fetch failures as fatal - repository might be offline
 	// or user might not have network access
@@ -204,6 +204,10 @@ func TestGetLatestVersionTag_WithGitRepo(t *testing.T) {
 	if !found {
 		t.Errorf("Expected version to be one of %v, got %s", expectedVersions, result.Version)
 	}
+
+	if result.Strategy != "describe --match v*" {
+		t.Errorf("Expected the tag from describe --match v*, got strategy %q", result.Strategy)
+	}
 }
 
 func TestGetLatestVersionTag_RemoteFallback(t *testing.T) {
@@ -261,6 +265,155 @@ func TestGetLatestVersionTag_RemoteFallback(t *testing.T) {
 	}
 }
 
+func TestGetLatestVersionTag_MavenOrder(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping integration test")
+	}
+
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "f.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setup := [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+		{"add", "f.txt"},
+		{"commit", "-m", "init"},
+		{"tag", "v1.0-SNAPSHOT"},
+		{"tag", "v1.0-RC1"},
+		{"tag", "v1.0.Final"},
+		{"tag", "v0.9"},
+	}
+	for _, args := range setup {
+		if err := runGitCommand(tempDir, args...); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+
+	// git's version sort ranks 1.0-SNAPSHOT highest; Maven order ranks the
+	// final release above its snapshot and release candidate
+	extractor := New(tempDir)
+	extractor.SetTagOrdering(OrderMaven)
+	result, err := extractor.GetLatestVersionTag()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Version != "1.0.Final" || result.Tag != "v1.0.Final" {
+		t.Errorf("Expected tag v1.0.Final, got %q (%q)", result.Tag, result.Version)
+	}
+
+	// Maven-style tags are only valid version tags in Maven order
+	valid, err := New(tempDir).isValidVersionTag("1.0.Final")
+	if err != nil || valid {
+		t.Errorf("Expected 1.0.Final to be invalid in semver order, got %t, %v", valid, err)
+	}
+}
+
+func TestSelectTag(t *testing.T) {
+	tests := []struct {
+		name              string
+		tags              []string
+		excludePrerelease bool
+		expected          string
+	}{
+		// git's version sort lists the release candidate first
+		{"release above rc", []string{"v1.0.0-rc.1", "v1.0.0"}, false, "v1.0.0"},
+		{"numeric precedence", []string{"v1.9.0", "v1.10.0", "v1.2.0"}, false, "v1.10.0"},
+		{"prerelease kept", []string{"v2.0.0-rc.1", "v1.0.0"}, false, "v2.0.0-rc.1"},
+		{"prerelease excluded", []string{"v2.0.0-rc.1", "v1.0.0"}, true, "v1.0.0"},
+		{"invalid skipped", []string{"latest", "v0.1.0"}, false, "v0.1.0"},
+		{"nothing wanted", []string{"v1.0.0-beta.1"}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor := New("/tmp")
+			extractor.SetExcludePrerelease(tt.excludePrerelease)
+			_, tag, err := extractor.selectTag(tt.tags)
+			if err != nil {
+				t.Fatalf("selectTag returned an error: %v", err)
+			}
+			if tag != tt.expected {
+				t.Errorf("selectTag(%v) = %q, expected %q", tt.tags, tag, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetLatestVersionTag_Policy(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping integration test")
+	}
+
+	tempDir := t.TempDir()
+	testFile := filepath.Join(tempDir, "f.txt")
+	run := func(args ...string) {
+		t.Helper()
+		if err := runGitCommand(tempDir, args...); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+	commit := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", "f.txt")
+		run("commit", "-m", content)
+	}
+
+	// 1.0.0 <- 1.1.0, 1.1.0-rc.1 <- 2.0.0-rc.1 (HEAD), with 3.0.0 on a
+	// branch from 1.0.0 that HEAD cannot reach
+	run("init")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	commit("one")
+	run("tag", "v1.0.0")
+	commit("two")
+	run("tag", "v1.1.0-rc.1")
+	run("tag", "v1.1.0")
+	commit("three")
+	run("tag", "v2.0.0-rc.1")
+	run("checkout", "-q", "-b", "side", "v1.0.0")
+	commit("side")
+	run("tag", "v3.0.0")
+	run("checkout", "-q", "-")
+
+	tests := []struct {
+		policy            TagPolicy
+		excludePrerelease bool
+		expected          string
+	}{
+		{PolicyNearest, false, "2.0.0-rc.1"},
+		{PolicyNearest, true, "1.1.0"},
+		{PolicyHighestReachable, false, "2.0.0-rc.1"},
+		{PolicyHighestReachable, true, "1.1.0"},
+		{PolicyHighestAny, false, "3.0.0"},
+	}
+
+	for _, tt := range tests {
+		extractor := New(tempDir)
+		extractor.SetTagPolicy(tt.policy)
+		extractor.SetExcludePrerelease(tt.excludePrerelease)
+		result, err := extractor.GetLatestVersionTag()
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", tt.policy, err)
+		}
+		if result.Version != tt.expected {
+			t.Errorf("%s (exclude prerelease %t): expected %s, got %s",
+				tt.policy, tt.excludePrerelease, tt.expected, result.Version)
+		}
+	}
+
+	if _, err := ParseTagPolicy("newest"); err == nil {
+		t.Error("expected an error for an unknown tag policy")
+	}
+	if policy, err := ParseTagPolicy(""); err != nil || policy != PolicyNearest {
+		t.Errorf("ParseTagPolicy(\"\") = %q, %v; want nearest", policy, err)
+	}
+}
+
 func TestFetchTags(t *testing.T) {
 	// Test with non-git directory
 	tempDir, err := os.MkdirTemp("", "git-test-*")
@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"bytes"
+	"crypto/sha1" //nolint:gosec // git object names are SHA-1
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// File modes recorded in trees and the index
+const (
+	modeTree       = 0o040000
+	modeFile       = 0o100644
+	modeExecutable = 0o100755
+	modeSymlink    = 0o120000
+	modeGitlink    = 0o160000
+)
+
+// Index entry flags
+const (
+	indexFlagExtended     = 0x4000
+	indexFlagStageMask    = 0x3000
+	indexFlagSkipWorktree = 0x4000 // In the extended flags
+	indexFlagIntentToAdd  = 0x2000 // In the extended flags
+)
+
+// indexEntry is a file recorded in the index
+type indexEntry struct {
+	path         string
+	id           string
+	mode         uint32
+	size         uint32
+	mtimeSec     uint32
+	mtimeNsec    uint32
+	stage        int
+	skipWorktree bool
+	intentToAdd  bool
+}
+
+// readIndex parses a version 2, 3 or 4 index file
+func readIndex(path string) ([]indexEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	malformed := fmt.Errorf("malformed index %s", path)
+	if len(data) < 12 || string(data[:4]) != "DIRC" {
+		return nil, malformed
+	}
+	version := binary.BigEndian.Uint32(data[4:])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("unsupported index version %d", version)
+	}
+	count := int(binary.BigEndian.Uint32(data[8:]))
+
+	// Each entry: ctime, mtime, dev, ino, mode, uid, gid and size, the ID,
+	// then flags, the extended flags when marked, and the path
+	const fixedSize = 40 + hashLen + 2
+	entries := make([]indexEntry, 0, count)
+	at := 12
+	previous := ""
+	for i := 0; i < count; i++ {
+		if len(data) < at+fixedSize {
+			return nil, malformed
+		}
+		e := data[at:]
+		entry := indexEntry{
+			mtimeSec:  binary.BigEndian.Uint32(e[8:]),
+			mtimeNsec: binary.BigEndian.Uint32(e[12:]),
+			mode:      binary.BigEndian.Uint32(e[24:]),
+			size:      binary.BigEndian.Uint32(e[36:]),
+			id:        hex.EncodeToString(e[40 : 40+hashLen]),
+		}
+		flags := binary.BigEndian.Uint16(e[40+hashLen:])
+		entry.stage = int(flags&indexFlagStageMask) >> 12
+		pathAt := at + fixedSize
+		if flags&indexFlagExtended != 0 {
+			if version < 3 || len(data) < pathAt+2 {
+				return nil, malformed
+			}
+			extended := binary.BigEndian.Uint16(data[pathAt:])
+			entry.skipWorktree = extended&indexFlagSkipWorktree != 0
+			entry.intentToAdd = extended&indexFlagIntentToAdd != 0
+			pathAt += 2
+		}
+
+		if version == 4 {
+			// The path is a count of bytes to drop from the end of the
+			// previous path, then a NUL-terminated suffix
+			r := bytes.NewReader(data[pathAt:])
+			strip, err := readOffsetVarint(r)
+			if err != nil || strip > uint64(len(previous)) {
+				return nil, malformed
+			}
+			suffixAt := len(data) - r.Len()
+			end := bytes.IndexByte(data[suffixAt:], 0)
+			if end < 0 {
+				return nil, malformed
+			}
+			entry.path = previous[:len(previous)-int(strip)] +
+				string(data[suffixAt:suffixAt+end])
+			at = suffixAt + end + 1
+		} else {
+			end := bytes.IndexByte(data[pathAt:], 0)
+			if end < 0 {
+				return nil, malformed
+			}
+			entry.path = string(data[pathAt : pathAt+end])
+			// Entries are NUL-padded to a multiple of eight bytes
+			at += (pathAt - at + end + 8) &^ 7
+		}
+		previous = entry.path
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// flattenTree lists the files beneath a tree by path
+func (r *repository) flattenTree(id, prefix string, files map[string]treeEntry) error {
+	kind, data, err := r.objects.read(id)
+	if err != nil {
+		return err
+	}
+	if kind != objTree {
+		return fmt.Errorf("object %s is not a tree", id)
+	}
+	entries, err := parseTree(data)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := prefix + entry.name
+		if entry.mode == modeTree {
+			if err := r.flattenTree(entry.id, path+"/", files); err != nil {
+				return err
+			}
+			continue
+		}
+		files[path] = entry
+	}
+	return nil
+}
+
+// dirty reports whether the index differs from HEAD's tree, or tracked
+// files in the work tree differ from the index, as git status does when
+// untracked files are ignored. Files whose size and modification time match
+// the index are taken as unchanged without being read; file mode changes
+// are not reported.
+func (r *repository) dirty() (bool, error) {
+	if r.workTree == "" {
+		return false, nil
+	}
+
+	head := make(map[string]treeEntry)
+	if id, err := r.resolveCommit("HEAD"); err == nil {
+		c, err := r.commit(id)
+		if err != nil {
+			return false, err
+		}
+		if err := r.flattenTree(c.tree, "", head); err != nil {
+			return false, err
+		}
+	}
+
+	entries, err := readIndex(filepath.Join(r.gitDir, "index"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return len(head) > 0, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		if entry.stage != 0 || entry.intentToAdd {
+			return true, nil
+		}
+		if entry.mode == modeTree {
+			// A sparse index directory, which is not checked out
+			continue
+		}
+		committed, ok := head[entry.path]
+		if !ok || committed.id != entry.id {
+			return true, nil
+		}
+		delete(head, entry.path)
+
+		if entry.skipWorktree || entry.mode == modeGitlink {
+			continue
+		}
+		changed, err := r.worktreeChanged(entry)
+		if err != nil || changed {
+			return true, err
+		}
+	}
+	for _, entry := range entries {
+		if entry.mode == modeTree {
+			prefix := entry.path
+			for path := range head {
+				if len(path) > len(prefix) && path[:len(prefix)] == prefix {
+					delete(head, path)
+				}
+			}
+		}
+	}
+	return len(head) > 0, nil
+}
+
+// worktreeChanged reports whether a tracked file differs from its index
+// entry
+func (r *repository) worktreeChanged(entry indexEntry) (bool, error) {
+	path := filepath.Join(r.workTree, filepath.FromSlash(entry.path))
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var content []byte
+	if entry.mode == modeSymlink {
+		if info.Mode()&fs.ModeSymlink == 0 {
+			return true, nil
+		}
+		target, err := os.Readlink(path)
+		if err != nil {
+			return false, err
+		}
+		content = []byte(filepath.ToSlash(target))
+	} else {
+		if !info.Mode().IsRegular() {
+			return true, nil
+		}
+		mtime := info.ModTime()
+		if uint32(info.Size()) == entry.size &&
+			uint32(mtime.Unix()) == entry.mtimeSec &&
+			uint32(mtime.Nanosecond()) == entry.mtimeNsec {
+			return false, nil
+		}
+		if content, err = os.ReadFile(path); err != nil {
+			return false, err
+		}
+	}
+	return blobID(content) != entry.id, nil
+}
+
+// blobID computes the object ID of a blob with the given content
+func blobID(content []byte) string {
+	h := sha1.New() //nolint:gosec // git object names are SHA-1
+	h.Write([]byte("blob " + strconv.Itoa(len(content)) + "\x00"))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,984 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxDescribeCandidates is how many tagged commits Describe weighs, as for
+// git describe's --candidates default
+const maxDescribeCandidates = 10
+
+// errNativeRemote reports an operation the native backend cannot perform
+var errNativeRemote = errors.New("the native git backend cannot contact remotes")
+
+// nativeBackend answers repository queries by reading the .git directory
+// directly: loose and packed refs, loose and packed objects, and the index.
+// Linked worktrees and .git files naming the git directory, as submodules
+// use, are followed. Repositories using reftable or SHA-256 object names
+// are not supported.
+type nativeBackend struct {
+	workingDir string
+	rev        string          // Revision standing in for HEAD; "" for HEAD
+	ctx        context.Context // Stops queries when done; nil for never
+
+	once sync.Once
+	repo *repository
+	err  error
+}
+
+// repository is an opened repository
+type repository struct {
+	gitDir    string // Per-worktree directory holding HEAD and the index
+	commonDir string // Directory holding refs, packed-refs and objects
+	workTree  string // Top of the work tree, "" for a bare repository
+	objects   *objectStore
+	shallow   map[string]bool
+
+	commits map[string]*commitInfo
+	tags    []tagRef
+}
+
+// tagRef is a tag and the commit it peels to, "" when it names no commit
+type tagRef struct {
+	name      string
+	commit    string
+	annotated bool
+}
+
+// newNativeBackend returns a native backend for a working directory. The
+// repository is opened on first use.
+func newNativeBackend(workingDir string) *nativeBackend {
+	return &nativeBackend{workingDir: workingDir}
+}
+
+// SetRevision makes queries about HEAD answer for rev instead
+func (b *nativeBackend) SetRevision(rev string) {
+	b.rev = rev
+}
+
+// SetContext makes queries fail once ctx is done. Each query is checked as
+// it starts; one in progress reads the .git directory to the end.
+func (b *nativeBackend) SetContext(ctx context.Context) {
+	b.ctx = ctx
+}
+
+// head returns the revision queries about HEAD answer for
+func (b *nativeBackend) head() string {
+	if b.rev == "" {
+		return "HEAD"
+	}
+	return b.rev
+}
+
+// open discovers and opens the repository containing the working directory,
+// or returns the context's error once it is done
+func (b *nativeBackend) open() (*repository, error) {
+	if b.ctx != nil && b.ctx.Err() != nil {
+		return nil, b.ctx.Err()
+	}
+	b.once.Do(func() {
+		b.repo, b.err = openRepository(b.workingDir)
+	})
+	return b.repo, b.err
+}
+
+// openRepository finds the repository containing dir by looking for .git in
+// dir and its parents, or dir being a bare repository
+func openRepository(dir string) (*repository, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	for current := abs; ; {
+		dotGit := filepath.Join(current, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			gitDir := dotGit
+			if !info.IsDir() {
+				if gitDir, err = readGitFile(dotGit); err != nil {
+					return nil, err
+				}
+			}
+			return newRepository(gitDir, current)
+		}
+		if isGitDir(current) {
+			return newRepository(current, "")
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil, fmt.Errorf("not a git repository: %s", dir)
+		}
+		current = parent
+	}
+}
+
+// readGitFile reads the "gitdir: <path>" line of a .git file, which linked
+// worktrees and submodules use in place of a .git directory
+func readGitFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", fmt.Errorf("invalid .git file %s", path)
+	}
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return filepath.Clean(target), nil
+}
+
+// isGitDir reports whether dir looks like a git directory
+func isGitDir(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// newRepository opens a git directory. A linked worktree's git directory
+// names the main one, which holds the shared refs and objects, in its
+// commondir file.
+func newRepository(gitDir, workTree string) (*repository, error) {
+	if !isGitDir(gitDir) {
+		if _, err := os.Stat(filepath.Join(gitDir, "HEAD")); err != nil {
+			return nil, fmt.Errorf("not a git directory: %s", gitDir)
+		}
+	}
+
+	commonDir := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+		commonDir = filepath.Clean(commonDir)
+	}
+
+	if _, err := os.Stat(filepath.Join(commonDir, "reftable")); err == nil {
+		return nil, fmt.Errorf("reftable repositories are not supported by the native git backend")
+	}
+	if format := configValue(filepath.Join(commonDir, "config"),
+		"extensions", "objectformat"); format != "" && format != "sha1" {
+		return nil, fmt.Errorf("%s object names are not supported by the native git backend", format)
+	}
+
+	repo := &repository{
+		gitDir:    gitDir,
+		commonDir: commonDir,
+		workTree:  workTree,
+		objects:   newObjectStore(filepath.Join(commonDir, "objects")),
+		shallow:   make(map[string]bool),
+		commits:   make(map[string]*commitInfo),
+	}
+	if data, err := os.ReadFile(filepath.Join(commonDir, "shallow")); err == nil {
+		for _, id := range strings.Fields(string(data)) {
+			repo.shallow[id] = true
+		}
+	}
+	return repo, nil
+}
+
+// configValue returns a value from a git config file, or "" when it is not
+// set. Only the simple "[section]" and "key = value" forms are read.
+func configValue(path, section, key string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	inSection := false
+	value := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			name := strings.Trim(line, "[] \t")
+			inSection = strings.EqualFold(name, section)
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		k, v, _ := strings.Cut(line, "=")
+		if strings.EqualFold(strings.TrimSpace(k), key) {
+			value = strings.ToLower(strings.TrimSpace(v))
+		}
+	}
+	return value
+}
+
+// resolveRef returns the object ID a ref names, following symbolic refs.
+// HEAD is read from the worktree's git directory, other refs from the
+// common directory, loose refs taking precedence over packed ones.
+func (r *repository) resolveRef(name string) (string, error) {
+	for depth := 0; depth < 10; depth++ {
+		dir := r.commonDir
+		if name == "HEAD" {
+			dir = r.gitDir
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+			packed, err := r.packedRefs()
+			if err != nil {
+				return "", err
+			}
+			for _, ref := range packed {
+				if ref.name == name {
+					return ref.id, nil
+				}
+			}
+			return "", fmt.Errorf("unknown ref %s", name)
+		}
+
+		content := strings.TrimSpace(string(data))
+		target, symbolic := strings.CutPrefix(content, "ref:")
+		if !symbolic {
+			return content, nil
+		}
+		name = strings.TrimSpace(target)
+	}
+	return "", fmt.Errorf("too many levels of symbolic refs")
+}
+
+// packedRef is an entry of packed-refs, with the commit an annotated tag
+// peels to when the file records it
+type packedRef struct {
+	name, id, peeled string
+}
+
+// packedRefs reads the packed-refs file
+func (r *repository) packedRefs() ([]packedRef, error) {
+	f, err := os.Open(filepath.Join(r.commonDir, "packed-refs"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var refs []packedRef
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "^"):
+			if len(refs) > 0 {
+				refs[len(refs)-1].peeled = line[1:]
+			}
+		default:
+			id, name, ok := strings.Cut(line, " ")
+			if ok {
+				refs = append(refs, packedRef{name: name, id: id})
+			}
+		}
+	}
+	return refs, scanner.Err()
+}
+
+// loadTags lists every tag with the commit it peels to. Loose tags take
+// precedence over packed ones of the same name.
+func (r *repository) loadTags() ([]tagRef, error) {
+	if r.tags != nil {
+		return r.tags, nil
+	}
+
+	ids := make(map[string]string)
+	peeled := make(map[string]string)
+	packed, err := r.packedRefs()
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range packed {
+		if name, ok := strings.CutPrefix(ref.name, "refs/tags/"); ok {
+			ids[name] = ref.id
+			if ref.peeled != "" {
+				peeled[name] = ref.peeled
+			}
+		}
+	}
+
+	tagsDir := filepath.Join(r.commonDir, "refs", "tags")
+	err = filepath.WalkDir(tagsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(tagsDir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		ids[name] = strings.TrimSpace(string(data))
+		delete(peeled, name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]tagRef, 0, len(ids))
+	for name, id := range ids {
+		commit, ok := peeled[name]
+		if !ok {
+			if commit, err = r.peel(id); err != nil {
+				return nil, fmt.Errorf("reading tag %s: %w", name, err)
+			}
+		}
+		tags = append(tags, tagRef{name: name, commit: commit, annotated: commit != id})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return compareVersionRefnames(tags[i].name, tags[j].name) > 0
+	})
+	r.tags = tags
+	return tags, nil
+}
+
+// peel follows annotated tags to the commit they name, returning "" for
+// tags of trees or blobs
+func (r *repository) peel(id string) (string, error) {
+	for depth := 0; depth < 10; depth++ {
+		kind, data, err := r.objects.read(id)
+		if err != nil {
+			return "", err
+		}
+		switch kind {
+		case objCommit:
+			return id, nil
+		case objTag:
+			if id, _, err = parseTagTarget(data); err != nil {
+				return "", err
+			}
+		default:
+			return "", nil
+		}
+	}
+	return "", fmt.Errorf("too many levels of tags")
+}
+
+// resolveCommit resolves a revision to a commit: a tag name, ref, branch
+// name, or full or unique abbreviated object ID, followed by any ~N, ^ and ^N
+// steps to its ancestors. Other revision syntax is rejected.
+func (r *repository) resolveCommit(rev string) (string, error) {
+	name, steps := rev, ""
+	if i := strings.IndexAny(rev, "~^"); i >= 0 {
+		// Ref names cannot contain either character
+		name, steps = rev[:i], rev[i:]
+	}
+	commit, err := r.resolveName(name)
+	if err != nil {
+		return "", err
+	}
+
+	for steps != "" {
+		op, n, j := steps[0], 1, 1
+		for j < len(steps) && isDigit(steps[j]) {
+			j++
+		}
+		if op != '~' && op != '^' {
+			return "", fmt.Errorf("unsupported revision syntax in %s", rev)
+		}
+		if j > 1 {
+			if n, err = strconv.Atoi(steps[1:j]); err != nil {
+				return "", fmt.Errorf("unknown revision %s", rev)
+			}
+		}
+		steps = steps[j:]
+
+		// ~N follows N first parents; ^N takes the Nth parent, ^0 the commit
+		count, parent := n, 1
+		if op == '^' {
+			count, parent = min(n, 1), n
+		}
+		for ; count > 0; count-- {
+			c, err := r.commit(commit)
+			if err != nil {
+				return "", err
+			}
+			if parent > len(c.parents) {
+				return "", fmt.Errorf("unknown revision %s", rev)
+			}
+			commit = c.parents[parent-1]
+		}
+	}
+	return commit, nil
+}
+
+// resolveName resolves a tag name, ref, branch name or object ID to a commit
+func (r *repository) resolveName(rev string) (string, error) {
+	if tags, err := r.loadTags(); err == nil {
+		for _, tag := range tags {
+			if tag.name == rev && tag.commit != "" {
+				return tag.commit, nil
+			}
+		}
+	}
+	id, err := r.resolveRevision(rev)
+	if err != nil {
+		switch {
+		case isObjectID(rev):
+			id = rev
+		case isShortObjectID(rev):
+			if id, err = r.objects.expand(strings.ToLower(rev)); err != nil {
+				if errors.Is(err, errObjectNotFound) {
+					return "", fmt.Errorf("unknown revision %s", rev)
+				}
+				return "", err
+			}
+		default:
+			return "", err
+		}
+	}
+	commit, err := r.peel(id)
+	if err != nil {
+		return "", err
+	}
+	if commit == "" {
+		return "", fmt.Errorf("%s does not name a commit", rev)
+	}
+	return commit, nil
+}
+
+// resolveRevision resolves HEAD, a full ref, or a branch or remote-tracking
+// branch by its short name, as git does. Other names are not looked up, so
+// that files in the git directory that are not refs are never read as ones.
+func (r *repository) resolveRevision(rev string) (string, error) {
+	if strings.Contains(rev, "..") || strings.HasPrefix(rev, "/") {
+		return "", fmt.Errorf("unknown revision %s", rev)
+	}
+	if rev == "HEAD" || strings.HasPrefix(rev, "refs/") {
+		return r.resolveRef(rev)
+	}
+	for _, prefix := range []string{"refs/heads/", "refs/remotes/"} {
+		if id, err := r.resolveRef(prefix + rev); err == nil {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("unknown revision %s", rev)
+}
+
+// isShortObjectID reports whether s is an abbreviated hexadecimal object
+// ID, which git accepts from four digits
+func isShortObjectID(s string) bool {
+	if len(s) < 4 || len(s) >= 2*hashLen {
+		return false
+	}
+	for _, c := range strings.ToLower(s) {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// isObjectID reports whether s is a full hexadecimal object ID
+func isObjectID(s string) bool {
+	if len(s) != 2*hashLen {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// commit reads a commit, remembering it for later walks. The parents of a
+// shallow clone's boundary commits are absent and treated as none.
+func (r *repository) commit(id string) (*commitInfo, error) {
+	if c, ok := r.commits[id]; ok {
+		return c, nil
+	}
+	kind, data, err := r.objects.read(id)
+	if err != nil {
+		return nil, err
+	}
+	if kind != objCommit {
+		return nil, fmt.Errorf("object %s is not a commit", id)
+	}
+	c, err := parseCommit(data)
+	if err != nil {
+		return nil, fmt.Errorf("reading commit %s: %w", id, err)
+	}
+	if r.shallow[id] {
+		c.parents = nil
+	}
+	r.commits[id] = c
+	return c, nil
+}
+
+// ancestors returns the commits reachable from id, including id, stopping
+// at commits in stop
+func (r *repository) ancestors(id string, stop map[string]bool) (map[string]bool, error) {
+	seen := make(map[string]bool)
+	pending := []string{id}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if seen[current] || stop[current] {
+			continue
+		}
+		seen[current] = true
+		c, err := r.commit(current)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, c.parents...)
+	}
+	return seen, nil
+}
+
+// distance counts the commits reachable from head but not from base
+func (r *repository) distance(base, head string) (int, error) {
+	excluded, err := r.ancestors(base, nil)
+	if err != nil {
+		return 0, err
+	}
+	counted, err := r.ancestors(head, excluded)
+	if err != nil {
+		return 0, err
+	}
+	return len(counted), nil
+}
+
+// commitQueue orders commits newest first, as git's history walks do
+type commitQueue struct {
+	ids   []string
+	times []int64
+}
+
+func (q *commitQueue) Len() int           { return len(q.ids) }
+func (q *commitQueue) Less(i, j int) bool { return q.times[i] > q.times[j] }
+func (q *commitQueue) Swap(i, j int) {
+	q.ids[i], q.ids[j] = q.ids[j], q.ids[i]
+	q.times[i], q.times[j] = q.times[j], q.times[i]
+}
+func (q *commitQueue) Push(x any) {
+	item := x.([2]any)
+	q.ids = append(q.ids, item[0].(string))
+	q.times = append(q.times, item[1].(int64))
+}
+func (q *commitQueue) Pop() any {
+	n := len(q.ids) - 1
+	id := q.ids[n]
+	q.ids, q.times = q.ids[:n], q.times[:n]
+	return id
+}
+
+// describe finds the tag among candidates, which maps commits to their tag
+// names, nearest to head. Like git describe it walks history newest first,
+// weighs the first maxDescribeCandidates tagged commits it meets, and takes
+// the one fewest commits behind head, the first met on a tie.
+func (r *repository) describe(head string, candidates map[string][]string) (string, error) {
+	headCommit, err := r.commit(head)
+	if err != nil {
+		return "", err
+	}
+	queue := &commitQueue{}
+	heap.Push(queue, [2]any{head, headCommit.time})
+	seen := map[string]bool{head: true}
+
+	var found []string
+	for queue.Len() > 0 && len(found) < maxDescribeCandidates {
+		id := heap.Pop(queue).(string)
+		if len(candidates[id]) > 0 {
+			if len(found) == 0 && id == head {
+				return candidates[id][0], nil
+			}
+			found = append(found, id)
+		}
+		c, err := r.commit(id)
+		if err != nil {
+			return "", err
+		}
+		for _, parent := range c.parents {
+			if seen[parent] {
+				continue
+			}
+			seen[parent] = true
+			pc, err := r.commit(parent)
+			if err != nil {
+				return "", err
+			}
+			heap.Push(queue, [2]any{parent, pc.time})
+		}
+	}
+	if len(found) == 0 {
+		return "", fmt.Errorf("no tags can describe %s", head)
+	}
+
+	reachable, err := r.ancestors(head, nil)
+	if err != nil {
+		return "", err
+	}
+	best, bestDistance := "", 0
+	for _, id := range found {
+		behind, err := r.ancestors(id, nil)
+		if err != nil {
+			return "", err
+		}
+		if d := len(reachable) - len(behind); best == "" || d < bestDistance {
+			best, bestDistance = id, d
+		}
+	}
+	return candidates[best][0], nil
+}
+
+// IsRepository reports whether a repository contains the working directory
+func (b *nativeBackend) IsRepository() bool {
+	_, err := b.open()
+	return err == nil
+}
+
+// RelativePath returns the working directory relative to the work tree
+func (b *nativeBackend) RelativePath() (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	if repo.workTree == "" {
+		return "", nil
+	}
+	abs, err := filepath.Abs(b.workingDir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(repo.workTree, abs)
+	if err != nil {
+		return "", err
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// Head returns the commit HEAD points at
+func (b *nativeBackend) Head() (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	return repo.resolveCommit(b.head())
+}
+
+// Describe finds the matching tag nearest to HEAD
+func (b *nativeBackend) Describe(match string, excludes []string) (string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.resolveCommit(b.head())
+	if err != nil {
+		return "", err
+	}
+	tags, err := repo.loadTags()
+	if err != nil {
+		return "", err
+	}
+
+	// As git describe does, name each commit by its annotated tags before
+	// its lightweight ones, then in refname order
+	ordered := append([]tagRef(nil), tags...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].annotated != ordered[j].annotated {
+			return ordered[i].annotated
+		}
+		return ordered[i].name < ordered[j].name
+	})
+	candidates := make(map[string][]string)
+	for _, tag := range ordered {
+		if tag.commit == "" || !globMatch(match, tag.name) {
+			continue
+		}
+		excluded := false
+		for _, exclude := range excludes {
+			if globMatch(exclude, tag.name) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			candidates[tag.commit] = append(candidates[tag.commit], tag.name)
+		}
+	}
+	return repo.describe(head, candidates)
+}
+
+// PointsAt lists the matching tags on the commit tag points at, in refname
+// order
+func (b *nativeBackend) PointsAt(tag, match string) ([]string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.resolveCommit(tag)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := repo.loadTags()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, t := range tags {
+		if t.commit == commit && globMatch(match, t.name) {
+			names = append(names, t.name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListTags lists tags in descending version sort
+func (b *nativeBackend) ListTags(merged bool) ([]string, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	tags, err := repo.loadTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var reachable map[string]bool
+	if merged {
+		head, err := repo.resolveCommit(b.head())
+		if err != nil {
+			return nil, err
+		}
+		if reachable, err = repo.ancestors(head, nil); err != nil {
+			return nil, err
+		}
+	}
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !merged || reachable[tag.commit] {
+			names = append(names, tag.name)
+		}
+	}
+	return names, nil
+}
+
+// RemoteTags is not available without contacting the remote
+func (b *nativeBackend) RemoteTags() ([]string, error) {
+	return nil, errNativeRemote
+}
+
+// Distance counts the commits reachable from HEAD but not from tag
+func (b *nativeBackend) Distance(tag string) (int, error) {
+	repo, err := b.open()
+	if err != nil {
+		return 0, err
+	}
+	base, err := repo.resolveCommit(tag)
+	if err != nil {
+		return 0, err
+	}
+	head, err := repo.resolveCommit(b.head())
+	if err != nil {
+		return 0, err
+	}
+	return repo.distance(base, head)
+}
+
+// Dirty compares HEAD's tree, the index and the work tree
+func (b *nativeBackend) Dirty() (bool, error) {
+	if b.rev != "" {
+		return false, nil
+	}
+	repo, err := b.open()
+	if err != nil {
+		return false, err
+	}
+	return repo.dirty()
+}
+
+// Tree lists the revision's files from its tree objects; their content is
+// read when a file is opened
+func (b *nativeBackend) Tree() (fs.FS, error) {
+	repo, err := b.open()
+	if err != nil {
+		return nil, err
+	}
+	id, err := repo.resolveCommit(b.head())
+	if err != nil {
+		return nil, err
+	}
+	c, err := repo.commit(id)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]treeEntry)
+	if err := repo.flattenTree(c.tree, "", entries); err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*treeBlob, len(entries))
+	for name, entry := range entries {
+		mode, ok := blobMode(entry.mode)
+		if !ok {
+			continue
+		}
+		files[name] = &treeBlob{id: entry.id, size: -1, mode: mode}
+	}
+	return newTreeFS(files, func(id string) ([]byte, error) {
+		kind, data, err := repo.objects.read(id)
+		if err != nil {
+			return nil, err
+		}
+		if kind != objBlob {
+			return nil, fmt.Errorf("object %s is not a blob", id)
+		}
+		return data, nil
+	}), nil
+}
+
+// FetchTags is not available without contacting the remote
+func (b *nativeBackend) FetchTags() error {
+	return errNativeRemote
+}
+
+// globCache holds compiled tag globs
+var globCache sync.Map
+
+// globMatch matches a tag name against a glob as git's tag matching does,
+// where "*" also matches "/". An empty glob matches every name.
+func globMatch(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	if re, ok := globCache.Load(pattern); ok {
+		return re.(*regexp.Regexp).MatchString(name)
+	}
+	re, err := regexp.Compile(globRegexp(pattern))
+	if err != nil {
+		return pattern == name
+	}
+	globCache.Store(pattern, re)
+	return re.MatchString(name)
+}
+
+// globRegexp translates a glob with "*", "?", "[...]" and "\" escapes into
+// an anchored regular expression
+func globRegexp(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// compareVersionRefnames compares tag names as git's version sort does,
+// taking runs of digits as numbers, so v1.10.0 sorts after v1.9.0
+func compareVersionRefnames(a, b string) int {
+	for a != "" && b != "" {
+		da, db := isDigit(a[0]), isDigit(b[0])
+		if da && db {
+			na, restA := splitRun(a, true)
+			nb, restB := splitRun(b, true)
+			x, _ := strconv.ParseUint(na, 10, 64)
+			y, _ := strconv.ParseUint(nb, 10, 64)
+			if x != y {
+				return compareUint(x, y)
+			}
+			a, b = restA, restB
+			continue
+		}
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[1:], b[1:]
+	}
+	return compareUint(uint64(len(a)), uint64(len(b)))
+}
+
+// splitRun splits the leading run of digits, or non-digits, from s
+func splitRun(s string, digits bool) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// compareUint compares two numbers, returning -1, 0 or +1
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,381 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/sha1" //nolint:gosec // git object names are SHA-1
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fixtureRepo writes a repository's files directly, without git
+type fixtureRepo struct {
+	t      *testing.T
+	gitDir string
+	clock  int64
+}
+
+func newFixtureRepo(t *testing.T, gitDir string) *fixtureRepo {
+	t.Helper()
+	for _, dir := range []string{"objects", "refs/heads", "refs/tags"} {
+		if err := os.MkdirAll(filepath.Join(gitDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &fixtureRepo{t: t, gitDir: gitDir, clock: 1700000000}
+	r.file("HEAD", "ref: refs/heads/main\n")
+	return r
+}
+
+// file writes a file beneath the git directory
+func (r *fixtureRepo) file(name, content string) {
+	r.t.Helper()
+	path := filepath.Join(r.gitDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// object writes a loose object and returns its ID
+func (r *fixtureRepo) object(kind string, data []byte) string {
+	r.t.Helper()
+	raw := append([]byte(fmt.Sprintf("%s %d\x00", kind, len(data))), data...)
+	sum := sha1.Sum(raw) //nolint:gosec // git object names are SHA-1
+	id := hex.EncodeToString(sum[:])
+
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(raw); err != nil {
+		r.t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		r.t.Fatal(err)
+	}
+	r.file("objects/"+id[:2]+"/"+id[2:], compressed.String())
+	return id
+}
+
+// commit writes a commit of a one-file tree, a second after the last
+func (r *fixtureRepo) commit(content string, parents ...string) string {
+	r.t.Helper()
+	blob := r.object("blob", []byte(content))
+	id, _ := hex.DecodeString(blob)
+	tree := r.object("tree", append([]byte("100644 f.txt\x00"), id...))
+
+	r.clock++
+	var b strings.Builder
+	fmt.Fprintf(&b, "tree %s\n", tree)
+	for _, parent := range parents {
+		fmt.Fprintf(&b, "parent %s\n", parent)
+	}
+	fmt.Fprintf(&b, "author A <a@example.com> %d +0000\n", r.clock)
+	fmt.Fprintf(&b, "committer A <a@example.com> %d +0000\n\n%s\n", r.clock, content)
+	return r.object("commit", []byte(b.String()))
+}
+
+// annotatedTag writes a tag object for a commit
+func (r *fixtureRepo) annotatedTag(name, commit string) string {
+	r.t.Helper()
+	return r.object("tag", []byte(fmt.Sprintf(
+		"object %s\ntype commit\ntag %s\ntagger A <a@example.com> %d +0000\n\n%s\n",
+		commit, name, r.clock, name)))
+}
+
+func TestNativeBackend_Fixture(t *testing.T) {
+	root := t.TempDir()
+	repo := newFixtureRepo(t, filepath.Join(root, "main", ".git"))
+
+	// one <- two <- merge -> side <- one; HEAD is merge
+	one := repo.commit("one")
+	two := repo.commit("two", one)
+	side := repo.commit("side", one)
+	merge := repo.commit("merge", two, side)
+	repo.file("refs/heads/main", merge+"\n")
+
+	// v1.0.0 is loose and lightweight; v1.1.0 is annotated and packed with
+	// its peeled commit; v2.0.0 is annotated and packed without one;
+	// v0.9.0 is packed but overridden by a loose ref
+	v110 := repo.annotatedTag("v1.1.0", two)
+	v200 := repo.annotatedTag("v2.0.0", side)
+	repo.file("refs/tags/v1.0.0", one+"\n")
+	repo.file("refs/tags/v0.9.0", one+"\n")
+	repo.file("packed-refs", "# pack-refs with: peeled fully-peeled sorted\n"+
+		v110+" refs/tags/v1.1.0\n^"+two+"\n"+
+		v200+" refs/tags/v2.0.0\n"+
+		two+" refs/tags/v0.9.0\n")
+	if err := os.MkdirAll(filepath.Join(root, "main", "sub", "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A linked worktree on "one"
+	repo.file("worktrees/wt/HEAD", one+"\n")
+	repo.file("worktrees/wt/commondir", "../..\n")
+	if err := os.MkdirAll(filepath.Join(root, "wt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitFile := "gitdir: ../main/.git/worktrees/wt\n"
+	if err := os.WriteFile(filepath.Join(root, "wt", ".git"), []byte(gitFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := newNativeBackend(filepath.Join(root, "main", "sub", "dir"))
+	if !b.IsRepository() {
+		t.Fatal("expected a repository")
+	}
+	if rel, err := b.RelativePath(); err != nil || rel != "sub/dir" {
+		t.Errorf("RelativePath() = %q, %v; want sub/dir", rel, err)
+	}
+	if head, err := b.Head(); err != nil || head != merge {
+		t.Errorf("Head() = %q, %v; want %s", head, err, merge)
+	}
+	if tag, err := b.Describe("", nil); err != nil || tag != "v2.0.0" {
+		t.Errorf("Describe() = %q, %v; want v2.0.0", tag, err)
+	}
+	if tag, err := b.Describe("v1.*", nil); err != nil || tag != "v1.1.0" {
+		t.Errorf("Describe(v1.*) = %q, %v; want v1.1.0", tag, err)
+	}
+	if tag, err := b.Describe("", []string{"v2.*", "v1.1.*"}); err != nil || tag != "v0.9.0" {
+		t.Errorf("Describe excluding v2.* and v1.1.* = %q, %v; want v0.9.0", tag, err)
+	}
+	if tags, err := b.ListTags(false); err != nil ||
+		!reflect.DeepEqual(tags, []string{"v2.0.0", "v1.1.0", "v1.0.0", "v0.9.0"}) {
+		t.Errorf("ListTags() = %v, %v", tags, err)
+	}
+	if tags, err := b.PointsAt("v1.0.0", ""); err != nil ||
+		!reflect.DeepEqual(tags, []string{"v0.9.0", "v1.0.0"}) {
+		t.Errorf("PointsAt(v1.0.0) = %v, %v", tags, err)
+	}
+	if tags, err := b.PointsAt("v1.0.0", "v1.*"); err != nil ||
+		!reflect.DeepEqual(tags, []string{"v1.0.0"}) {
+		t.Errorf("PointsAt(v1.0.0, v1.*) = %v, %v", tags, err)
+	}
+	for tag, want := range map[string]int{"v1.0.0": 3, "v1.1.0": 2, "v2.0.0": 2} {
+		if got, err := b.Distance(tag); err != nil || got != want {
+			t.Errorf("Distance(%s) = %d, %v; want %d", tag, got, err, want)
+		}
+	}
+
+	wt := newNativeBackend(filepath.Join(root, "wt"))
+	if head, err := wt.Head(); err != nil || head != one {
+		t.Errorf("worktree Head() = %q, %v; want %s", head, err, one)
+	}
+	if tags, err := wt.ListTags(true); err != nil ||
+		!reflect.DeepEqual(tags, []string{"v1.0.0", "v0.9.0"}) {
+		t.Errorf("worktree ListTags(true) = %v, %v", tags, err)
+	}
+	if rel, err := wt.RelativePath(); err != nil || rel != "" {
+		t.Errorf("worktree RelativePath() = %q, %v; want empty", rel, err)
+	}
+
+	if newNativeBackend(t.TempDir()).IsRepository() {
+		t.Error("expected an empty directory not to be a repository")
+	}
+	if _, err := b.RemoteTags(); err == nil {
+		t.Error("expected RemoteTags to be unavailable")
+	}
+}
+
+func TestNativeBackend_MatchesExec(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping integration test")
+	}
+
+	tempDir := t.TempDir()
+	repoDir := filepath.Join(tempDir, "repo")
+	if err := os.MkdirAll(filepath.Join(repoDir, "pkg", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) {
+		t.Helper()
+		if err := runGitCommand(repoDir, args...); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(content string) {
+		t.Helper()
+		writeFile("f.txt", strings.Repeat(content+"\n", 200))
+		run("add", "-A")
+		run("commit", "-q", "-m", content)
+	}
+
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	writeFile("pkg/sub/g.txt", "g")
+	commit("one")
+	run("tag", "v1.0.0")
+	commit("two")
+	run("tag", "-a", "v1.1.0-rc.1", "-m", "rc")
+	run("tag", "-a", "v1.1.0", "-m", "release")
+	run("checkout", "-q", "-b", "side")
+	writeFile("side.txt", "side")
+	run("add", "side.txt")
+	run("commit", "-q", "-m", "side")
+	run("tag", "-a", "api/v0.1.0", "-m", "api")
+	run("checkout", "-q", "-")
+	commit("three")
+	run("merge", "-q", "--no-ff", "-m", "merge", "side")
+	run("tag", "v1.10.0")
+	// Pack everything, with deltas between the versions of f.txt, then add
+	// loose objects on top
+	run("gc", "-q", "--aggressive")
+	commit("four")
+	run("tag", "-a", "v1.9.0", "-m", "lower")
+	commit("five")
+	run("worktree", "add", "-q", filepath.Join(tempDir, "wt"), "v1.1.0")
+
+	compare := func(dir string) {
+		t.Helper()
+		execBackend := NewBackend(BackendExec, dir)
+		native := NewBackend(BackendNative, dir)
+		check := func(name string, get func(Backend) (any, error)) {
+			t.Helper()
+			want, wantErr := get(execBackend)
+			got, err := get(native)
+			if (wantErr == nil) != (err == nil) || !reflect.DeepEqual(got, want) {
+				t.Errorf("%s in %s: native %v (%v), exec %v (%v)",
+					name, dir, got, err, want, wantErr)
+			}
+		}
+
+		check("RelativePath", func(b Backend) (any, error) { return b.RelativePath() })
+		check("Head", func(b Backend) (any, error) { return b.Head() })
+		check("Dirty", func(b Backend) (any, error) { return b.Dirty() })
+		check("ListTags", func(b Backend) (any, error) { return b.ListTags(false) })
+		check("ListTags merged", func(b Backend) (any, error) { return b.ListTags(true) })
+		for _, match := range []string{"", "v*", "api/*", "v1.1.*", "none*"} {
+			check("Describe "+match, func(b Backend) (any, error) { return b.Describe(match, nil) })
+		}
+		check("Describe excluding", func(b Backend) (any, error) {
+			return b.Describe("v*", []string{"v1.9.*", "v1.10.*"})
+		})
+		for _, tag := range []string{"v1.0.0", "v1.1.0", "api/v0.1.0", "v1.10.0"} {
+			check("PointsAt "+tag, func(b Backend) (any, error) { return b.PointsAt(tag, "") })
+			check("Distance "+tag, func(b Backend) (any, error) { return b.Distance(tag) })
+		}
+	}
+
+	compare(repoDir)
+	compare(filepath.Join(repoDir, "pkg", "sub"))
+	compare(filepath.Join(tempDir, "wt"))
+
+	writeFile("f.txt", "modified")
+	compare(repoDir)
+	run("add", "f.txt")
+	compare(repoDir)
+	run("reset", "-q", "--hard")
+	writeFile("untracked.txt", "new")
+	compare(repoDir)
+	if err := os.Remove(filepath.Join(repoDir, "pkg", "sub", "g.txt")); err != nil {
+		t.Fatal(err)
+	}
+	compare(repoDir)
+
+	// The extractor gives the same result on either backend
+	run("checkout", "-q", "--", ".")
+	for _, policy := range TagPolicies() {
+		var versions []string
+		for _, kind := range []BackendKind{BackendExec, BackendNative} {
+			extractor := New(repoDir)
+			extractor.SetBackend(NewBackend(kind, repoDir))
+			extractor.SetTagPolicy(TagPolicy(policy))
+			result, err := extractor.GetLatestVersionTag()
+			if err != nil {
+				t.Fatalf("%s with %s: %v", policy, kind, err)
+			}
+			versions = append(versions, result.Version)
+		}
+		if versions[0] != versions[1] {
+			t.Errorf("%s: exec found %s, native found %s", policy, versions[0], versions[1])
+		}
+	}
+}
+
+func TestBackendContext(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping integration test")
+	}
+
+	repoDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+		{"commit", "-q", "--allow-empty", "-m", "one"},
+	} {
+		if err := runGitCommand(repoDir, args...); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, kind := range []BackendKind{BackendExec, BackendNative} {
+		backend := NewBackend(kind, repoDir)
+		if _, err := backend.Head(); err != nil {
+			t.Fatalf("%s: Head failed: %v", kind, err)
+		}
+		backend.SetContext(ctx)
+		if _, err := backend.Head(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected a cancelled context to stop Head, got %v", kind, err)
+		}
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		expected      bool
+	}{
+		{"", "v1.0.0", true},
+		{"v*", "v1.0.0", true},
+		{"v*", "api/v1.0.0", false},
+		{"api/*", "api/v1.0.0", true},
+		{"*", "api/v1.0.0", true},
+		{"v1.?.0", "v1.2.0", true},
+		{"v1.?.0", "v1.10.0", false},
+		{"v[0-9].*", "v1.0", true},
+		{"v[!0-9]*", "v1.0", false},
+		{"v1.0+build", "v1.0+build", true},
+		{`v\*`, "v*", true},
+		{`v\*`, "v1", false},
+	}
+
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.name); got != tt.expected {
+			t.Errorf("globMatch(%q, %q) = %t, expected %t", tt.pattern, tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestParseBackendKind(t *testing.T) {
+	if kind, err := ParseBackendKind(""); err != nil || kind != BackendAuto {
+		t.Errorf("ParseBackendKind(\"\") = %q, %v; want auto", kind, err)
+	}
+	if kind, err := ParseBackendKind("native"); err != nil || kind != BackendNative {
+		t.Errorf("ParseBackendKind(native) = %q, %v", kind, err)
+	}
+	if _, err := ParseBackendKind("libgit2"); err == nil {
+		t.Error("expected an error for an unknown backend")
+	}
+}
@@ -0,0 +1,614 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Object kinds, numbered as in pack files
+const (
+	objCommit   = 1
+	objTree     = 2
+	objBlob     = 3
+	objTag      = 4
+	objOfsDelta = 6
+	objRefDelta = 7
+)
+
+// objectKinds maps the kind names of loose object headers to their numbers
+var objectKinds = map[string]int{
+	"commit": objCommit, "tree": objTree, "blob": objBlob, "tag": objTag,
+}
+
+// hashLen is the length of a SHA-1 object ID in bytes
+const hashLen = 20
+
+// Bounds on the objects kept per pack for reuse as delta bases
+const (
+	maxDeltaCache       = 256
+	maxCachedObjectSize = 1 << 20
+)
+
+// errObjectNotFound reports an object missing from every object directory
+var errObjectNotFound = errors.New("object not found")
+
+// objectStore reads objects from a repository's object directories: its
+// own and any alternates, each holding loose objects and pack files
+type objectStore struct {
+	dirs []string
+
+	once  sync.Once
+	packs []*packFile
+	err   error
+}
+
+// packFile is a pack and its index
+type packFile struct {
+	path    string
+	fanout  [256]uint32
+	ids     []byte // Sorted object IDs, hashLen bytes each
+	offsets []uint64
+
+	mu    sync.Mutex
+	file  *os.File
+	cache map[uint64]packObject
+}
+
+// packObject is an object read from a pack, kept as a delta base
+type packObject struct {
+	kind int
+	data []byte
+}
+
+// newObjectStore returns the store for an objects directory, following
+// objects/info/alternates
+func newObjectStore(objectsDir string) *objectStore {
+	s := &objectStore{}
+	seen := make(map[string]bool)
+	var add func(dir string, depth int)
+	add = func(dir string, depth int) {
+		dir = filepath.Clean(dir)
+		if seen[dir] || depth > 5 {
+			return
+		}
+		seen[dir] = true
+		s.dirs = append(s.dirs, dir)
+		data, err := os.ReadFile(filepath.Join(dir, "info", "alternates"))
+		if err != nil {
+			return
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if !filepath.IsAbs(line) {
+				line = filepath.Join(dir, line)
+			}
+			add(line, depth+1)
+		}
+	}
+	add(objectsDir, 0)
+	return s
+}
+
+// read returns an object's kind and content
+func (s *objectStore) read(id string) (int, []byte, error) {
+	raw, err := hex.DecodeString(id)
+	if err != nil || len(raw) != hashLen {
+		return 0, nil, fmt.Errorf("invalid object ID %q", id)
+	}
+
+	for _, dir := range s.dirs {
+		kind, data, err := readLooseObject(filepath.Join(dir, id[:2], id[2:]))
+		if err == nil {
+			return kind, data, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return 0, nil, fmt.Errorf("reading object %s: %w", id, err)
+		}
+	}
+
+	packs, err := s.loadPacks()
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, pack := range packs {
+		if offset, ok := pack.find(raw); ok {
+			kind, data, err := pack.read(s, offset)
+			if err != nil {
+				return 0, nil, fmt.Errorf("reading object %s from %s: %w",
+					id, filepath.Base(pack.path), err)
+			}
+			return kind, data, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("%w: %s", errObjectNotFound, id)
+}
+
+// expand returns the one object whose ID starts with the hexadecimal
+// prefix, as git resolves an abbreviated object ID
+func (s *objectStore) expand(prefix string) (string, error) {
+	matches := make(map[string]bool)
+	for _, dir := range s.dirs {
+		entries, err := os.ReadDir(filepath.Join(dir, prefix[:2]))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if id := prefix[:2] + entry.Name(); strings.HasPrefix(id, prefix) && len(id) == 2*hashLen {
+				matches[id] = true
+			}
+		}
+	}
+
+	packs, err := s.loadPacks()
+	if err != nil {
+		return "", err
+	}
+	for _, pack := range packs {
+		pack.expand(prefix, matches)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", errObjectNotFound, prefix)
+	case 1:
+		for id := range matches {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("short object ID %s is ambiguous", prefix)
+}
+
+// readLooseObject reads and inflates a loose object file
+func readLooseObject(path string) (int, []byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer f.Close()
+
+	zr, err := zlib.NewReader(bufio.NewReader(f))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer zr.Close()
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	header, data, ok := bytes.Cut(content, []byte{0})
+	if !ok {
+		return 0, nil, fmt.Errorf("malformed loose object header")
+	}
+	name, size, _ := strings.Cut(string(header), " ")
+	kind, known := objectKinds[name]
+	if !known {
+		return 0, nil, fmt.Errorf("unknown object kind %q", name)
+	}
+	if n, err := strconv.Atoi(size); err != nil || n != len(data) {
+		return 0, nil, fmt.Errorf("loose object size mismatch")
+	}
+	return kind, data, nil
+}
+
+// loadPacks reads the index of every pack once
+func (s *objectStore) loadPacks() ([]*packFile, error) {
+	s.once.Do(func() {
+		for _, dir := range s.dirs {
+			indexes, _ := filepath.Glob(filepath.Join(dir, "pack", "*.idx"))
+			sort.Strings(indexes)
+			for _, idx := range indexes {
+				pack, err := openPackIndex(idx)
+				if err != nil {
+					s.err = err
+					return
+				}
+				s.packs = append(s.packs, pack)
+			}
+		}
+	})
+	return s.packs, s.err
+}
+
+// openPackIndex parses a version 1 or 2 pack index
+func openPackIndex(idxPath string) (*packFile, error) {
+	data, err := os.ReadFile(idxPath)
+	if err != nil {
+		return nil, err
+	}
+	pack := &packFile{path: strings.TrimSuffix(idxPath, ".idx") + ".pack"}
+	malformed := fmt.Errorf("malformed pack index %s", filepath.Base(idxPath))
+
+	v2 := bytes.HasPrefix(data, []byte{0xff, 't', 'O', 'c'})
+	fanoutAt := 0
+	if v2 {
+		if len(data) < 8 || binary.BigEndian.Uint32(data[4:]) != 2 {
+			return nil, fmt.Errorf("unsupported pack index version in %s",
+				filepath.Base(idxPath))
+		}
+		fanoutAt = 8
+	}
+	if len(data) < fanoutAt+256*4 {
+		return nil, malformed
+	}
+	for i := range pack.fanout {
+		pack.fanout[i] = binary.BigEndian.Uint32(data[fanoutAt+i*4:])
+	}
+	n := int(pack.fanout[255])
+	at := fanoutAt + 256*4
+
+	if !v2 {
+		// Version 1: a 4 byte offset then the ID for every object
+		if len(data) < at+n*(4+hashLen) {
+			return nil, malformed
+		}
+		pack.ids = make([]byte, 0, n*hashLen)
+		pack.offsets = make([]uint64, n)
+		for i := 0; i < n; i++ {
+			entry := data[at+i*(4+hashLen):]
+			pack.offsets[i] = uint64(binary.BigEndian.Uint32(entry))
+			pack.ids = append(pack.ids, entry[4:4+hashLen]...)
+		}
+		return pack, nil
+	}
+
+	// Version 2: IDs, CRCs, 4 byte offsets, then 8 byte offsets for those
+	// with the high bit set
+	idsAt, offsetsAt := at, at+n*hashLen+n*4
+	largeAt := offsetsAt + n*4
+	if len(data) < largeAt {
+		return nil, malformed
+	}
+	pack.ids = data[idsAt : idsAt+n*hashLen]
+	pack.offsets = make([]uint64, n)
+	for i := 0; i < n; i++ {
+		offset := binary.BigEndian.Uint32(data[offsetsAt+i*4:])
+		if offset&0x80000000 == 0 {
+			pack.offsets[i] = uint64(offset)
+			continue
+		}
+		j := largeAt + int(offset&0x7fffffff)*8
+		if len(data) < j+8 {
+			return nil, malformed
+		}
+		pack.offsets[i] = binary.BigEndian.Uint64(data[j:])
+	}
+	return pack, nil
+}
+
+// find returns the pack offset of an object
+func (p *packFile) find(id []byte) (uint64, bool) {
+	lo := uint32(0)
+	if id[0] > 0 {
+		lo = p.fanout[id[0]-1]
+	}
+	hi := p.fanout[id[0]]
+	i := lo + uint32(sort.Search(int(hi-lo), func(i int) bool {
+		at := int(lo+uint32(i)) * hashLen
+		return bytes.Compare(p.ids[at:at+hashLen], id) >= 0
+	}))
+	if i < hi && bytes.Equal(p.ids[int(i)*hashLen:int(i+1)*hashLen], id) {
+		return p.offsets[i], true
+	}
+	return 0, false
+}
+
+// expand adds the IDs of the pack's objects starting with the hexadecimal
+// prefix to matches
+func (p *packFile) expand(prefix string, matches map[string]bool) {
+	first, _ := hex.DecodeString(prefix[:2])
+	lo := uint32(0)
+	if first[0] > 0 {
+		lo = p.fanout[first[0]-1]
+	}
+	hi := p.fanout[first[0]]
+	i := lo + uint32(sort.Search(int(hi-lo), func(i int) bool {
+		at := int(lo+uint32(i)) * hashLen
+		return hex.EncodeToString(p.ids[at:at+hashLen]) >= prefix
+	}))
+	for ; i < hi; i++ {
+		id := hex.EncodeToString(p.ids[int(i)*hashLen : int(i+1)*hashLen])
+		if !strings.HasPrefix(id, prefix) {
+			break
+		}
+		matches[id] = true
+	}
+}
+
+// read returns the kind and content of the object at offset, resolving
+// deltas against their bases
+func (p *packFile) read(s *objectStore, offset uint64) (int, []byte, error) {
+	p.mu.Lock()
+	if cached, ok := p.cache[offset]; ok {
+		p.mu.Unlock()
+		return cached.kind, cached.data, nil
+	}
+	if p.file == nil {
+		f, err := os.Open(p.path)
+		if err != nil {
+			p.mu.Unlock()
+			return 0, nil, err
+		}
+		p.file = f
+	}
+	r := bufio.NewReader(io.NewSectionReader(p.file, int64(offset), 1<<62))
+	kind, size, base, baseID, err := readPackHeader(r, offset)
+	var data []byte
+	if err == nil {
+		data, err = inflate(r, size)
+	}
+	p.mu.Unlock()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var baseData []byte
+	switch kind {
+	case objOfsDelta:
+		kind, baseData, err = p.read(s, base)
+	case objRefDelta:
+		kind, baseData, err = s.read(hex.EncodeToString(baseID))
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if baseData != nil {
+		if data, err = applyDelta(baseData, data); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if len(data) <= maxCachedObjectSize {
+		p.mu.Lock()
+		if p.cache == nil || len(p.cache) >= maxDeltaCache {
+			p.cache = make(map[uint64]packObject)
+		}
+		p.cache[offset] = packObject{kind: kind, data: data}
+		p.mu.Unlock()
+	}
+	return kind, data, nil
+}
+
+// readPackHeader reads a pack entry's header: its kind, inflated size, and
+// for deltas the base's offset or ID
+func readPackHeader(r *bufio.Reader, offset uint64) (int, uint64, uint64, []byte, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	kind := int(c>>4) & 7
+	size := uint64(c & 0x0f)
+	for shift := 4; c&0x80 != 0; shift += 7 {
+		if c, err = r.ReadByte(); err != nil {
+			return 0, 0, 0, nil, err
+		}
+		size |= uint64(c&0x7f) << shift
+	}
+
+	switch kind {
+	case objOfsDelta:
+		distance, err := readOffsetVarint(r)
+		if err != nil {
+			return 0, 0, 0, nil, err
+		}
+		if distance > offset {
+			return 0, 0, 0, nil, fmt.Errorf("delta base before start of pack")
+		}
+		return kind, size, offset - distance, nil, nil
+	case objRefDelta:
+		id := make([]byte, hashLen)
+		if _, err := io.ReadFull(r, id); err != nil {
+			return 0, 0, 0, nil, err
+		}
+		return kind, size, 0, id, nil
+	case objCommit, objTree, objBlob, objTag:
+		return kind, size, 0, nil, nil
+	}
+	return 0, 0, 0, nil, fmt.Errorf("unknown pack object kind %d", kind)
+}
+
+// readOffsetVarint reads the variable length integer git uses for delta
+// base offsets and index path prefixes, in which each continuation adds one
+// before shifting so that every value has a single encoding
+func readOffsetVarint(r io.ByteReader) (uint64, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	value := uint64(c & 0x7f)
+	for c&0x80 != 0 {
+		if c, err = r.ReadByte(); err != nil {
+			return 0, err
+		}
+		value = ((value + 1) << 7) | uint64(c&0x7f)
+	}
+	return value, nil
+}
+
+// inflate decompresses a zlib stream that inflates to size bytes
+func inflate(r io.Reader, size uint64) ([]byte, error) {
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	data := make([]byte, size)
+	if _, err := io.ReadFull(zr, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// applyDelta rebuilds an object from its base and a delta, which holds the
+// two sizes then instructions to copy ranges of the base or insert literal
+// bytes
+func applyDelta(base, delta []byte) ([]byte, error) {
+	r := bytes.NewReader(delta)
+	readSize := func() (uint64, error) {
+		var size uint64
+		for shift := 0; ; shift += 7 {
+			c, err := r.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			size |= uint64(c&0x7f) << shift
+			if c&0x80 == 0 {
+				return size, nil
+			}
+		}
+	}
+	malformed := fmt.Errorf("malformed delta")
+
+	baseSize, err := readSize()
+	if err != nil || baseSize != uint64(len(base)) {
+		return nil, malformed
+	}
+	resultSize, err := readSize()
+	if err != nil {
+		return nil, malformed
+	}
+
+	out := make([]byte, 0, resultSize)
+	for r.Len() > 0 {
+		op, _ := r.ReadByte()
+		switch {
+		case op&0x80 != 0:
+			// Copy: bits 0-3 select offset bytes, bits 4-6 size bytes
+			var offset, size uint64
+			for i := 0; i < 4; i++ {
+				if op&(1<<i) != 0 {
+					c, err := r.ReadByte()
+					if err != nil {
+						return nil, malformed
+					}
+					offset |= uint64(c) << (8 * i)
+				}
+			}
+			for i := 0; i < 3; i++ {
+				if op&(0x10<<i) != 0 {
+					c, err := r.ReadByte()
+					if err != nil {
+						return nil, malformed
+					}
+					size |= uint64(c) << (8 * i)
+				}
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > uint64(len(base)) {
+				return nil, malformed
+			}
+			out = append(out, base[offset:offset+size]...)
+		case op != 0:
+			// Insert the next op bytes
+			literal := make([]byte, op)
+			if _, err := io.ReadFull(r, literal); err != nil {
+				return nil, malformed
+			}
+			out = append(out, literal...)
+		default:
+			return nil, malformed
+		}
+	}
+	if uint64(len(out)) != resultSize {
+		return nil, malformed
+	}
+	return out, nil
+}
+
+// commitInfo is the part of a commit the history walks need
+type commitInfo struct {
+	tree    string
+	parents []string
+	time    int64 // Committer time, seconds since the epoch
+}
+
+// parseCommit reads a commit's tree, parents and committer time
+func parseCommit(data []byte) (*commitInfo, error) {
+	c := &commitInfo{}
+	header, _, _ := bytes.Cut(data, []byte("\n\n"))
+	for _, line := range strings.Split(string(header), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "tree":
+			c.tree = value
+		case "parent":
+			c.parents = append(c.parents, value)
+		case "committer":
+			// Name <email> seconds timezone
+			fields := strings.Fields(value)
+			if len(fields) >= 2 {
+				c.time, _ = strconv.ParseInt(fields[len(fields)-2], 10, 64)
+			}
+		}
+	}
+	if c.tree == "" {
+		return nil, fmt.Errorf("malformed commit")
+	}
+	return c, nil
+}
+
+// parseTagTarget reads the ID and kind of the object a tag object names
+func parseTagTarget(data []byte) (string, string, error) {
+	var target, kind string
+	header, _, _ := bytes.Cut(data, []byte("\n\n"))
+	for _, line := range strings.Split(string(header), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "object":
+			target = value
+		case "type":
+			kind = value
+		}
+	}
+	if target == "" {
+		return "", "", fmt.Errorf("malformed tag")
+	}
+	return target, kind, nil
+}
+
+// treeEntry is one entry of a tree object
+type treeEntry struct {
+	mode uint32
+	name string
+	id   string
+}
+
+// parseTree reads a tree's entries, each "<octal mode> <name>\0<ID>"
+func parseTree(data []byte) ([]treeEntry, error) {
+	var entries []treeEntry
+	for len(data) > 0 {
+		header, rest, ok := bytes.Cut(data, []byte{0})
+		if !ok || len(rest) < hashLen {
+			return nil, fmt.Errorf("malformed tree")
+		}
+		mode, name, _ := strings.Cut(string(header), " ")
+		m, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("malformed tree entry mode %q", mode)
+		}
+		entries = append(entries, treeEntry{mode: uint32(m), name: name,
+			id: hex.EncodeToString(rest[:hashLen])})
+		data = rest[hashLen:]
+	}
+	return entries, nil
+}
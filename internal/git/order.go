@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/version"
+)
+
+// TagPolicy selects which version tag is the latest
+type TagPolicy string
+
+// Supported tag selection policies
+const (
+	// PolicyNearest takes the tag nearest to HEAD, as git describe finds it;
+	// of several tags on that commit, the highest
+	PolicyNearest TagPolicy = "nearest"
+	// PolicyHighestReachable takes the highest tag reachable from HEAD, so a
+	// maintenance tag such as 1.0.1 made after 1.1.0 does not win
+	PolicyHighestReachable TagPolicy = "highest-reachable"
+	// PolicyHighestAny takes the highest tag in the repository, falling back
+	// to the remote's tags when there are none locally
+	PolicyHighestAny TagPolicy = "highest-any"
+)
+
+// TagOrdering selects how version tags are ranked against each other
+type TagOrdering string
+
+// Tag orderings
+const (
+	// OrderSemver ranks tags by Semantic Versioning precedence, so 1.0.0
+	// ranks above 1.0.0-rc.1 (the default)
+	OrderSemver TagOrdering = "semver"
+	// OrderMaven ranks tags in Maven order, so 1.0.Final ranks above
+	// 1.0-RC1 and 1.0-SNAPSHOT. Maven-style tags such as 2.0.Final are valid
+	// version tags under this ordering.
+	OrderMaven TagOrdering = "maven"
+)
+
+// maxDescribeAttempts bounds how many tagged commits the nearest policy
+// passes over when they carry no wanted tag
+const maxDescribeAttempts = 25
+
+// TagPolicies returns the supported tag selection policy names
+func TagPolicies() []string {
+	return []string{string(PolicyNearest), string(PolicyHighestReachable),
+		string(PolicyHighestAny)}
+}
+
+// ParseTagPolicy validates a tag selection policy name. An empty name
+// selects PolicyNearest.
+func ParseTagPolicy(name string) (TagPolicy, error) {
+	if name == "" {
+		return PolicyNearest, nil
+	}
+	for _, policy := range TagPolicies() {
+		if name == policy {
+			return TagPolicy(name), nil
+		}
+	}
+	return "", fmt.Errorf("unknown tag policy %q (expected one of: %s)",
+		name, strings.Join(TagPolicies(), ", "))
+}
+
+// SetTagPolicy sets which version tag is the latest. The default is
+// PolicyNearest.
+func (g *GitVersionExtractor) SetTagPolicy(policy TagPolicy) {
+	g.tagPolicy = policy
+}
+
+// SetTagOrdering sets how version tags are ranked. The default is
+// OrderSemver.
+func (g *GitVersionExtractor) SetTagOrdering(ordering TagOrdering) {
+	g.tagOrdering = ordering
+}
+
+// SetExcludePrerelease skips tags whose version is a pre-release, such as
+// 1.0.0-rc.1, or a snapshot in Maven order
+func (g *GitVersionExtractor) SetExcludePrerelease(exclude bool) {
+	g.excludePrerelease = exclude
+}
+
+// selectTag returns the version and tag of the highest wanted version tag
+// among tags. Tags of equal precedence, and tags whose version does not
+// parse, keep the order they are given in.
+func (g *GitVersionExtractor) selectTag(tags []string) (string, string, error) {
+	var bestVersion, bestTag string
+	var best *version.Version
+	for _, tag := range tags {
+		v, valid, err := g.versionFromTag(tag)
+		if err != nil {
+			return "", "", err
+		}
+		if !valid {
+			continue
+		}
+		parsed := g.parseTagVersion(v)
+		if parsed != nil && g.excludePrerelease && parsed.IsPrerelease() {
+			continue
+		}
+		switch {
+		case bestTag == "":
+		case parsed == nil:
+			continue
+		case best != nil && parsed.Compare(best) <= 0:
+			continue
+		}
+		best, bestVersion, bestTag = parsed, v, tag
+	}
+	return bestVersion, bestTag, nil
+}
+
+// parseTagVersion parses a tag's version for ranking, or returns nil
+func (g *GitVersionExtractor) parseTagVersion(v string) *version.Version {
+	var parsed *version.Version
+	var err error
+	if g.tagOrdering == OrderMaven {
+		parsed, err = version.ParseMaven(v).Version()
+	} else {
+		parsed, err = version.Parse(v)
+	}
+	if err != nil {
+		return nil
+	}
+	return parsed
+}
+
+// getNearestTag finds the wanted version tag nearest to HEAD among those
+// matching matchPattern, taking the highest of several tags on one commit.
+// Commits whose tags are all unwanted (not versions, or pre-releases when
+// they are excluded) are passed over.
+func (g *GitVersionExtractor) getNearestTag(matchPattern string) (string, string, error) {
+	var excludes []string
+	for attempt := 0; attempt < maxDescribeAttempts; attempt++ {
+		_, tag, err := g.getTagWithDescribe(matchPattern, excludes...)
+		if err != nil {
+			return "", "", err
+		}
+
+		tags, err := g.backend.PointsAt(tag, matchPattern)
+		if err != nil {
+			return "", "", err
+		}
+		if len(tags) == 0 {
+			tags = []string{tag}
+		}
+
+		v, best, err := g.selectTag(tags)
+		if err != nil {
+			return "", "", err
+		}
+		if v != "" {
+			return v, best, nil
+		}
+		excludes = append(excludes, tags...)
+	}
+	return "", "", fmt.Errorf("no version tag within %d tagged commits",
+		maxDescribeAttempts)
+}
+
+// isMavenVersionTag reports whether a version is a Maven version, when tags
+// are in Maven order
+func (g *GitVersionExtractor) isMavenVersionTag(v string) bool {
+	if g.tagOrdering != OrderMaven {
+		return false
+	}
+	rule, err := version.ParseSchemeRule("maven")
+	return err == nil && rule.Match(v)
+}
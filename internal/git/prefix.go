@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// tagPrefixData is the data available to tag prefix templates
+type tagPrefixData struct {
+	Path string // Project directory relative to the repository root, "" at the root
+	Name string // Last element of Path
+}
+
+// ExpandTagPrefix renders a tag prefix template such as "{{.Path}}/" or
+// "charts/{{.Name}}-" for a project directory given relative to the
+// repository root. A leading "/" left by an empty Path is dropped, so the Go
+// module convention "{{.Path}}/" yields no prefix for a root module.
+func ExpandTagPrefix(prefix, relPath string) (string, error) {
+	if !strings.Contains(prefix, "{{") {
+		return prefix, nil
+	}
+
+	tmpl, err := template.New("tag_prefix").Option("missingkey=error").Parse(prefix)
+	if err != nil {
+		return "", fmt.Errorf("invalid tag prefix template %q: %w", prefix, err)
+	}
+
+	relPath = strings.Trim(path.Clean("/"+relPath), "/")
+	data := tagPrefixData{Path: relPath}
+	if relPath != "" {
+		data.Name = path.Base(relPath)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("invalid tag prefix template %q: %w", prefix, err)
+	}
+	return strings.TrimLeft(out.String(), "/"), nil
+}
+
+// SetTagPrefix restricts tag lookups to tags starting with prefix, which is
+// removed before the version is read from the tag. This selects one
+// component's tags in a monorepo, e.g. "api/" for api/v2.3.0.
+func (g *GitVersionExtractor) SetTagPrefix(prefix string) {
+	g.tagPrefix = prefix
+}
+
+// RelativePath returns the working directory relative to the repository
+// root, using "/" separators, or "" at the root.
+func (g *GitVersionExtractor) RelativePath() (string, error) {
+	return g.backend.RelativePath()
+}
+
+// versionFromTag returns the version a tag carries and whether it is a
+// usable version tag, honouring the configured tag prefix.
+func (g *GitVersionExtractor) versionFromTag(tag string) (string, bool, error) {
+	tag = strings.TrimSpace(tag)
+	if g.tagPrefix != "" {
+		if !strings.HasPrefix(tag, g.tagPrefix) {
+			return "", false, nil
+		}
+		tag = tag[len(g.tagPrefix):]
+	}
+
+	version := g.cleanVersionFromTag(tag)
+	valid, err := g.isValidVersionTag(version)
+	return version, valid, err
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandTagPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		relPath string
+		want    string
+	}{
+		{"api/", "services/api", "api/"},
+		{"{{.Path}}/", "services/api", "services/api/"},
+		{"{{.Path}}/", "", ""},
+		{"{{.Name}}-v", "packages/web", "web-v"},
+		{"charts/{{.Name}}-", "charts/mychart", "charts/mychart-"},
+		{"{{.Path}}-", "charts/mychart/", "charts/mychart-"},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandTagPrefix(tt.prefix, tt.relPath)
+		if err != nil {
+			t.Errorf("ExpandTagPrefix(%q, %q) failed: %v", tt.prefix, tt.relPath, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExpandTagPrefix(%q, %q) = %q, want %q", tt.prefix, tt.relPath, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"{{.Path", "{{.Module}}/"} {
+		if _, err := ExpandTagPrefix(bad, "api"); err == nil {
+			t.Errorf("ExpandTagPrefix(%q) should fail", bad)
+		}
+	}
+}
+
+func TestGetLatestVersionTag_TagPrefix(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping integration test")
+	}
+
+	tempDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		if err := runGitCommand(tempDir, args...); err != nil {
+			t.Skipf("Failed to set up git repo: %v", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(tempDir, "services", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "test.txt"), []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGitCommand(tempDir, "add", "test.txt"); err != nil {
+		t.Skipf("Failed to add file: %v", err)
+	}
+	if err := runGitCommand(tempDir, "commit", "-m", "Initial commit"); err != nil {
+		t.Skipf("Failed to commit: %v", err)
+	}
+
+	// The web tags are the most recent, and web-ui shares the web- prefix
+	for _, tag := range []string{"api/v2.3.0", "api/v2.10.0", "web-v1.9.0", "web-ui-v3.0.0", "v9.0.0"} {
+		if err := runGitCommand(tempDir, "tag", tag); err != nil {
+			t.Skipf("Failed to create tag: %v", err)
+		}
+	}
+
+	tests := []struct {
+		prefix  string
+		version string
+		tag     string
+	}{
+		{"api/", "2.10.0", "api/v2.10.0"},
+		{"web-", "1.9.0", "web-v1.9.0"},
+		{"web-ui-", "3.0.0", "web-ui-v3.0.0"},
+	}
+	for _, tt := range tests {
+		extractor := New(tempDir)
+		extractor.SetTagPrefix(tt.prefix)
+		result, err := extractor.GetLatestVersionTag()
+		if err != nil {
+			t.Fatalf("prefix %s: expected no error, got: %v", tt.prefix, err)
+		}
+		if result.Version != tt.version || result.Tag != tt.tag {
+			t.Errorf("prefix %s: got %s (%s), want %s (%s)",
+				tt.prefix, result.Version, result.Tag, tt.version, tt.tag)
+		}
+	}
+
+	extractor := New(tempDir)
+	extractor.SetTagPrefix("docs/")
+	if _, err := extractor.GetLatestVersionTag(); err == nil {
+		t.Error("expected no tags to match the docs/ prefix")
+	}
+
+	rel, err := New(filepath.Join(tempDir, "services", "api")).RelativePath()
+	if err != nil || rel != "services/api" {
+		t.Errorf("RelativePath() = %q, %v; want services/api", rel, err)
+	}
+}
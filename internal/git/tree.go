@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// treeBlob is a file committed in a tree
+type treeBlob struct {
+	id   string
+	size int64 // -1 until the blob is read, when the listing lacks sizes
+	mode fs.FileMode
+}
+
+// treeFS serves the files of a commit's tree. The listing is read up front;
+// a file's content is read when the file is opened, and kept, since
+// extraction reads few files, some more than once.
+type treeFS struct {
+	files map[string]*treeBlob
+	dirs  map[string][]string // Directory -> the names of its entries
+	read  func(id string) ([]byte, error)
+
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+// newTreeFS serves files, which map paths to the blobs committed there,
+// reading blobs with read
+func newTreeFS(files map[string]*treeBlob, read func(id string) ([]byte, error)) *treeFS {
+	t := &treeFS{
+		files: files,
+		dirs:  map[string][]string{".": nil},
+		read:  read,
+		blobs: make(map[string][]byte),
+	}
+	for name := range files {
+		for child := name; child != "."; child = path.Dir(child) {
+			dir := path.Dir(child)
+			_, seen := t.dirs[dir]
+			t.dirs[dir] = append(t.dirs[dir], path.Base(child))
+			if seen {
+				// The parents of a known directory are already indexed
+				break
+			}
+		}
+	}
+	for _, names := range t.dirs {
+		sort.Strings(names)
+	}
+	return t
+}
+
+// blob returns a file's content, reading it on first use
+func (t *treeFS) blob(b *treeBlob) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if data, ok := t.blobs[b.id]; ok {
+		return data, nil
+	}
+	data, err := t.read(b.id)
+	if err != nil {
+		return nil, err
+	}
+	t.blobs[b.id] = data
+	b.size = int64(len(data))
+	return data, nil
+}
+
+// Open opens the named file or directory
+func (t *treeFS) Open(name string) (fs.File, error) {
+	info, err := t.Stat(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: unwrapPathError(err)}
+	}
+	if info.IsDir() {
+		entries, _ := t.ReadDir(name)
+		return &treeDir{info: info, entries: entries}, nil
+	}
+	data, err := t.blob(t.files[name])
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &treeFile{info: info, Reader: bytes.NewReader(data)}, nil
+}
+
+// Stat describes the named file or directory
+func (t *treeFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if _, ok := t.dirs[name]; ok {
+		return &treeInfo{name: path.Base(name), mode: fs.ModeDir | 0o755}, nil
+	}
+	b, ok := t.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	if b.size < 0 {
+		if _, err := t.blob(b); err != nil {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+		}
+	}
+	return &treeInfo{name: path.Base(name), size: b.size, mode: b.mode}, nil
+}
+
+// ReadFile reads the named file
+func (t *treeFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	b, ok := t.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	data, err := t.blob(b)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return bytes.Clone(data), nil
+}
+
+// ReadDir lists the named directory, sorted by name
+func (t *treeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	names, ok := t.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(names))
+	for _, entry := range names {
+		child := path.Join(name, entry)
+		mode := fs.ModeDir
+		if b, ok := t.files[child]; ok {
+			mode = b.mode
+		}
+		entries = append(entries, &treeDirEntry{t: t, path: child, mode: mode})
+	}
+	return entries, nil
+}
+
+// treeDirEntry is an entry of a tree directory. Its size is only known once
+// the file is read, which Info does.
+type treeDirEntry struct {
+	t    *treeFS
+	path string
+	mode fs.FileMode
+}
+
+func (d *treeDirEntry) Name() string               { return path.Base(d.path) }
+func (d *treeDirEntry) IsDir() bool                { return d.mode.IsDir() }
+func (d *treeDirEntry) Type() fs.FileMode          { return d.mode.Type() }
+func (d *treeDirEntry) Info() (fs.FileInfo, error) { return d.t.Stat(d.path) }
+
+// unwrapPathError returns the error a PathError wraps
+func unwrapPathError(err error) error {
+	if pathErr, ok := err.(*fs.PathError); ok {
+		return pathErr.Err
+	}
+	return err
+}
+
+// treeInfo describes a file or directory in a tree. Trees record no
+// modification times.
+type treeInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (i *treeInfo) Name() string       { return i.name }
+func (i *treeInfo) Size() int64        { return i.size }
+func (i *treeInfo) Mode() fs.FileMode  { return i.mode }
+func (i *treeInfo) ModTime() time.Time { return time.Time{} }
+func (i *treeInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *treeInfo) Sys() any           { return nil }
+
+// treeFile is an open file of a tree
+type treeFile struct {
+	info fs.FileInfo
+	*bytes.Reader
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *treeFile) Close() error               { return nil }
+
+// treeDir is an open directory of a tree
+type treeDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *treeDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *treeDir) Close() error               { return nil }
+
+func (d *treeDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: fs.ErrInvalid}
+}
+
+// ReadDir returns the next n entries, or all remaining ones when n <= 0
+func (d *treeDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
+
+// blobMode is the file mode of a tree entry's file, or false for entries
+// that are not file content: symbolic links and submodules
+func blobMode(mode uint32) (fs.FileMode, bool) {
+	switch mode {
+	case modeFile:
+		return 0o644, true
+	case modeExecutable:
+		return 0o755, true
+	}
+	return 0, false
+}
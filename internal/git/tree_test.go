@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package git
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping integration test")
+	}
+
+	repoDir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if err := runGitCommand(repoDir, args...); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	writeFile("VERSION", "1.0.0\n")
+	writeFile("web/package.json", `{"version": "1.0.0"}`)
+	writeFile("tool.sh", "#!/bin/sh\n")
+	if err := os.Chmod(filepath.Join(repoDir, "tool.sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("VERSION", filepath.Join(repoDir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "-A")
+	run("commit", "-q", "-m", "one")
+	run("tag", "v1.0.0")
+	run("checkout", "-q", "-b", "release")
+	writeFile("VERSION", "1.1.0\n")
+	run("commit", "-q", "-am", "two")
+	run("commit", "-q", "--allow-empty", "-m", "three")
+	run("checkout", "-q", "-")
+	writeFile("VERSION", "2.0.0\n")
+	run("commit", "-q", "-am", "four")
+	run("tag", "v2.0.0")
+	// Uncommitted changes are not part of any revision
+	writeFile("VERSION", "dirty\n")
+
+	for _, kind := range []BackendKind{BackendExec, BackendNative} {
+		t.Run(string(kind), func(t *testing.T) {
+			backend := NewBackend(kind, filepath.Join(repoDir, "web"))
+			backend.SetRevision("release")
+
+			tree, err := backend.Tree()
+			if err != nil {
+				t.Fatalf("Tree failed: %v", err)
+			}
+			if err := fstest.TestFS(tree, "VERSION", "web/package.json", "tool.sh"); err != nil {
+				t.Error(err)
+			}
+			if data, err := fs.ReadFile(tree, "VERSION"); err != nil || string(data) != "1.1.0\n" {
+				t.Errorf("Expected VERSION 1.1.0 at release, got %q (%v)", data, err)
+			}
+			if info, err := fs.Stat(tree, "tool.sh"); err != nil || info.Mode() != 0755 {
+				t.Errorf("Expected an executable tool.sh, got %v (%v)", info, err)
+			}
+			if _, err := fs.Stat(tree, "link"); err == nil {
+				t.Error("Expected symbolic links to be left out")
+			}
+
+			if tag, err := backend.Describe("", nil); err != nil || tag != "v1.0.0" {
+				t.Errorf("Expected v1.0.0 to describe release, got %q (%v)", tag, err)
+			}
+			if distance, err := backend.Distance("v1.0.0"); err != nil || distance != 2 {
+				t.Errorf("Expected release 2 commits after v1.0.0, got %d (%v)", distance, err)
+			}
+			if tags, err := backend.ListTags(true); err != nil || len(tags) != 1 {
+				t.Errorf("Expected only v1.0.0 merged into release, got %v (%v)", tags, err)
+			}
+			if dirty, err := backend.Dirty(); err != nil || dirty {
+				t.Errorf("Expected a revision to be clean, got %v (%v)", dirty, err)
+			}
+
+			backend.SetRevision("v2.0.0")
+			tree, err = backend.Tree()
+			if err != nil {
+				t.Fatalf("Tree of a tag failed: %v", err)
+			}
+			if data, err := fs.ReadFile(tree, "VERSION"); err != nil || string(data) != "2.0.0\n" {
+				t.Errorf("Expected VERSION 2.0.0 at v2.0.0, got %q (%v)", data, err)
+			}
+
+			backend.SetRevision("no-such-branch")
+			if _, err := backend.Head(); err == nil {
+				t.Error("Expected an unknown revision to fail")
+			}
+		})
+	}
+
+	extractor := New(repoDir)
+	extractor.SetRevision("release")
+	result, err := extractor.GetLatestVersionTag()
+	if err != nil {
+		t.Fatalf("GetLatestVersionTag failed: %v", err)
+	}
+	if result.Version != "1.0.0" || result.Distance != 2 || result.Dirty {
+		t.Errorf("Expected clean 1.0.0 two commits back, got %+v", result)
+	}
+}
+
+func TestRevisionParity(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping integration test")
+	}
+
+	repoDir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).Output()
+		if err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	run("commit", "-q", "--allow-empty", "-m", "one")
+	run("tag", "-a", "v1.0.0", "-m", "1.0.0")
+	run("checkout", "-q", "-b", "topic")
+	run("commit", "-q", "--allow-empty", "-m", "two")
+	run("checkout", "-q", "-")
+	run("commit", "-q", "--allow-empty", "-m", "three")
+	run("merge", "-q", "--no-ff", "-m", "merge", "topic")
+	first := run("rev-list", "--max-parents=0", "HEAD")
+
+	revisions := []string{
+		"HEAD", "HEAD~", "HEAD~1", "HEAD~2", "HEAD^", "HEAD^2", "HEAD^^",
+		"HEAD^2~1", "HEAD^0", "topic~1", "v1.0.0^0", first, first[:7],
+		strings.ToUpper(first[:10]),
+		// Revisions neither backend resolves
+		"HEAD^3", "HEAD~9", "topic^{tree}", "no-such-branch~1", "fffffff",
+	}
+	check := func(t *testing.T) {
+		viaExec, viaNative := NewBackend(BackendExec, repoDir), NewBackend(BackendNative, repoDir)
+		for _, rev := range revisions {
+			viaExec.SetRevision(rev)
+			viaNative.SetRevision(rev)
+			want, wantErr := viaExec.Head()
+			got, gotErr := viaNative.Head()
+			if got != want || (gotErr == nil) != (wantErr == nil) {
+				t.Errorf("%s: native resolved %q (%v), exec %q (%v)", rev, got, gotErr, want, wantErr)
+			}
+		}
+	}
+
+	t.Run("loose", check)
+	run("gc", "-q")
+	t.Run("packed", check)
+}
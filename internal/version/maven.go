@@ -0,0 +1,324 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// mavenQualifiers lists the well-known qualifiers in ascending order. The
+// empty qualifier is a release; unknown qualifiers sort after all of them.
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// mavenAliases maps qualifier spellings to their normal form
+var mavenAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+// mavenTimestampRe matches a deployed snapshot's timestamped version, e.g.
+// 1.0-20240115.103000-3
+var mavenTimestampRe = regexp.MustCompile(`-[0-9]{8}\.[0-9]{6}-[0-9]+$`)
+
+// mavenReleaseRe splits a version into its leading release numbers and the
+// qualifiers after them
+var mavenReleaseRe = regexp.MustCompile(`^[vV]?([0-9]+(?:\.[0-9]+)*)[.-]?([0-9A-Za-z_.-]*)$`)
+
+// MavenVersion is a version ordered as Maven's ComparableVersion orders it:
+// numbers compare numerically, qualifiers in the order alpha < beta <
+// milestone < rc < snapshot < release < sp, trailing zeros and release
+// qualifiers are ignored, so 1.0 = 1.0.0 = 1.0-ga, and 1.2.3-SNAPSHOT <
+// 1.2.3 < 1.2.3-sp1.
+type MavenVersion struct {
+	Original string
+	items    *mavenList
+}
+
+// mavenItem is one element of a parsed Maven version: a number, a
+// qualifier, or a sub-list started by "-" or a number/letter transition
+type mavenItem interface {
+	// compare compares the item with o, which is nil past the end of the
+	// shorter list
+	compare(o mavenItem) int
+	isNull() bool
+	String() string
+}
+
+// mavenInt is a numeric item, held as its digits without leading zeros so
+// numbers of any size compare correctly
+type mavenInt string
+
+// mavenString is a qualifier item
+type mavenString string
+
+// mavenList is a sub-list of items
+type mavenList struct {
+	items []mavenItem
+}
+
+// ParseMaven parses a version as Maven does. Every string is a valid Maven
+// version.
+func ParseMaven(s string) *MavenVersion {
+	version := strings.ToLower(strings.TrimSpace(s))
+	root := &mavenList{}
+	list := root
+	stack := []*mavenList{root}
+	startList := func() {
+		next := &mavenList{}
+		list.items = append(list.items, next)
+		list = next
+		stack = append(stack, next)
+	}
+
+	isDigit := false
+	start := 0
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				list.items = append(list.items, mavenInt(""))
+			} else {
+				list.items = append(list.items, parseMavenItem(isDigit, version[start:i]))
+			}
+			start = i + 1
+			if c == '-' {
+				startList()
+			}
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				list.items = append(list.items, newMavenString(version[start:i], true))
+				start = i
+				startList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.items = append(list.items, parseMavenItem(true, version[start:i]))
+				start = i
+				startList()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		list.items = append(list.items, parseMavenItem(isDigit, version[start:]))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return &MavenVersion{Original: s, items: root}
+}
+
+// Compare returns -1, 0 or +1 as m has lower, equal or higher precedence
+// than o
+func (m *MavenVersion) Compare(o *MavenVersion) int {
+	return m.items.compare(o.items)
+}
+
+// String returns the canonical form of the version, in which equal
+// versions are identical: 1.0.0-GA becomes 1, and 1.0-alpha1 becomes
+// 1-alpha-1
+func (m *MavenVersion) String() string {
+	return m.items.String()
+}
+
+// IsSnapshot reports whether the version is a snapshot: it ends in SNAPSHOT
+// or is a deployed snapshot's timestamped version
+func (m *MavenVersion) IsSnapshot() bool {
+	v := strings.TrimSpace(m.Original)
+	return strings.HasSuffix(strings.ToUpper(v), "SNAPSHOT") || mavenTimestampRe.MatchString(v)
+}
+
+// Version returns the version in the generic form, ordered in Maven order
+// against other maven versions. Qualifiers that mark a release, such as the
+// Final of 1.2.3.Final, are not reported as a pre-release.
+func (m *MavenVersion) Version() (*Version, error) {
+	v, err := Parse(m.Original)
+	if err != nil {
+		// Qualifiers such as 2.0.Final follow the numbers without a hyphen
+		match := mavenReleaseRe.FindStringSubmatch(strings.TrimSpace(m.Original))
+		if match == nil {
+			return nil, fmt.Errorf("invalid Maven version %q", m.Original)
+		}
+		v = &Version{Original: m.Original}
+		for _, part := range strings.Split(match[1], ".") {
+			n, err := strconv.ParseUint(part, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Maven version %q: %w", m.Original, err)
+			}
+			v.Release = append(v.Release, n)
+		}
+		for i, field := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+			if i < len(v.Release) {
+				*field = v.Release[i]
+			}
+		}
+		v.Prerelease = strings.FieldsFunc(match[2], func(r rune) bool {
+			return r == '.' || r == '-'
+		})
+	}
+	v.Scheme, v.maven = SchemeMaven, m
+	if m.Compare(ParseMaven(joinRelease(v.Release))) == 0 {
+		v.Prerelease = nil
+	}
+	return v, nil
+}
+
+// CompareMaven compares two version strings in Maven order, as
+// MavenVersion.Compare
+func CompareMaven(a, b string) int {
+	return ParseMaven(a).Compare(ParseMaven(b))
+}
+
+// parseMavenItem parses one component of a version
+func parseMavenItem(isDigit bool, s string) mavenItem {
+	if isDigit {
+		return mavenInt(strings.TrimLeft(s, "0"))
+	}
+	return newMavenString(s, false)
+}
+
+// newMavenString normalises a qualifier. A single letter followed by a
+// number is shorthand: a1 is alpha-1, b2 beta-2 and m3 milestone-3.
+func newMavenString(s string, followedByDigit bool) mavenString {
+	if followedByDigit && len(s) == 1 {
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := mavenAliases[s]; ok {
+		s = alias
+	}
+	return mavenString(s)
+}
+
+// normalize removes trailing null items (zeros, release qualifiers and
+// empty lists) up to the last sub-list
+func (l *mavenList) normalize() {
+	for i := len(l.items) - 1; i >= 0; i-- {
+		item := l.items[i]
+		if item.isNull() {
+			l.items = append(l.items[:i], l.items[i+1:]...)
+		} else if _, ok := item.(*mavenList); !ok {
+			break
+		}
+	}
+}
+
+func (n mavenInt) isNull() bool { return n == "" }
+
+func (n mavenInt) String() string {
+	if n == "" {
+		return "0"
+	}
+	return string(n)
+}
+
+func (n mavenInt) compare(o mavenItem) int {
+	switch o := o.(type) {
+	case nil:
+		if n.isNull() {
+			return 0
+		}
+		return 1
+	case mavenInt:
+		if len(n) != len(o) {
+			return compareUint(uint64(len(n)), uint64(len(o)))
+		}
+		return strings.Compare(string(n), string(o))
+	}
+	// 1.1 > 1-sp and 1.1 > 1-1
+	return 1
+}
+
+func (s mavenString) isNull() bool { return s == "" }
+
+func (s mavenString) String() string { return string(s) }
+
+func (s mavenString) compare(o mavenItem) int {
+	switch o := o.(type) {
+	case nil:
+		// 1-rc < 1, 1-sp > 1
+		return strings.Compare(comparableQualifier(string(s)),
+			comparableQualifier(""))
+	case mavenString:
+		return strings.Compare(comparableQualifier(string(s)),
+			comparableQualifier(string(o)))
+	}
+	// 1.any < 1.1 and 1.any < 1-1
+	return -1
+}
+
+// comparableQualifier returns a key that orders qualifiers: the position
+// of a well-known qualifier, or past them all followed by the qualifier
+func comparableQualifier(q string) string {
+	for i, known := range mavenQualifiers {
+		if q == known {
+			return string(rune('0' + i))
+		}
+	}
+	return string(rune('0'+len(mavenQualifiers))) + "-" + q
+}
+
+func (l *mavenList) isNull() bool { return len(l.items) == 0 }
+
+func (l *mavenList) String() string {
+	var b strings.Builder
+	for _, item := range l.items {
+		if b.Len() > 0 {
+			if _, ok := item.(*mavenList); ok {
+				b.WriteByte('-')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteString(item.String())
+	}
+	return b.String()
+}
+
+func (l *mavenList) compare(o mavenItem) int {
+	switch o := o.(type) {
+	case nil:
+		for _, item := range l.items {
+			if c := item.compare(nil); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case mavenInt:
+		// 1-1 < 1.0.x
+		return -1
+	case mavenString:
+		// 1-1 > 1-sp
+		return 1
+	case *mavenList:
+		for i := 0; i < len(l.items) || i < len(o.items); i++ {
+			var left, right mavenItem
+			if i < len(l.items) {
+				left = l.items[i]
+			}
+			if i < len(o.items) {
+				right = o.items[i]
+			}
+			var c int
+			if left == nil {
+				c = -right.compare(nil)
+			} else {
+				c = left.compare(right)
+			}
+			if c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
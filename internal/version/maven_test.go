@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package version
+
+import "testing"
+
+// Ascending version sequences from Maven's own ComparableVersion tests
+var (
+	mavenQualifierOrder = []string{"1-alpha2snapshot", "1-alpha2", "1-alpha-123",
+		"1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2", "1-rc123",
+		"1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def",
+		"1-pom-1", "1-1-snapshot", "1-1", "1-2", "1-123"}
+	mavenNumberOrder = []string{"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123",
+		"2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1", "2.1.0.1", "2.2", "2.123",
+		"11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a",
+		"11b", "11c", "11m"}
+)
+
+func TestMavenOrder(t *testing.T) {
+	for _, order := range [][]string{mavenQualifierOrder, mavenNumberOrder} {
+		for i := range order {
+			for j := range order {
+				want := compareUint(uint64(i), uint64(j))
+				if got := CompareMaven(order[i], order[j]); got != want {
+					t.Errorf("CompareMaven(%q, %q) = %d, want %d",
+						order[i], order[j], got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestMavenEquality(t *testing.T) {
+	groups := [][]string{
+		{"1", "1.0", "1.0.0", "1-0", "1.0-0", "1ga", "1.ga", "1-ga", "1.0.GA",
+			"1final", "1-FINAL", "1.0.0.RELEASE", "1release"},
+		{"1a1", "1-a1", "1alpha1", "1-alpha-1", "1.0-ALPHA1"},
+		{"1cr", "1rc", "1-CR", "1.0-RC"},
+		{"1m3", "1-milestone-3", "1.0-M3"},
+		{"1.2.3-SNAPSHOT", "1.2.3-snapshot"},
+	}
+	for _, group := range groups {
+		first := ParseMaven(group[0])
+		for _, v := range group[1:] {
+			other := ParseMaven(v)
+			if first.Compare(other) != 0 || first.String() != other.String() {
+				t.Errorf("%q (%s) should equal %q (%s)", group[0], first, v, other)
+			}
+		}
+	}
+}
+
+func TestMavenJavaVersions(t *testing.T) {
+	// Orderings commonly seen in Java projects that differ from semver
+	ascending := []string{"1.0-alpha-2", "1.0-beta", "2.0-M1", "2.0-RC1",
+		"2.0-SNAPSHOT", "2.0", "2.0.Final", "2.0-sp1", "2.0.1"}
+	for i := 0; i+1 < len(ascending); i++ {
+		if c := CompareMaven(ascending[i], ascending[i+1]); c > 0 {
+			t.Errorf("%s should not sort after %s", ascending[i], ascending[i+1])
+		}
+	}
+	if CompareMaven("2.0", "2.0.Final") != 0 {
+		t.Error("2.0.Final should equal 2.0")
+	}
+	if got := ParseMaven("1.0.0-alpha1").String(); got != "1-alpha-1" {
+		t.Errorf("String() = %q, want 1-alpha-1", got)
+	}
+}
+
+func TestMavenSnapshot(t *testing.T) {
+	for v, want := range map[string]bool{
+		"1.2.3-SNAPSHOT":        true,
+		"1.2.3-snapshot":        true,
+		"1.0-20240115.103000-3": true,
+		"1.2.3":                 false,
+		"1.2.3-RC1":             false,
+	} {
+		if got := ParseMaven(v).IsSnapshot(); got != want {
+			t.Errorf("IsSnapshot(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestMavenVersion(t *testing.T) {
+	tests := []struct {
+		input      string
+		prerelease bool
+		fields     string
+	}{
+		{"1.2.3.Final", false, ""},
+		{"2.0.0.RELEASE", false, ""},
+		{"1.2.3-SNAPSHOT", true, "SNAPSHOT"},
+		{"2.0-M1", true, "M1"},
+		{"2.0-sp1", false, "sp1"},
+	}
+	for _, tt := range tests {
+		v, err := ParseMaven(tt.input).Version()
+		if err != nil {
+			t.Fatalf("Version() of %s failed: %v", tt.input, err)
+		}
+		if v.Scheme != SchemeMaven || v.Maven() == nil {
+			t.Errorf("%s: expected a maven version, got %+v", tt.input, v)
+		}
+		if v.IsPrerelease() != tt.prerelease || v.Fields().Prerelease != tt.fields {
+			t.Errorf("%s: prerelease %v %q, want %v %q", tt.input,
+				v.IsPrerelease(), v.Fields().Prerelease, tt.prerelease, tt.fields)
+		}
+	}
+
+	// Maven versions order in Maven order; generic versions by semver rules
+	a, _ := ParseMaven("2.0.Final").Version()
+	b, _ := ParseMaven("2.0").Version()
+	c, _ := ParseMaven("2.0-RC1").Version()
+	if a.Compare(b) != 0 || c.Compare(b) >= 0 {
+		t.Error("unexpected Maven ordering of generic versions")
+	}
+}
@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440Re is the version pattern from PEP 440 Appendix B, which accepts
+// every permitted spelling of a version so it can be normalised
+var pep440Re = regexp.MustCompile(`(?i)^\s*v?` +
+	`(?:(?P<epoch>[0-9]+)!)?` +
+	`(?P<release>[0-9]+(?:\.[0-9]+)*)` +
+	`(?P<pre>[-_.]?(?P<pre_l>alpha|beta|preview|pre|a|b|c|rc)[-_.]?(?P<pre_n>[0-9]+)?)?` +
+	`(?P<post>(?:-(?P<post_n1>[0-9]+))|(?:[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>[0-9]+)?))?` +
+	`(?P<dev>[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>[0-9]+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?\s*$`)
+
+// pep440Labels maps pre-release spellings to their normal form
+var pep440Labels = map[string]string{
+	"a": "a", "alpha": "a",
+	"b": "b", "beta": "b",
+	"c": "rc", "rc": "rc", "pre": "rc", "preview": "rc",
+}
+
+// semverLabels maps normalised PEP 440 pre-release labels to the
+// identifiers used in their Semantic Versioning equivalent
+var semverLabels = map[string]string{"a": "alpha", "b": "beta", "rc": "rc"}
+
+// PEP440 is a Python package version as specified by PEP 440
+type PEP440 struct {
+	Epoch    uint64
+	Release  []uint64
+	Pre      string // "a", "b" or "rc"; empty when not a pre-release
+	PreN     uint64
+	HasPost  bool
+	PostN    uint64
+	HasDev   bool
+	DevN     uint64
+	Local    []string // Local version label segments, lower case
+	Original string
+}
+
+// ParsePEP440 parses a PEP 440 version, accepting the alternative spellings
+// the specification permits, such as 1.0-RC1, 1.0.post-2 or 1.0_dev
+func ParsePEP440(s string) (*PEP440, error) {
+	m := pep440Re.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid PEP 440 version %q", s)
+	}
+	group := func(name string) string {
+		return m[pep440Re.SubexpIndex(name)]
+	}
+
+	p := &PEP440{Original: s}
+	var err error
+	if epoch := group("epoch"); epoch != "" {
+		if p.Epoch, err = strconv.ParseUint(epoch, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid PEP 440 version %q: %w", s, err)
+		}
+	}
+	for _, part := range strings.Split(group("release"), ".") {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PEP 440 version %q: %w", s, err)
+		}
+		p.Release = append(p.Release, n)
+	}
+
+	// An omitted pre-release, post-release or development number is zero.
+	number := func(text string) uint64 {
+		n, _ := strconv.ParseUint(text, 10, 64)
+		return n
+	}
+	if label := group("pre_l"); label != "" {
+		p.Pre = pep440Labels[strings.ToLower(label)]
+		p.PreN = number(group("pre_n"))
+	}
+	if group("post") != "" {
+		p.HasPost = true
+		p.PostN = number(group("post_n1") + group("post_n2"))
+	}
+	if group("dev") != "" {
+		p.HasDev = true
+		p.DevN = number(group("dev_n"))
+	}
+	if local := group("local"); local != "" {
+		p.Local = strings.FieldsFunc(strings.ToLower(local), func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+	}
+	return p, nil
+}
+
+// IsPrerelease reports whether the version is a pre-release or development
+// release
+func (p *PEP440) IsPrerelease() bool {
+	return p.Pre != "" || p.HasDev
+}
+
+// String returns the canonical normalised form of the version, e.g. 1.0rc1
+// for 1.0-RC1 and 1.0.post0 for 1.0-r
+func (p *PEP440) String() string {
+	var b strings.Builder
+	if p.Epoch != 0 {
+		fmt.Fprintf(&b, "%d!", p.Epoch)
+	}
+	b.WriteString(joinRelease(p.Release))
+	if p.Pre != "" {
+		fmt.Fprintf(&b, "%s%d", p.Pre, p.PreN)
+	}
+	if p.HasPost {
+		fmt.Fprintf(&b, ".post%d", p.PostN)
+	}
+	if p.HasDev {
+		fmt.Fprintf(&b, ".dev%d", p.DevN)
+	}
+	if len(p.Local) > 0 {
+		b.WriteString("+" + strings.Join(p.Local, "."))
+	}
+	return b.String()
+}
+
+// Semver returns the Semantic Versioning equivalent of the version: the
+// release padded to three numbers, pre-releases as alpha.N, beta.N or rc.N,
+// development releases as dev.N, and post-release and local labels as build
+// metadata. 1.0.dev4+local.7 becomes 1.0.0-dev.4+local.7. Semantic
+// Versioning has no counterpart for an epoch or more than three release
+// numbers, and post-releases lose their precedence over the release.
+func (p *PEP440) Semver() (string, error) {
+	if p.Epoch != 0 {
+		return "", fmt.Errorf("PEP 440 version %s has an epoch, which has no "+
+			"semver equivalent", p)
+	}
+	if len(p.Release) > 3 {
+		return "", fmt.Errorf("PEP 440 version %s has more than three release "+
+			"numbers, which has no semver equivalent", p)
+	}
+
+	release := append([]uint64(nil), p.Release...)
+	for len(release) < 3 {
+		release = append(release, 0)
+	}
+	s := joinRelease(release)
+	if pre := p.semverPrerelease(); len(pre) > 0 {
+		s += "-" + strings.Join(pre, ".")
+	}
+	if build := p.semverBuild(); len(build) > 0 {
+		s += "+" + strings.Join(build, ".")
+	}
+	return s, nil
+}
+
+// Version returns the version in the generic form, with the pre-release
+// and build identifiers of its semver equivalent
+func (p *PEP440) Version() *Version {
+	v := &Version{
+		Release:    append([]uint64(nil), p.Release...),
+		Prerelease: p.semverPrerelease(),
+		Build:      p.semverBuild(),
+		Original:   p.Original,
+		Scheme:     SchemePEP440,
+		pep440:     p,
+	}
+	for i, field := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if i < len(v.Release) {
+			*field = v.Release[i]
+		}
+	}
+	return v
+}
+
+// pep440PreRanks orders the normalised pre-release labels
+var pep440PreRanks = map[string]uint64{"a": 0, "b": 1, "rc": 2}
+
+// Compare returns -1, 0 or +1 as p has lower, equal or higher precedence
+// than o, following PEP 440: epochs compare first, then release numbers,
+// with missing components counting as zero. Within a release a development
+// release comes before a pre-release, which comes before the release, which
+// comes before its post-releases; 1.0a1.dev1 comes before 1.0a1 and
+// 1.0.post1.dev1 before 1.0.post1. A local version label sorts after the
+// same version without one.
+func (p *PEP440) Compare(o *PEP440) int {
+	if c := compareUint(p.Epoch, o.Epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(p.Release) || i < len(o.Release); i++ {
+		if c := compareUint(component(p.Release, i), component(o.Release, i)); c != 0 {
+			return c
+		}
+	}
+	if c := compareInts(p.preKey(), o.preKey()); c != 0 {
+		return c
+	}
+	if c := compareInts(p.postKey(), o.postKey()); c != 0 {
+		return c
+	}
+	if c := compareInts(p.devKey(), o.devKey()); c != 0 {
+		return c
+	}
+	return compareLocal(p.Local, o.Local)
+}
+
+// preKey ranks the pre-release phase: a development release of the final
+// release first, then pre-releases by label and number, then the rest
+func (p *PEP440) preKey() []uint64 {
+	switch {
+	case p.Pre != "":
+		return []uint64{1, pep440PreRanks[p.Pre], p.PreN}
+	case p.HasDev && !p.HasPost:
+		return []uint64{0}
+	}
+	return []uint64{2}
+}
+
+// postKey ranks post-releases after versions that are none
+func (p *PEP440) postKey() []uint64 {
+	if p.HasPost {
+		return []uint64{1, p.PostN}
+	}
+	return []uint64{0}
+}
+
+// devKey ranks development releases before versions that are none
+func (p *PEP440) devKey() []uint64 {
+	if p.HasDev {
+		return []uint64{0, p.DevN}
+	}
+	return []uint64{1}
+}
+
+// compareInts compares two keys element by element, a shorter key coming
+// first when it is a prefix of the other
+func compareInts(a, b []uint64) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareUint(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(a)), uint64(len(b)))
+}
+
+// compareLocal compares local version labels: segment by segment, numeric
+// segments numerically and after alphanumeric ones, which compare as
+// strings; a label that is a prefix of the other comes first
+func compareLocal(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		na, errA := strconv.ParseUint(a[i], 10, 64)
+		nb, errB := strconv.ParseUint(b[i], 10, 64)
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = compareUint(na, nb)
+		case errA == nil:
+			c = 1
+		case errB == nil:
+			c = -1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(a)), uint64(len(b)))
+}
+
+// semverPrerelease returns the semver pre-release identifiers
+func (p *PEP440) semverPrerelease() []string {
+	var pre []string
+	if p.Pre != "" {
+		pre = append(pre, semverLabels[p.Pre], strconv.FormatUint(p.PreN, 10))
+	}
+	if p.HasDev {
+		pre = append(pre, "dev", strconv.FormatUint(p.DevN, 10))
+	}
+	return pre
+}
+
+// semverBuild returns the semver build metadata identifiers
+func (p *PEP440) semverBuild() []string {
+	var build []string
+	if p.HasPost {
+		build = append(build, "post", strconv.FormatUint(p.PostN, 10))
+	}
+	return append(build, p.Local...)
+}
+
+// joinRelease formats release numbers as a dotted string
+func joinRelease(release []uint64) string {
+	parts := make([]string, len(release))
+	for i, n := range release {
+		parts[i] = strconv.FormatUint(n, 10)
+	}
+	return strings.Join(parts, ".")
+}
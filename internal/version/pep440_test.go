@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package version
+
+import "testing"
+
+func TestParsePEP440(t *testing.T) {
+	tests := []struct {
+		input      string
+		canonical  string
+		semver     string
+		prerelease bool
+	}{
+		{"1.0", "1.0", "1.0.0", false},
+		{"v1.2.3", "1.2.3", "1.2.3", false},
+		{"1.0rc1", "1.0rc1", "1.0.0-rc.1", true},
+		{"1.0-RC1", "1.0rc1", "1.0.0-rc.1", true},
+		{"1.0.alpha.2", "1.0a2", "1.0.0-alpha.2", true},
+		{"1.0b", "1.0b0", "1.0.0-beta.0", true},
+		{"1.0c3", "1.0rc3", "1.0.0-rc.3", true},
+		{"1.0preview1", "1.0rc1", "1.0.0-rc.1", true},
+		{"2.0.post3", "2.0.post3", "2.0.0+post.3", false},
+		{"2.0-3", "2.0.post3", "2.0.0+post.3", false},
+		{"2.0.rev", "2.0.post0", "2.0.0+post.0", false},
+		{"1.0.dev4+local.7", "1.0.dev4+local.7", "1.0.0-dev.4+local.7", true},
+		{"1.0rc1.dev2", "1.0rc1.dev2", "1.0.0-rc.1.dev.2", true},
+		{"1.0_dev", "1.0.dev0", "1.0.0-dev.0", true},
+		{"1.0+Ubuntu-1_2", "1.0+ubuntu.1.2", "1.0.0+ubuntu.1.2", false},
+		{"1.2.4.dev7+g1a2b3c4", "1.2.4.dev7+g1a2b3c4", "1.2.4-dev.7+g1a2b3c4", true},
+		{"01.02", "1.2", "1.2.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := ParsePEP440(tt.input)
+			if err != nil {
+				t.Fatalf("ParsePEP440(%q) failed: %v", tt.input, err)
+			}
+			if got := p.String(); got != tt.canonical {
+				t.Errorf("String() = %q, want %q", got, tt.canonical)
+			}
+			semver, err := p.Semver()
+			if err != nil || semver != tt.semver {
+				t.Errorf("Semver() = %q, %v; want %q", semver, err, tt.semver)
+			}
+			if p.IsPrerelease() != tt.prerelease {
+				t.Errorf("IsPrerelease() = %v, want %v", p.IsPrerelease(), tt.prerelease)
+			}
+
+			// The semver equivalent must itself be a valid semver version
+			if v, err := Parse(semver); err != nil || v.Scheme != SchemeSemver {
+				t.Errorf("semver equivalent %q does not parse as semver: %v", semver, err)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "latest", "1.0-", "1.0+", "1.0.x", "1..0", "1.0 beta"} {
+		if _, err := ParsePEP440(bad); err == nil {
+			t.Errorf("ParsePEP440(%q) should fail", bad)
+		}
+	}
+}
+
+func TestPEP440WithoutSemver(t *testing.T) {
+	for _, input := range []string{"1!1.0", "1.2.3.4"} {
+		p, err := ParsePEP440(input)
+		if err != nil {
+			t.Fatalf("ParsePEP440(%q) failed: %v", input, err)
+		}
+		if _, err := p.Semver(); err == nil {
+			t.Errorf("Semver() of %s should fail", input)
+		}
+	}
+
+	p, _ := ParsePEP440("1!1.0")
+	if p.Epoch != 1 || p.String() != "1!1.0" {
+		t.Errorf("unexpected epoch version: %+v", p)
+	}
+}
+
+func TestParseRecognisesPEP440(t *testing.T) {
+	v, err := Parse("2.0.post3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Scheme != SchemePEP440 || v.PEP440() == nil || v.String() != "2.0.post3" ||
+		v.Fields().Build != "post.3" {
+		t.Errorf("unexpected version: %+v", v)
+	}
+}
+
+func TestPEP440Compare(t *testing.T) {
+	// Each version has lower precedence than the next
+	ordered := []string{
+		"1.0.dev0",
+		"1.0.dev1",
+		"1.0a1.dev1",
+		"1.0a1",
+		"1.0a2",
+		"1.0b1",
+		"1.0rc1",
+		"1.0rc1+abc",
+		"1.0rc1+local.1",
+		"1.0rc1+local.2",
+		"1.0rc1+local.2.x",
+		"1.0rc1+5",
+		"1.0",
+		"1.0.post1.dev1",
+		"1.0.post1",
+		"1.0.post2",
+		"1.1.dev0",
+		"1.1",
+		"2.0",
+		"1!1.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		lower, higher := ordered[i], ordered[i+1]
+		if c, err := Compare(lower, higher); err != nil || c != -1 {
+			t.Errorf("Compare(%s, %s) = %d, %v; want -1", lower, higher, c, err)
+		}
+		if c, err := Compare(higher, lower); err != nil || c != 1 {
+			t.Errorf("Compare(%s, %s) = %d, %v; want 1", higher, lower, c, err)
+		}
+	}
+
+	equal := [][2]string{
+		{"1.0", "1.0.0"},
+		{"1.0rc1", "1.0.0-rc.1"},
+		{"1.0.post0", "1.0-r"},
+		{"1.0rc1+abc", "1.0rc1+ABC"},
+	}
+	for _, pair := range equal {
+		if c, err := Compare(pair[0], pair[1]); err != nil || c != 0 {
+			t.Errorf("Compare(%s, %s) = %d, %v; want 0", pair[0], pair[1], c, err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Patterns of the validation schemes that are a single regular expression.
+// Extracted versions have had any leading "v" removed already.
+var (
+	// Maven accepts any dotted or hyphenated mix of numbers and qualifiers
+	// that starts with a number, e.g. 1.0-SNAPSHOT or 2.0.0.RELEASE
+	mavenRuleRe = regexp.MustCompile(`^[0-9][0-9A-Za-z_]*(?:[.-][0-9A-Za-z_]+)*$`)
+	// Debian versions are [epoch:]upstream[-revision]
+	debianRuleRe = regexp.MustCompile(`^(?:[0-9]+:)?[0-9][0-9A-Za-z.+~]*(?:-[0-9A-Za-z.+~]+)*$`)
+	// RPM versions may not contain a hyphen other than before the release
+	rpmRuleRe = regexp.MustCompile(`^(?:[0-9]+:)?[0-9][0-9A-Za-z._+~^]*(?:-[0-9A-Za-z._+~^]+)?$`)
+	// Loose versions have at least a major and minor number, then any
+	// pre-release or build suffix, e.g. 1.2, 3.2.0.dev or 2.0-SNAPSHOT
+	looseRuleRe = regexp.MustCompile(`^[0-9]+(?:\.[0-9]+)+(?:[-.+_~]?[0-9A-Za-z]+)*$`)
+	// CalVer without a format: a four digit year and up to three numbers
+	calverRuleRe = regexp.MustCompile(`^[12][0-9]{3}(?:\.[0-9]+){0,3}` + calverModifier + `$`)
+)
+
+// calverModifier is the optional tag that may follow a CalVer version, e.g.
+// the "-rc1" of 2024.05-rc1
+const calverModifier = `(?:-[0-9A-Za-z][0-9A-Za-z.-]*)?`
+
+// calverTokens maps the CalVer format tokens from calver.org to patterns,
+// longest first so that e.g. YYYY is preferred to YY
+var calverTokens = []struct{ token, pattern string }{
+	{"MAJOR", `[0-9]+`},
+	{"MINOR", `[0-9]+`},
+	{"MICRO", `[0-9]+`},
+	{"PATCH", `[0-9]+`},
+	{"YYYY", `[1-9][0-9]{3}`},
+	{"YY", `(?:0|[1-9][0-9]{0,2})`},
+	{"0Y", `[0-9]{2,3}`},
+	{"MM", `(?:[1-9]|1[0-2])`},
+	{"0M", `(?:0[1-9]|1[0-2])`},
+	{"WW", `(?:[0-9]|[1-4][0-9]|5[0-3])`},
+	{"0W", `(?:0[0-9]|[1-4][0-9]|5[0-3])`},
+	{"DD", `(?:[1-9]|[12][0-9]|3[01])`},
+	{"0D", `(?:0[1-9]|[12][0-9]|3[01])`},
+}
+
+// SchemeRule validates versions against the versioning scheme named by a
+// version_scheme setting
+type SchemeRule struct {
+	spec  string
+	match func(string) bool
+}
+
+// schemeRules caches parsed rules by spec, since a rule is looked up for
+// every version matched for its project type
+var schemeRules sync.Map
+
+// SchemeRuleNames returns the accepted forms of a version_scheme setting
+func SchemeRuleNames() []string {
+	return []string{"semver", "pep440", "maven", "calver", "calver:FORMAT",
+		"debian", "rpm", "loose", "regex:PATTERN"}
+}
+
+// ParseSchemeRule parses a version_scheme setting: one of semver, pep440,
+// maven, calver, debian, rpm or loose; calver:FORMAT for CalVer with a
+// calver.org format such as YYYY.0M.MICRO; or regex:PATTERN for a custom
+// regular expression, which must match the whole version.
+func ParseSchemeRule(spec string) (*SchemeRule, error) {
+	if cached, ok := schemeRules.Load(spec); ok {
+		return cached.(*SchemeRule), nil
+	}
+
+	name, arg, hasArg := strings.Cut(spec, ":")
+	rule := &SchemeRule{spec: spec}
+	switch {
+	case hasArg && name == "calver":
+		re, err := calverFormatRe(arg)
+		if err != nil {
+			return nil, err
+		}
+		rule.match = re.MatchString
+	case hasArg && name == "regex":
+		re, err := regexp.Compile(`^(?:` + arg + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version scheme regex %q: %w", arg, err)
+		}
+		rule.match = re.MatchString
+	case hasArg:
+		return nil, fmt.Errorf("version scheme %q does not take an argument", name)
+	case name == "semver":
+		rule.match = semverRe.MatchString
+	case name == "pep440":
+		rule.match = func(v string) bool {
+			_, err := ParsePEP440(v)
+			return err == nil
+		}
+	case name == "maven":
+		rule.match = mavenRuleRe.MatchString
+	case name == "calver":
+		rule.match = calverRuleRe.MatchString
+	case name == "debian":
+		rule.match = debianRuleRe.MatchString
+	case name == "rpm":
+		rule.match = rpmRuleRe.MatchString
+	case name == "loose":
+		rule.match = looseRuleRe.MatchString
+	default:
+		return nil, fmt.Errorf("unknown version scheme %q (expected one of: %s)",
+			spec, strings.Join(SchemeRuleNames(), ", "))
+	}
+
+	schemeRules.Store(spec, rule)
+	return rule, nil
+}
+
+// Match reports whether v is a valid version in the rule's scheme
+func (r *SchemeRule) Match(v string) bool {
+	return v != "" && r.match(v)
+}
+
+// String returns the version_scheme setting the rule was parsed from
+func (r *SchemeRule) String() string {
+	return r.spec
+}
+
+// calverFormatRe compiles a calver.org format such as YYYY.0M.0D into a
+// pattern. Characters other than tokens must appear literally, and the
+// version may end with a modifier such as -rc1.
+func calverFormatRe(format string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	tokens := 0
+	for rest := format; rest != ""; {
+		matched := false
+		for _, t := range calverTokens {
+			if strings.HasPrefix(rest, t.token) {
+				b.WriteString(t.pattern)
+				rest = rest[len(t.token):]
+				tokens++
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		c := rest[0]
+		if c >= 'A' && c <= 'Z' {
+			return nil, fmt.Errorf("invalid calver format %q: unknown token at %q",
+				format, rest)
+		}
+		b.WriteString(regexp.QuoteMeta(string(c)))
+		rest = rest[1:]
+	}
+	if tokens == 0 {
+		return nil, fmt.Errorf("invalid calver format %q: no tokens", format)
+	}
+	b.WriteString(calverModifier + "$")
+	return regexp.Compile(b.String())
+}
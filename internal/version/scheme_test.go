@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package version
+
+import "testing"
+
+func TestSchemeRuleMatch(t *testing.T) {
+	tests := []struct {
+		spec  string
+		valid []string
+		bad   []string
+	}{
+		{"semver", []string{"1.2.3", "1.0.0-rc.1+build.5"},
+			[]string{"1.2", "01.2.3", "1.0rc1", "latest"}},
+		{"pep440", []string{"1.0", "1.0rc1", "2.0.post3", "1!1.0.dev4+local.7"},
+			[]string{"1.0-SNAPSHOT", "latest"}},
+		{"maven", []string{"1.0", "1.0-SNAPSHOT", "2.0.0.RELEASE", "1.0-beta-1"},
+			[]string{"${revision}", "latest", "1.0+build"}},
+		{"calver", []string{"2024", "2024.05", "2024.05.15", "2024.5.1-rc1"},
+			[]string{"24.05", "1.2.3", "2024.05.15.1.2"}},
+		{"calver:YYYY.0M.0D", []string{"2024.05.15", "2024.12.01-beta"},
+			[]string{"2024.5.15", "2024.13.01", "2024.05", "24.05.15"}},
+		{"calver:YY.MM.MICRO", []string{"24.5.0", "6.12.3"},
+			[]string{"2024.5.0", "24.05.0"}},
+		{"debian", []string{"1.2.3", "1:2.30-1ubuntu2", "2.0~rc1-3"},
+			[]string{"v1.0", "1.0-", "latest"}},
+		{"rpm", []string{"2.4.1", "2.4.1-3.el9", "1.0~rc1"},
+			[]string{"1.0-1-2", "latest"}},
+		{"loose", []string{"1.2", "3.2.0.dev", "2.0-SNAPSHOT", "1.2.3-rc.1+b.5"},
+			[]string{"2024", "1", "latest"}},
+		{`regex:[0-9]+-[a-z]+`, []string{"12-alpha"},
+			[]string{"12-alpha-1", "x12-alpha"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			rule, err := ParseSchemeRule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchemeRule(%q) failed: %v", tt.spec, err)
+			}
+			if rule.String() != tt.spec {
+				t.Errorf("String() = %q, want %q", rule.String(), tt.spec)
+			}
+			for _, v := range tt.valid {
+				if !rule.Match(v) {
+					t.Errorf("%s should accept %q", tt.spec, v)
+				}
+			}
+			for _, v := range append(tt.bad, "") {
+				if rule.Match(v) {
+					t.Errorf("%s should reject %q", tt.spec, v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSchemeRuleInvalid(t *testing.T) {
+	for _, spec := range []string{"", "sem-ver", "semver:2", "calver:", "calver:YYY.MM",
+		"calver:.-", "regex:(unclosed"} {
+		if _, err := ParseSchemeRule(spec); err == nil {
+			t.Errorf("ParseSchemeRule(%q) should fail", spec)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+// Package version parses extracted version strings into their components and
+// orders them by precedence.
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Scheme names the versioning convention a version was recognised as
+type Scheme string
+
+// Detected version schemes
+const (
+	// SchemeSemver is Semantic Versioning 2.0.0, e.g. 1.2.3-rc.1+build.5
+	SchemeSemver Scheme = "semver"
+	// SchemeCalVer is a date-based version, e.g. 2024.01.15
+	SchemeCalVer Scheme = "calver"
+	// SchemePEP440 is a Python package version, e.g. 1.0rc1 or 3.2.0.dev
+	SchemePEP440 Scheme = "pep440"
+	// SchemeLoose is any other dotted numeric version, e.g. 1.2, 1.2.3.4 or
+	// 2.0-SNAPSHOT
+	SchemeLoose Scheme = "loose"
+	// SchemeMaven is a version from a Maven or Gradle build, ordered as
+	// Maven orders it, e.g. 2.0-M1 or 1.2.3.Final
+	SchemeMaven Scheme = "maven"
+)
+
+// Version recognition patterns. The leading "v" is removed before matching.
+var (
+	// Official Semantic Versioning pattern from semver.org
+	semverRe = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+	// Date-based versions: a four digit year, then two digit components
+	calverRe = regexp.MustCompile(`^((?:19|2[0-9])[0-9]{2}(?:\.[0-9]{2})*)$`)
+	// Python-style versions with a dotted label, e.g. 3.2.0.dev or 1.0.0.alpha1
+	pythonStyleRe = regexp.MustCompile(`^([0-9]+\.[0-9]+\.[0-9]+)\.([a-zA-Z][0-9a-zA-Z]*)$`)
+	// Up to four numeric components with optional pre-release and build
+	looseRe = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+){0,3})(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+)
+
+// Version is a parsed version string
+type Version struct {
+	Major uint64
+	Minor uint64
+	Patch uint64
+	// Release holds every numeric release component; Major, Minor and Patch
+	// are its first three, or zero when the version has fewer.
+	Release    []uint64
+	Prerelease []string // Dot-separated pre-release identifiers
+	Build      []string // Dot-separated build metadata identifiers
+	Original   string   // The text the version was parsed from
+	Scheme     Scheme
+
+	pep440 *PEP440       // The PEP 440 form of a pep440 version
+	maven  *MavenVersion // The Maven form of a maven version
+}
+
+// Parse parses a version string, recognising Semantic Versioning, CalVer,
+// loose dotted numeric versions and PEP 440 versions, in that order. A
+// leading "v" or "V" is ignored.
+func Parse(s string) (*Version, error) {
+	original := s
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "v") || strings.HasPrefix(s, "V") {
+		s = s[1:]
+	}
+
+	var (
+		release, pre, build string
+		scheme              Scheme
+	)
+	switch {
+	case calverRe.MatchString(s):
+		release, scheme = s, SchemeCalVer
+	case semverRe.MatchString(s):
+		m := looseRe.FindStringSubmatch(s)
+		release, pre, build, scheme = m[1], m[2], m[3], SchemeSemver
+	case looseRe.MatchString(s):
+		m := looseRe.FindStringSubmatch(s)
+		release, pre, build, scheme = m[1], m[2], m[3], SchemeLoose
+	default:
+		if p, err := ParsePEP440(s); err == nil {
+			p.Original = original
+			return p.Version(), nil
+		}
+		// Python-style labels outside PEP 440, such as 1.0.0.final
+		m := pythonStyleRe.FindStringSubmatch(s)
+		if m == nil {
+			return nil, fmt.Errorf("invalid version %q", original)
+		}
+		release, pre, scheme = m[1], m[2], SchemeLoose
+	}
+
+	v := &Version{Original: original, Scheme: scheme}
+	for _, part := range strings.Split(release, ".") {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", original, err)
+		}
+		v.Release = append(v.Release, n)
+	}
+	for i, field := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if i < len(v.Release) {
+			*field = v.Release[i]
+		}
+	}
+	if pre != "" {
+		v.Prerelease = strings.Split(pre, ".")
+	}
+	if build != "" {
+		v.Build = strings.Split(build, ".")
+	}
+	return v, nil
+}
+
+// IsPrerelease reports whether the version has pre-release identifiers. A
+// maven version is a pre-release when it is a snapshot or Maven orders it
+// before its release numbers, as for 2.0-M1 but not 2.0-sp1.
+func (v *Version) IsPrerelease() bool {
+	if v.maven != nil {
+		return v.maven.IsSnapshot() ||
+			v.maven.Compare(ParseMaven(joinRelease(v.Release))) < 0
+	}
+	return len(v.Prerelease) > 0
+}
+
+// PEP440 returns the PEP 440 form of a pep440 version, or nil
+func (v *Version) PEP440() *PEP440 {
+	return v.pep440
+}
+
+// Maven returns the Maven form of a maven version, or nil
+func (v *Version) Maven() *MavenVersion {
+	return v.maven
+}
+
+// String returns the version in its normalised form: the release numbers
+// without leading zeros or a "v" prefix, then any pre-release and build.
+// PEP 440 versions use their canonical form.
+func (v *Version) String() string {
+	if v.pep440 != nil {
+		return v.pep440.String()
+	}
+
+	s := joinRelease(v.Release)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if len(v.Build) > 0 {
+		s += "+" + strings.Join(v.Build, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or +1 as v has lower, equal or higher precedence
+// than o, following Semantic Versioning: release numbers compare
+// numerically, with missing components counting as zero; a pre-release has
+// lower precedence than its release; pre-release identifiers compare
+// numerically when both are numeric, otherwise as ASCII, numeric ones first;
+// and build metadata is ignored. Two maven versions compare in Maven order
+// instead. A pep440 version compares with another version that has a PEP
+// 440 form, such as 1.0 or 1.0.0-rc.1, in PEP 440 order.
+func (v *Version) Compare(o *Version) int {
+	if v.maven != nil && o.maven != nil {
+		return v.maven.Compare(o.maven)
+	}
+	if v.pep440 != nil || o.pep440 != nil {
+		if p, q := v.pep440Form(), o.pep440Form(); p != nil && q != nil {
+			return p.Compare(q)
+		}
+	}
+
+	for i := 0; i < len(v.Release) || i < len(o.Release); i++ {
+		if c := compareUint(component(v.Release, i), component(o.Release, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(o.Prerelease)))
+}
+
+// pep440Form returns the PEP 440 form of the version, or nil when it has
+// none
+func (v *Version) pep440Form() *PEP440 {
+	if v.pep440 != nil {
+		return v.pep440
+	}
+	if v.maven != nil {
+		return nil
+	}
+	p, err := ParsePEP440(v.String())
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// Compare parses and compares two version strings, as Version.Compare
+func Compare(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// component returns release component i, or zero past the end
+func component(release []uint64, i int) uint64 {
+	if i < len(release) {
+		return release[i]
+	}
+	return 0
+}
+
+// compareUint compares two numbers, returning -1, 0 or +1
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compares two pre-release identifiers
+func compareIdentifier(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return compareUint(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// Fields is the flattened form of a Version reported alongside an extracted
+// version, so callers can branch on its parts without parsing it again
+type Fields struct {
+	Major      uint64 `json:"major"`
+	Minor      uint64 `json:"minor"`
+	Patch      uint64 `json:"patch"`
+	Prerelease string `json:"prerelease"`
+	Build      string `json:"build"`
+	Scheme     Scheme `json:"scheme"`
+}
+
+// Fields returns the version's flattened form
+func (v *Version) Fields() *Fields {
+	return &Fields{
+		Major:      v.Major,
+		Minor:      v.Minor,
+		Patch:      v.Patch,
+		Prerelease: strings.Join(v.Prerelease, "."),
+		Build:      strings.Join(v.Build, "."),
+		Scheme:     v.Scheme,
+	}
+}
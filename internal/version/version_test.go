@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input      string
+		release    []uint64
+		prerelease []string
+		build      []string
+		scheme     Scheme
+		str        string
+	}{
+		{"1.2.3", []uint64{1, 2, 3}, nil, nil, SchemeSemver, "1.2.3"},
+		{"v1.2.3-rc.1+build.5", []uint64{1, 2, 3}, []string{"rc", "1"}, []string{"build", "5"}, SchemeSemver, "1.2.3-rc.1+build.5"},
+		{"1.0.0-alpha-2", []uint64{1, 0, 0}, []string{"alpha-2"}, nil, SchemeSemver, "1.0.0-alpha-2"},
+		{"2024.01.15", []uint64{2024, 1, 15}, nil, nil, SchemeCalVer, "2024.1.15"},
+		{"2024.10", []uint64{2024, 10}, nil, nil, SchemeCalVer, "2024.10"},
+		{"3.2.0.dev", []uint64{3, 2, 0}, []string{"dev", "0"}, nil, SchemePEP440, "3.2.0.dev0"},
+		{"1.0rc1", []uint64{1, 0}, []string{"rc", "1"}, nil, SchemePEP440, "1.0rc1"},
+		{"1.0.0.final", []uint64{1, 0, 0}, []string{"final"}, nil, SchemeLoose, "1.0.0-final"},
+		{"1.2", []uint64{1, 2}, nil, nil, SchemeLoose, "1.2"},
+		{"1.2.3.4", []uint64{1, 2, 3, 4}, nil, nil, SchemeLoose, "1.2.3.4"},
+		{"01.2.3", []uint64{1, 2, 3}, nil, nil, SchemeLoose, "1.2.3"},
+		{"2.0-SNAPSHOT", []uint64{2, 0}, []string{"SNAPSHOT"}, nil, SchemeLoose, "2.0-SNAPSHOT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(v.Release, tt.release) ||
+				!reflect.DeepEqual(v.Prerelease, tt.prerelease) ||
+				!reflect.DeepEqual(v.Build, tt.build) || v.Scheme != tt.scheme {
+				t.Errorf("Parse(%q) = %+v", tt.input, v)
+			}
+			if v.Major != tt.release[0] || v.Original != tt.input {
+				t.Errorf("Parse(%q) has major %d, original %q", tt.input, v.Major, v.Original)
+			}
+			if got := v.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "latest", "1..2", "v", "1.2.3-", "99999999999999999999.0.0"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+// TestCompare checks the precedence example from the Semantic Versioning
+// specification, plus release components of different lengths
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1",
+		"1.1", "1.1.0.1", "2.0.0", "v10.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			got, err := Compare(ordered[i], ordered[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	equal := [][2]string{{"1.0.0+build.1", "1.0.0+build.2"}, {"1.2", "1.2.0"}, {"v1.2.3", "1.2.3"}}
+	for _, pair := range equal {
+		if got, _ := Compare(pair[0], pair[1]); got != 0 {
+			t.Errorf("Compare(%s, %s) = %d, want 0", pair[0], pair[1], got)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	v, err := Parse("1.2.3-rc.1+build.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Fields{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1", Build: "build.5", Scheme: SchemeSemver}
+	if got := v.Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %+v, want %+v", got, want)
+	}
+	if !v.IsPrerelease() {
+		t.Error("expected a pre-release")
+	}
+}
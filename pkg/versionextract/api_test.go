@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package versionextract
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/api.txt")
+
+// exportedAPI lists the package's exported declarations, one per entry,
+// with function bodies and unexported struct fields left out
+func exportedAPI(t *testing.T) []string {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	var api []string
+	render := func(node any) {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, node); err != nil {
+			t.Fatal(err)
+		}
+		api = append(api, buf.String())
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if !d.Name.IsExported() || (d.Recv != nil && !receiverExported(d.Recv)) {
+					continue
+				}
+				d.Body = nil
+				render(d)
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						if !s.Name.IsExported() {
+							continue
+						}
+						if st, ok := s.Type.(*ast.StructType); ok {
+							st.Fields.List = exportedFields(st.Fields.List)
+						}
+						render(&ast.GenDecl{Tok: d.Tok, Specs: []ast.Spec{s}})
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							if name.IsExported() {
+								api = append(api, d.Tok.String()+" "+name.Name)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(api)
+	return api
+}
+
+// receiverExported reports whether a method's receiver type is exported
+func receiverExported(recv *ast.FieldList) bool {
+	typ := recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	ident, ok := typ.(*ast.Ident)
+	return ok && ident.IsExported()
+}
+
+// exportedFields drops a struct's unexported fields, keeping embedded
+// exported types
+func exportedFields(fields []*ast.Field) []*ast.Field {
+	var exported []*ast.Field
+	for _, field := range fields {
+		if len(field.Names) == 0 {
+			exported = append(exported, field)
+			continue
+		}
+		var names []*ast.Ident
+		for _, name := range field.Names {
+			if name.IsExported() {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 0 {
+			field.Names = names
+			exported = append(exported, field)
+		}
+	}
+	return exported
+}
+
+// TestAPI guards the package's compatibility promise: the exported API
+// must match testdata/api.txt. After a deliberate, compatible addition, run
+// make api to record it; removals and changes wait for a major version.
+func TestAPI(t *testing.T) {
+	got := strings.Join(exportedAPI(t), "\n\n") + "\n"
+	golden := filepath.Join("testdata", "api.txt")
+
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read the recorded API: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("The exported API differs from %s; if the change is compatible, "+
+			"run make api. Got:\n%s", golden, got)
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package versionextract
+
+import (
+	"errors"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/config"
+)
+
+// Config is a loaded configuration: the patterns that locate versions in
+// project files. It can be shared between extractions with WithConfig.
+type Config struct {
+	config *config.Config
+}
+
+// LoadConfig loads the configuration Extract would use for path: the
+// built-in default patterns, then the .version-extract.yaml found in path or
+// a parent directory up to the top of the git work tree, then the files
+// given with WithConfigFile. An empty path skips the search. Of the options,
+// only WithConfigFile, WithStrictConfig, WithLogger and WithFS apply.
+func LoadConfig(path string, opts ...Option) (*Config, error) {
+	return loadConfig(path, newOptions(opts))
+}
+
+// loadConfig loads the configuration for path as LoadConfig does
+func loadConfig(path string, o *options) (*Config, error) {
+	var layers []string
+	if path != "" {
+		var local string
+		var err error
+		if o.fsys != nil {
+			local, err = config.DiscoverConfigFS(o.fsys, path)
+		} else {
+			local, err = config.DiscoverConfig(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if local != "" {
+			layers = append(layers, local)
+		}
+	}
+	layers = append(layers, o.configFiles...)
+
+	loader := config.NewLoader()
+	loader.SetStrict(o.strictConfig)
+	loader.SetLogger(o.logger)
+	loader.SetFS(o.fsys)
+	cfg, err := loader.LoadDefault(layers...)
+	if err != nil {
+		var invalid *config.ValidationError
+		if errors.As(err, &invalid) {
+			return nil, newConfigError(invalid)
+		}
+		return nil, err
+	}
+	return &Config{config: cfg}, nil
+}
+
+// ProjectTypes lists the project types the configuration supports, in
+// priority order
+func (c *Config) ProjectTypes() []string {
+	return c.config.GetSupportedTypes()
+}
+
+// ConfigError lists every problem strict configuration loading found
+type ConfigError struct {
+	Problems []Problem
+}
+
+// Problem is an issue found in a configuration file, with the line and
+// column it was found at when known
+type Problem struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// newConfigError converts the loader's validation error
+func newConfigError(invalid *config.ValidationError) *ConfigError {
+	e := &ConfigError{}
+	for _, p := range invalid.Problems {
+		e.Problems = append(e.Problems, Problem(p))
+	}
+	return e
+}
+
+// Error lists the problems, one per line
+func (e *ConfigError) Error() string {
+	problems := make([]config.Problem, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		problems = append(problems, config.Problem(p))
+	}
+	return (&config.ValidationError{Problems: problems}).Error()
+}
+
+// String formats the problem as file:line:column: message
+func (p Problem) String() string {
+	return config.Problem(p).String()
+}
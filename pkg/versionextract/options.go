@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package versionextract
+
+import (
+	"io/fs"
+	"log/slog"
+)
+
+// Option configures extraction or configuration loading. Invalid values,
+// such as an unknown tag policy, are reported by Extract and ExtractAll.
+type Option func(*options)
+
+// options holds the settings the options configure
+type options struct {
+	config            *Config
+	configFiles       []string
+	strictConfig      bool
+	dynamicFallback   bool
+	gitVersionStyle   string
+	tagPrefix         string
+	tagPolicy         string
+	excludePrerelease bool
+	gitBackend        string
+	revision          string
+	scheme            string
+	gradleProperties  map[string]string
+	skipDirectories   []string
+	logger            *slog.Logger
+	fsys              fs.FS
+}
+
+// newOptions applies opts over the defaults
+func newOptions(opts []Option) *options {
+	o := &options{dynamicFallback: true}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithConfig extracts with a configuration loaded by LoadConfig, instead of
+// loading one for each extraction
+func WithConfig(cfg *Config) Option {
+	return func(o *options) {
+		o.config = cfg
+	}
+}
+
+// WithConfigFile layers a configuration file over the defaults and the
+// discovered .version-extract.yaml. Files given with several options are
+// layered in order.
+func WithConfigFile(path string) Option {
+	return func(o *options) {
+		o.configFiles = append(o.configFiles, path)
+	}
+}
+
+// WithStrictConfig fails configuration loading with a *ConfigError listing
+// every problem found, instead of logging a warning about each and skipping
+// the entries that cannot be used
+func WithStrictConfig(strict bool) Option {
+	return func(o *options) {
+		o.strictConfig = strict
+	}
+}
+
+// WithDynamicFallback selects whether projects that version dynamically fall
+// back to git tags. It is enabled by default.
+func WithDynamicFallback(enabled bool) Option {
+	return func(o *options) {
+		o.dynamicFallback = enabled
+	}
+}
+
+// WithGitVersionStyle selects how versions taken from git tags describe
+// commits after the tag: "exact" (the default), "semver", "pep440" or
+// "maven-snapshot"
+func WithGitVersionStyle(style string) Option {
+	return func(o *options) {
+		o.gitVersionStyle = style
+	}
+}
+
+// WithTagPrefix restricts the git tag fallback to tags starting with
+// prefix, overriding each project's tag_prefix. The prefix may be a
+// template over the project directory, as for tag_prefix.
+func WithTagPrefix(prefix string) Option {
+	return func(o *options) {
+		o.tagPrefix = prefix
+	}
+}
+
+// WithTagPolicy selects which git tag the fallback takes as the latest:
+// "nearest" (the default), "highest-reachable" or "highest-any"
+func WithTagPolicy(policy string) Option {
+	return func(o *options) {
+		o.tagPolicy = policy
+	}
+}
+
+// WithExcludePrerelease makes the git tag fallback skip pre-release tags
+func WithExcludePrerelease(exclude bool) Option {
+	return func(o *options) {
+		o.excludePrerelease = exclude
+	}
+}
+
+// WithGitBackend selects how git repositories are read: "auto" (the
+// default), "exec" or "native"
+func WithGitBackend(backend string) Option {
+	return func(o *options) {
+		o.gitBackend = backend
+	}
+}
+
+// WithRevision extracts from the files committed at rev, a tag, branch or
+// commit ID, without checking it out. The path given to Extract or
+// ExtractAll is a path on disk within the repository, which need only exist
+// in the revision, and results name files by their path in the revision
+// relative to the top of the work tree. The git tag fallback looks tags up
+// relative to rev instead of HEAD. Configuration is still read from disk.
+func WithRevision(rev string) Option {
+	return func(o *options) {
+		o.revision = rev
+	}
+}
+
+// WithGradleProperty sets a Gradle project property, as gradle -Pname=value
+// does. It takes precedence over gradle.properties files when a build script
+// refers to the property. Repeat it to set several properties.
+func WithGradleProperty(name, value string) Option {
+	return func(o *options) {
+		if o.gradleProperties == nil {
+			o.gradleProperties = make(map[string]string)
+		}
+		o.gradleProperties[name] = value
+	}
+}
+
+// WithScheme validates every matched version against a version scheme,
+// overriding each project's version_scheme, e.g. "semver" or
+// "calver:YYYY.0M.MICRO"
+func WithScheme(scheme string) Option {
+	return func(o *options) {
+		o.scheme = scheme
+	}
+}
+
+// WithSkipDirectories replaces the directories skipped when searching for
+// project files, by default node_modules, vendor, target, build and dist.
+// Hidden directories are always skipped.
+func WithSkipDirectories(dirs ...string) Option {
+	return func(o *options) {
+		o.skipDirectories = append([]string{}, dirs...)
+	}
+}
+
+// WithLogger sets the logger for diagnostics, such as warnings about
+// configuration entries that are skipped. By default they are logged to
+// slog.Default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithFS reads project files from fsys instead of the host file system, so
+// versions can be extracted from a release archive or an fstest.MapFS. The
+// path given to Extract, ExtractAll or LoadConfig is then a path in fsys,
+// such as "." for its root, and configuration files, including those given
+// with WithConfigFile, are read from fsys too. The git tag fallback needs a
+// repository on disk, so it finds no tags.
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fsys = fsys
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package versionextract
+
+import (
+	"github.com/lfreleng-actions/version-extract-action/internal/extractor"
+)
+
+// Result is a version found in a project file. It marshals to JSON with the
+// keys the command's JSON output uses.
+type Result struct {
+	Version      string `json:"version"`
+	ProjectType  string `json:"project_type"`
+	Subtype      string `json:"subtype,omitempty"`
+	File         string `json:"file"`
+	RelativePath string `json:"relative_path,omitempty"` // File relative to the search path (ExtractAll only)
+	MatchedBy    string `json:"matched_by"`
+
+	// VersionSource is "static", "static-constant", "static-property" or
+	// "dynamic-git-tag"
+	VersionSource string `json:"version_source,omitempty"`
+
+	// GitTag, GitDistance, GitCommit and GitDirty describe the tag a
+	// dynamic version was taken from: the tag, the commits made since it,
+	// the HEAD commit and whether the work tree has uncommitted changes
+	GitTag      string `json:"git_tag,omitempty"`
+	GitDistance int    `json:"git_distance,omitempty"`
+	GitCommit   string `json:"git_commit,omitempty"`
+	GitDirty    bool   `json:"git_dirty,omitempty"`
+
+	// CanonicalVersion and SemverVersion are set for PEP 440 versions found
+	// in Python manifests: the normalised form, e.g. 1.0rc1 for 1.0-RC1, and
+	// its Semantic Versioning equivalent, e.g. 1.0.0-rc.1, which is empty
+	// when the version has none
+	CanonicalVersion string `json:"canonical_version,omitempty"`
+	SemverVersion    string `json:"semver_version,omitempty"`
+
+	// Snapshot is set for Maven and Gradle snapshot versions, such as
+	// 1.2.3-SNAPSHOT
+	Snapshot bool `json:"snapshot,omitempty"`
+
+	// Fields is the version parsed into its components, or nil when the
+	// version is not in a recognised form
+	*Fields
+}
+
+// Fields are the components of a version
+type Fields struct {
+	Major      uint64 `json:"major"`
+	Minor      uint64 `json:"minor"`
+	Patch      uint64 `json:"patch"`
+	Prerelease string `json:"prerelease"` // Dot-separated identifiers, e.g. rc.1
+	Build      string `json:"build"`
+	Scheme     string `json:"scheme"` // "semver", "calver", "pep440", "maven" or "loose"
+}
+
+// newResult converts an extractor result
+func newResult(r *extractor.ExtractResult) *Result {
+	result := &Result{
+		Version:          r.Version,
+		ProjectType:      r.ProjectType,
+		Subtype:          r.Subtype,
+		File:             r.File,
+		RelativePath:     r.RelativePath,
+		MatchedBy:        r.MatchedBy,
+		VersionSource:    r.VersionSource,
+		GitTag:           r.GitTag,
+		GitDistance:      r.GitDistance,
+		GitCommit:        r.GitCommit,
+		GitDirty:         r.GitDirty,
+		CanonicalVersion: r.CanonicalVersion,
+		SemverVersion:    r.SemverVersion,
+		Snapshot:         r.Snapshot,
+	}
+	if f := r.Fields; f != nil {
+		result.Fields = &Fields{
+			Major:      f.Major,
+			Minor:      f.Minor,
+			Patch:      f.Patch,
+			Prerelease: f.Prerelease,
+			Build:      f.Build,
+			Scheme:     string(f.Scheme),
+		}
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+// Package versionextract extracts version strings from project files, as
+// the version-extract command does, for Go programs that would otherwise
+// run the binary and parse its JSON output.
+//
+//	result, err := versionextract.Extract(ctx, ".",
+//		versionextract.WithTagPolicy("highest-reachable"))
+//	if errors.Is(err, versionextract.ErrNoVersion) {
+//		// No supported project file holds a version
+//	}
+//
+// Options mirror the command's flags, and configuration is loaded as the
+// command loads it: the built-in default patterns, then the
+// .version-extract.yaml found from the search path, then any files given
+// with WithConfigFile.
+//
+// # Compatibility
+//
+// The package follows Semantic Versioning with the module's releases:
+// within a major version, exported identifiers are only ever added, never
+// removed or changed incompatibly. The exported API is recorded in
+// testdata/api.txt and checked by the package's tests, so a change to it
+// is always deliberate. The extraction engine lives in internal packages
+// and may change freely behind this API.
+package versionextract
+
+import (
+	"context"
+
+	"github.com/lfreleng-actions/version-extract-action/internal/extractor"
+)
+
+// ErrNoVersion is returned when no supported project file yields a version
+var ErrNoVersion = extractor.ErrNoVersion
+
+// Extract extracts the version of the project at path, a directory or a
+// project file. Project types are tried in priority order and the first
+// version found is returned, falling back to git tags for projects that
+// version dynamically. Extraction stops with ctx's error once ctx is done.
+func Extract(ctx context.Context, path string, opts ...Option) (*Result, error) {
+	ext, err := newExtractor(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ext.ExtractContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return newResult(result), nil
+}
+
+// ExtractAll extracts a version from every supported project file beneath
+// path, rather than stopping at the first, for monorepos holding several
+// components. Each file is reported once, by the highest-priority project
+// type that yields a version from it, with RelativePath set.
+func ExtractAll(ctx context.Context, path string, opts ...Option) ([]*Result, error) {
+	ext, err := newExtractor(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	found, err := ext.ExtractAllContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*Result, 0, len(found))
+	for _, result := range found {
+		results = append(results, newResult(result))
+	}
+	return results, nil
+}
+
+// newExtractor creates an extractor for path configured by the options
+func newExtractor(path string, opts []Option) (*extractor.VersionExtractor, error) {
+	o := newOptions(opts)
+
+	cfg := o.config
+	if cfg == nil {
+		var err error
+		if cfg, err = loadConfig(path, o); err != nil {
+			return nil, err
+		}
+	}
+
+	ext := extractor.NewWithOptions(cfg.config, o.dynamicFallback)
+	ext.SetLogger(o.logger)
+	ext.SetFS(o.fsys)
+	if o.skipDirectories != nil {
+		ext.SetSkipDirectories(o.skipDirectories)
+	}
+	if err := ext.SetGitVersionStyle(o.gitVersionStyle); err != nil {
+		return nil, err
+	}
+	if err := ext.SetTagPrefix(o.tagPrefix); err != nil {
+		return nil, err
+	}
+	if err := ext.SetTagPolicy(o.tagPolicy); err != nil {
+		return nil, err
+	}
+	ext.SetExcludePrerelease(o.excludePrerelease)
+	if err := ext.SetGitBackend(o.gitBackend); err != nil {
+		return nil, err
+	}
+	if err := ext.SetScheme(o.scheme); err != nil {
+		return nil, err
+	}
+	if err := ext.SetRevision(o.revision); err != nil {
+		return nil, err
+	}
+	for name, value := range o.gradleProperties {
+		if err := ext.SetGradleProperty(name, value); err != nil {
+			return nil, err
+		}
+	}
+	return ext, nil
+}
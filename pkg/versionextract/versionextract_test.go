@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2026 The Linux Foundation
+
+package versionextract_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lfreleng-actions/version-extract-action/pkg/versionextract"
+)
+
+// writeFile writes a file, creating its parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "package.json"), `{"name": "web", "version": "1.2.3-rc.1"}`)
+
+	result, err := versionextract.Extract(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "1.2.3-rc.1" || result.ProjectType != "JavaScript" {
+		t.Errorf("Expected JavaScript version 1.2.3-rc.1, got %s %s", result.ProjectType, result.Version)
+	}
+	if result.Fields == nil || result.Major != 1 || result.Prerelease != "rc.1" || result.Scheme != "semver" {
+		t.Errorf("Expected parsed fields, got %+v", result.Fields)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal result: %v", err)
+	}
+	for _, key := range []string{`"version":"1.2.3-rc.1"`, `"project_type":"JavaScript"`, `"major":1`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("Expected %s in %s", key, data)
+		}
+	}
+}
+
+func TestExtractAll(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "web", "package.json"), `{"name": "web", "version": "1.9.0"}`)
+	writeFile(t, filepath.Join(dir, "charts", "api", "Chart.yaml"), "apiVersion: v2\nname: api\nversion: 2.3.0\n")
+
+	results, err := versionextract.ExtractAll(context.Background(), dir, versionextract.WithDynamicFallback(false))
+	if err != nil {
+		t.Fatalf("ExtractAll failed: %v", err)
+	}
+	found := make(map[string]string)
+	for _, result := range results {
+		found[result.RelativePath] = result.Version
+	}
+	if found["web/package.json"] != "1.9.0" || found["charts/api/Chart.yaml"] != "2.3.0" {
+		t.Errorf("Expected both components, got %v", found)
+	}
+}
+
+func TestExtractNoVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "README.md"), "no version here\n")
+
+	_, err := versionextract.Extract(context.Background(), dir, versionextract.WithDynamicFallback(false))
+	if !errors.Is(err, versionextract.ErrNoVersion) {
+		t.Errorf("Expected ErrNoVersion, got %v", err)
+	}
+}
+
+func TestExtractCancelled(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "package.json"), `{"version": "1.0.0"}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := versionextract.Extract(ctx, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Extract to be cancelled, got %v", err)
+	}
+	if _, err := versionextract.ExtractAll(ctx, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ExtractAll to be cancelled, got %v", err)
+	}
+}
+
+func TestExtractInvalidOption(t *testing.T) {
+	_, err := versionextract.Extract(context.Background(), t.TempDir(),
+		versionextract.WithTagPolicy("newest"))
+	if err == nil || !strings.Contains(err.Error(), `unknown tag policy "newest"`) {
+		t.Errorf("Expected an unknown tag policy error, got %v", err)
+	}
+}
+
+func TestExtractGradleProperty(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "build.gradle"), "version = findProperty('releaseVersion')\n")
+	writeFile(t, filepath.Join(dir, "gradle.properties"), "releaseVersion=1.0.0\n")
+
+	result, err := versionextract.Extract(context.Background(), dir,
+		versionextract.WithGradleProperty("releaseVersion", "1.1.0"))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "1.1.0" {
+		t.Errorf("Expected the -P property 1.1.0, got %s from %s", result.Version, result.File)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	custom := filepath.Join(dir, "custom.yaml")
+	writeFile(t, custom, `replace: true
+projects:
+  - type: Custom
+    file: VERSION.txt
+    regex:
+      - '(\S+)'
+    samples:
+      - https://example.com/custom
+`)
+	writeFile(t, filepath.Join(dir, "VERSION.txt"), "4.5.6\n")
+	writeFile(t, filepath.Join(dir, "package.json"), `{"version": "1.0.0"}`)
+
+	cfg, err := versionextract.LoadConfig("", versionextract.WithConfigFile(custom))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if types := cfg.ProjectTypes(); len(types) != 1 || types[0] != "Custom" {
+		t.Errorf("Expected only the Custom project type, got %v", types)
+	}
+
+	result, err := versionextract.Extract(context.Background(), dir, versionextract.WithConfig(cfg))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "4.5.6" || result.ProjectType != "Custom" {
+		t.Errorf("Expected Custom version 4.5.6, got %s %s", result.ProjectType, result.Version)
+	}
+}
+
+// TestLocalConfigAddsType checks that a repository's own configuration can
+// add a project type without the sample URLs the built-in patterns carry
+func TestLocalConfigAddsType(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".version-extract.yaml"), `projects:
+  - type: Custom
+    file: VERSION.txt
+    regex:
+      - '^(\S+)$'
+    priority: 1
+`)
+	writeFile(t, filepath.Join(dir, "VERSION.txt"), "4.5.6\n")
+	writeFile(t, filepath.Join(dir, "package.json"), `{"version": "1.0.0"}`)
+
+	result, err := versionextract.Extract(context.Background(), dir, versionextract.WithStrictConfig(true))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "4.5.6" || result.ProjectType != "Custom" {
+		t.Errorf("Expected Custom version 4.5.6, got %s %s", result.ProjectType, result.Version)
+	}
+}
+
+func TestLoadConfigStrict(t *testing.T) {
+	custom := filepath.Join(t.TempDir(), "custom.yaml")
+	writeFile(t, custom, "projects:\n  - type: Custom\n    file: VERSION.txt\n    colour: blue\n")
+
+	_, err := versionextract.LoadConfig("", versionextract.WithConfigFile(custom),
+		versionextract.WithStrictConfig(true))
+	var invalid *versionextract.ConfigError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected a ConfigError, got %v", err)
+	}
+	if len(invalid.Problems) == 0 || invalid.Problems[0].String() != custom+`:4:5: unknown key "colour"` {
+		t.Errorf("Expected an unknown key problem, got %v", invalid.Problems)
+	}
+}
+
+func TestExtractFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		".version-extract.yaml": {Data: []byte(`replace: true
+projects:
+  - type: Custom
+    file: VERSION.txt
+    regex:
+      - '(\S+)'
+    samples:
+      - https://example.com/custom
+`)},
+		"VERSION.txt":  {Data: []byte("4.5.6\n")},
+		"package.json": {Data: []byte(`{"version": "1.0.0"}`)},
+	}
+
+	result, err := versionextract.Extract(context.Background(), ".", versionextract.WithFS(fsys))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "4.5.6" || result.File != "VERSION.txt" {
+		t.Errorf("Expected 4.5.6 from VERSION.txt with the archive's configuration, got %s from %s",
+			result.Version, result.File)
+	}
+}
+
+func TestExtractRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available, skipping git integration test")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %v: %v: %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	writeFile(t, filepath.Join(dir, "package.json"), `{"version": "1.0.0"}`)
+	git("add", ".")
+	git("commit", "-q", "-m", "one")
+	git("tag", "v1.0.0")
+	writeFile(t, filepath.Join(dir, "package.json"), `{"version": "2.0.0"}`)
+	git("commit", "-q", "-am", "two")
+
+	result, err := versionextract.Extract(context.Background(), dir, versionextract.WithRevision("v1.0.0"))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if result.Version != "1.0.0" {
+		t.Errorf("Expected 1.0.0 at v1.0.0, got %s", result.Version)
+	}
+}